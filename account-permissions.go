@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+)
+
+// s3PolicyActions - the S3 actions a wildcard or NotAction policy
+// element is expanded against.
+var s3PolicyActions = []string{
+	"s3:AbortMultipartUpload",
+	"s3:BypassGovernanceRetention",
+	"s3:CreateBucket",
+	"s3:DeleteBucket",
+	"s3:DeleteBucketPolicy",
+	"s3:DeleteObject",
+	"s3:DeleteObjectTagging",
+	"s3:DeleteObjectVersion",
+	"s3:DeleteObjectVersionTagging",
+	"s3:ForceDeleteBucket",
+	"s3:GetBucketEncryption",
+	"s3:GetBucketLocation",
+	"s3:GetBucketNotification",
+	"s3:GetBucketObjectLockConfiguration",
+	"s3:GetBucketPolicy",
+	"s3:GetBucketTagging",
+	"s3:GetBucketVersioning",
+	"s3:GetLifecycleConfiguration",
+	"s3:GetObject",
+	"s3:GetObjectLegalHold",
+	"s3:GetObjectRetention",
+	"s3:GetObjectTagging",
+	"s3:GetObjectVersion",
+	"s3:GetObjectVersionTagging",
+	"s3:GetReplicationConfiguration",
+	"s3:ListAllMyBuckets",
+	"s3:ListBucket",
+	"s3:ListBucketMultipartUploads",
+	"s3:ListBucketVersions",
+	"s3:ListMultipartUploadParts",
+	"s3:PutBucketEncryption",
+	"s3:PutBucketNotification",
+	"s3:PutBucketObjectLockConfiguration",
+	"s3:PutBucketPolicy",
+	"s3:PutBucketTagging",
+	"s3:PutBucketVersioning",
+	"s3:PutLifecycleConfiguration",
+	"s3:PutObject",
+	"s3:PutObjectLegalHold",
+	"s3:PutObjectRetention",
+	"s3:PutObjectTagging",
+	"s3:PutObjectVersionTagging",
+	"s3:PutReplicationConfiguration",
+	"s3:ReplicateDelete",
+	"s3:ReplicateObject",
+	"s3:ReplicateTags",
+	"s3:RestoreObject",
+}
+
+type accountPolicyStatement struct {
+	Effect      string          `json:"Effect"`
+	Action      json.RawMessage `json:"Action"`
+	NotAction   json.RawMessage `json:"NotAction"`
+	Resource    json.RawMessage `json:"Resource"`
+	NotResource json.RawMessage `json:"NotResource"`
+}
+
+// EffectivePermissions - returns for every bucket of the account the sorted
+// S3 actions its policy allows on that bucket. Deny statements remove
+// actions when their resource covers the whole bucket, denials scoped to a
+// prefix only are not subtracted. Conditions and NotResource statements are
+// ignored. Buckets without any allowed action are omitted, nil is returned
+// when the policy cannot be parsed.
+func (a AccountInfo) EffectivePermissions() map[string][]string {
+	var doc struct {
+		Statement []accountPolicyStatement `json:"Statement"`
+	}
+	if err := json.Unmarshal(a.Policy, &doc); err != nil {
+		return nil
+	}
+
+	perms := make(map[string][]string, len(a.Buckets))
+	for _, bucket := range a.Buckets {
+		allowed := make(map[string]struct{})
+		var denied []string
+		for _, st := range doc.Statement {
+			if st.NotResource != nil {
+				continue
+			}
+			resources, err := policyStringOrSlice(st.Resource)
+			if err != nil {
+				continue
+			}
+			actions := statementActions(st)
+			switch st.Effect {
+			case "Allow":
+				if policyResourcesMatch(resources, bucket.Name, false) {
+					for _, act := range actions {
+						allowed[act] = struct{}{}
+					}
+				}
+			case "Deny":
+				if policyResourcesMatch(resources, bucket.Name, true) {
+					denied = append(denied, actions...)
+				}
+			}
+		}
+		for _, act := range denied {
+			delete(allowed, act)
+		}
+		if len(allowed) == 0 {
+			continue
+		}
+		actions := make([]string, 0, len(allowed))
+		for act := range allowed {
+			actions = append(actions, act)
+		}
+		sort.Strings(actions)
+		perms[bucket.Name] = actions
+	}
+	return perms
+}
+
+// statementActions - expands the Action or NotAction element of a statement
+// into S3 action names. Actions without wildcards are kept as is even when
+// they are not part of s3PolicyActions.
+func statementActions(st accountPolicyStatement) []string {
+	if st.Action == nil && st.NotAction != nil {
+		excluded, err := policyStringOrSlice(st.NotAction)
+		if err != nil {
+			return nil
+		}
+		var actions []string
+		for _, act := range s3PolicyActions {
+			if !policyActionsMatch(excluded, act) {
+				actions = append(actions, act)
+			}
+		}
+		return actions
+	}
+
+	patterns, err := policyStringOrSlice(st.Action)
+	if err != nil {
+		return nil
+	}
+	var actions []string
+	for _, p := range patterns {
+		if !strings.ContainsAny(p, "*?") {
+			if strings.HasPrefix(p, "s3:") {
+				actions = append(actions, p)
+			}
+			continue
+		}
+		for _, act := range s3PolicyActions {
+			if policyActionsMatch([]string{p}, act) {
+				actions = append(actions, act)
+			}
+		}
+	}
+	return actions
+}
+
+func policyActionsMatch(patterns []string, action string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, action); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// policyResourcesMatch - reports whether any of the resources applies to
+// bucket. With whole set only resources covering every object of the bucket
+// match.
+func policyResourcesMatch(resources []string, bucket string, whole bool) bool {
+	for _, r := range resources {
+		r = strings.TrimPrefix(r, "arn:aws:s3:::")
+		if r == "*" {
+			return true
+		}
+		name, object := r, ""
+		if i := strings.IndexByte(r, '/'); i >= 0 {
+			name, object = r[:i], r[i+1:]
+		}
+		if ok, _ := path.Match(name, bucket); !ok {
+			continue
+		}
+		if !whole || object == "" || object == "*" {
+			return true
+		}
+	}
+	return false
+}
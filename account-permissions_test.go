@@ -0,0 +1,72 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccountInfoEffectivePermissions(t *testing.T) {
+	info := AccountInfo{
+		Policy: []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {"Effect": "Allow", "Action": ["s3:*"], "Resource": ["arn:aws:s3:::*"]},
+    {"Effect": "Deny", "Action": "s3:DeleteObject", "Resource": ["arn:aws:s3:::logs/*"]},
+    {"Effect": "Deny", "Action": "s3:PutObject", "Resource": ["arn:aws:s3:::data/private/*"]}
+  ]
+}`),
+		Buckets: []BucketAccessInfo{{Name: "logs"}, {Name: "data"}},
+	}
+
+	perms := info.EffectivePermissions()
+	if !reflect.DeepEqual(perms["data"], s3PolicyActions) {
+		t.Errorf("Expected all actions on data, got %v", perms["data"])
+	}
+	logs := perms["logs"]
+	if len(logs) != len(s3PolicyActions)-1 {
+		t.Errorf("Expected all actions but one on logs, got %v", logs)
+	}
+	for _, act := range logs {
+		if act == "s3:DeleteObject" {
+			t.Errorf("Expected s3:DeleteObject to be denied on logs")
+		}
+	}
+
+	info.Policy = []byte(`{"Statement":[{"Effect":"Allow","Action":"s3:Get*","Resource":"arn:aws:s3:::data/*"}]}`)
+	perms = info.EffectivePermissions()
+	if _, ok := perms["logs"]; ok {
+		t.Errorf("Expected no permissions on logs, got %v", perms["logs"])
+	}
+	for _, act := range perms["data"] {
+		if act[:6] != "s3:Get" {
+			t.Errorf("Unexpected action %s on data", act)
+		}
+	}
+	if len(perms["data"]) == 0 {
+		t.Error("Expected get actions on data")
+	}
+
+	info.Policy = []byte(`not json`)
+	if perms := info.EffectivePermissions(); perms != nil {
+		t.Errorf("Expected nil for an invalid policy, got %v", perms)
+	}
+}
@@ -23,10 +23,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -55,6 +57,54 @@ type ErrorResponse struct {
 	// Region where the bucket is located. This header is returned
 	// only in HEAD bucket and ListObjects response.
 	Region string
+
+	// StatusCode is the HTTP status of the response carrying the error.
+	StatusCode int `xml:"-" json:"-"`
+}
+
+// Errors an ErrorResponse can be matched against with errors.Is, based on
+// the error code and the HTTP status returned by the server.
+var (
+	// ErrAccessDenied is matched by authentication and authorization failures.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrNotFound is matched when the requested resource does not exist.
+	ErrNotFound = errors.New("resource not found")
+	// ErrConflict is matched when the resource already exists or is in use.
+	ErrConflict = errors.New("resource conflict")
+	// ErrServerNotInitialized is matched while the server is still starting.
+	ErrServerNotInitialized = errors.New("server not initialized")
+)
+
+// Is reports whether the error response belongs to the class of target,
+// one of ErrAccessDenied, ErrNotFound, ErrConflict, ErrServerNotInitialized
+// or ErrNotSupported.
+func (e ErrorResponse) Is(target error) bool {
+	switch target {
+	case ErrAccessDenied:
+		switch e.Code {
+		case "AccessDenied", "XMinioAdminAccessDenied", "InvalidAccessKeyId",
+			"SignatureDoesNotMatch", "ExpiredToken", "InvalidToken":
+			return true
+		}
+		return e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		if strings.Contains(e.Code, "NoSuch") || strings.HasSuffix(e.Code, "NotFound") {
+			return true
+		}
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		if strings.HasSuffix(e.Code, "AlreadyExists") || strings.HasSuffix(e.Code, "AlreadyOwnedByYou") ||
+			strings.HasSuffix(e.Code, "InUse") {
+			return true
+		}
+		return e.StatusCode == http.StatusConflict
+	case ErrServerNotInitialized:
+		return e.Code == "XMinioServerNotInitialized"
+	case ErrNotSupported:
+		return e.Code == "NotImplemented" || e.Code == "XMinioAdminNotImplemented" ||
+			e.StatusCode == http.StatusNotImplemented
+	}
+	return false
 }
 
 // Error - Returns HTTP error string
@@ -79,8 +129,9 @@ func httpRespToErrorResponse(resp *http.Response) error {
 	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 100<<10))
 	if err != nil {
 		return ErrorResponse{
-			Code:    resp.Status,
-			Message: fmt.Sprintf("Failed to read server response: %s.", err),
+			Code:       resp.Status,
+			Message:    fmt.Sprintf("Failed to read server response: %s.", err),
+			StatusCode: resp.StatusCode,
 		}
 	}
 
@@ -100,11 +151,13 @@ func httpRespToErrorResponse(resp *http.Response) error {
 				bodyString = bodyString[:1021] + "..."
 			}
 			return ErrorResponse{
-				Code:    resp.Status,
-				Message: fmt.Sprintf("Failed to parse server response (%s): %s", err.Error(), bodyString),
+				Code:       resp.Status,
+				Message:    fmt.Sprintf("Failed to parse server response (%s): %s", err.Error(), bodyString),
+				StatusCode: resp.StatusCode,
 			}
 		}
 	}
+	errResp.StatusCode = resp.StatusCode
 	return errResp
 }
 
@@ -138,3 +191,17 @@ func ErrInvalidArgument(message string) error {
 		RequestID: "minio",
 	}
 }
+
+// ErrNotSupported is returned when the server does not implement the
+// requested admin API.
+var ErrNotSupported = errors.New("operation not supported by the server")
+
+// isNotSupportedResponse returns true if the server does not know the
+// requested admin API, as older servers do for newer APIs.
+func isNotSupportedResponse(resp *http.Response, errResp ErrorResponse) bool {
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusNotImplemented:
+		return true
+	}
+	return errResp.Code == "NotImplemented"
+}
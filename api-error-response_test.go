@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrorResponseIs(t *testing.T) {
+	testCases := []struct {
+		status   int
+		body     string
+		expected error
+	}{
+		{http.StatusForbidden, `{"Code":"AccessDenied","Message":"Access Denied."}`, ErrAccessDenied},
+		{http.StatusForbidden, `{"Code":"InvalidAccessKeyId","Message":"invalid key"}`, ErrAccessDenied},
+		{http.StatusNotFound, `{"Code":"XMinioAdminNoSuchUser","Message":"The specified user does not exist."}`, ErrNotFound},
+		{http.StatusBadRequest, `{"Code":"XMinioAdminBucketQuotaConfigNotFound","Message":"no quota"}`, ErrNotFound},
+		{http.StatusConflict, `{"Code":"BucketAlreadyOwnedByYou","Message":"bucket exists"}`, ErrConflict},
+		{http.StatusBadRequest, `{"Code":"XMinioAdminPolicyInUse","Message":"policy in use"}`, ErrConflict},
+		{http.StatusServiceUnavailable, `{"Code":"XMinioServerNotInitialized","Message":"Server not initialized, please try again."}`, ErrServerNotInitialized},
+		{http.StatusNotImplemented, `{"Code":"NotImplemented","Message":"not implemented"}`, ErrNotSupported},
+		{http.StatusNotFound, `<html>not found</html>`, ErrNotFound},
+		{http.StatusBadRequest, `{"Code":"InvalidRequest","Message":"bad request"}`, nil},
+	}
+	classes := []error{ErrAccessDenied, ErrNotFound, ErrConflict, ErrServerNotInitialized, ErrNotSupported}
+
+	for i, tc := range testCases {
+		err := httpRespToErrorResponse(&http.Response{
+			StatusCode: tc.status,
+			Status:     http.StatusText(tc.status),
+			Body:       ioutil.NopCloser(strings.NewReader(tc.body)),
+		})
+		for _, class := range classes {
+			if got := errors.Is(err, class); got != (class == tc.expected) {
+				t.Errorf("Test %d: errors.Is(%v, %v) = %v", i+1, err, class, got)
+			}
+		}
+		var errResp ErrorResponse
+		if !errors.As(err, &errResp) || errResp.StatusCode != tc.status {
+			t.Errorf("Test %d: expected an ErrorResponse with status %d, got %#v", i+1, tc.status, err)
+		}
+	}
+}
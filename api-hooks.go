@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestInfo - describes an admin HTTP request, passed to the request hook
+type RequestInfo struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Time   time.Time
+}
+
+// ResponseInfo - describes the outcome of an admin HTTP request, passed to
+// the response hook. StatusCode is zero when the request failed with Err.
+type ResponseInfo struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Header     http.Header
+	Duration   time.Duration
+	Err        error
+}
+
+// redactedHeaders - request and response headers carrying secrets
+var redactedHeaders = []string{"Authorization", "X-Amz-Security-Token", "Cookie", "Set-Cookie"}
+
+// SetRequestHook - sets a function called before every HTTP request made
+// by the client, including retries. nil removes the hook.
+func (adm *AdminClient) SetRequestHook(hook func(RequestInfo)) {
+	adm.requestHook = hook
+}
+
+// SetResponseHook - sets a function called after every HTTP request made
+// by the client, including retries. nil removes the hook.
+func (adm *AdminClient) SetResponseHook(hook func(ResponseInfo)) {
+	adm.responseHook = hook
+}
+
+// SetHookRedaction - controls whether secrets such as the Authorization
+// header are redacted in the headers passed to the hooks, enabled by default.
+func (adm *AdminClient) SetHookRedaction(redact bool) {
+	adm.hookNoRedact = !redact
+}
+
+// hookHeader - returns a copy of h for the hooks.
+func (adm AdminClient) hookHeader(h http.Header) http.Header {
+	h = h.Clone()
+	if adm.hookNoRedact {
+		return h
+	}
+	for _, k := range redactedHeaders {
+		if _, ok := h[k]; ok {
+			h.Set(k, "**REDACTED**")
+		}
+	}
+	return h
+}
+
+// callRequestHook - calls the request hook, if any, for req.
+func (adm AdminClient) callRequestHook(req *http.Request, start time.Time) {
+	if adm.requestHook == nil {
+		return
+	}
+	adm.requestHook(RequestInfo{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Header: adm.hookHeader(req.Header),
+		Time:   start,
+	})
+}
+
+// callResponseHook - calls the response hook, if any, for the outcome of req.
+func (adm AdminClient) callResponseHook(req *http.Request, resp *http.Response, err error, start time.Time) {
+	if adm.responseHook == nil {
+		return
+	}
+	info := ResponseInfo{
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+		info.Header = adm.hookHeader(resp.Header)
+	}
+	adm.responseHook(info)
+}
@@ -0,0 +1,72 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRequestResponseHooks(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var reqs []RequestInfo
+	var resps []ResponseInfo
+	adm.SetRequestHook(func(info RequestInfo) { reqs = append(reqs, info) })
+	adm.SetResponseHook(func(info ResponseInfo) { resps = append(resps, info) })
+
+	if err := adm.SetUserStatus(context.Background(), "user", AccountEnabled); err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 || len(resps) != 1 {
+		t.Fatalf("Expected one request and one response, got %d and %d", len(reqs), len(resps))
+	}
+	req, resp := reqs[0], resps[0]
+	if req.Method != http.MethodPut || req.Path != libraryAdminURLPrefix+adminAPIPrefix+"/set-user-status" {
+		t.Errorf("Unexpected request %s %s", req.Method, req.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "**REDACTED**" {
+		t.Errorf("Expected a redacted Authorization header, got %q", got)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Err != nil {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if resp.Duration < 20*time.Millisecond {
+		t.Errorf("Expected a duration of at least 20ms, got %v", resp.Duration)
+	}
+	if got := resp.Header.Get("Set-Cookie"); got != "**REDACTED**" {
+		t.Errorf("Expected a redacted Set-Cookie header, got %q", got)
+	}
+
+	adm.SetHookRedaction(false)
+	reqs = nil
+	if err := adm.SetUserStatus(context.Background(), "user", AccountEnabled); err != nil {
+		t.Fatal(err)
+	}
+	if got := reqs[0].Header.Get("Authorization"); got == "**REDACTED**" || got == "" {
+		t.Errorf("Expected the Authorization header, got %q", got)
+	}
+}
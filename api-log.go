@@ -22,9 +22,14 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // LogMask is a bit mask for log types.
@@ -154,6 +159,282 @@ func (adm AdminClient) GetLogs(ctx context.Context, node string, lineCnt int, lo
 	return logCh
 }
 
+// LogLevel is the severity of a log entry, levels are ordered
+// from the least to the most severe.
+type LogLevel int
+
+const (
+	// LogLevelUnknown - the entry has no known level
+	LogLevelUnknown LogLevel = iota
+	LogLevelInfo
+	LogLevelEvent
+	LogLevelWarning
+	LogLevelError
+	LogLevelFatal
+)
+
+// ParseLogLevel returns the level of a log level or log kind name,
+// LogLevelUnknown if it is not known.
+func ParseLogLevel(s string) LogLevel {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "INFO":
+		return LogLevelInfo
+	case "EVENT":
+		return LogLevelEvent
+	case "WARNING", "WARN":
+		return LogLevelWarning
+	case "ERROR":
+		return LogLevelError
+	case "FATAL":
+		return LogLevelFatal
+	}
+	return LogLevelUnknown
+}
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelEvent:
+		return "EVENT"
+	case LogLevelWarning:
+		return "WARNING"
+	case LogLevelError:
+		return "ERROR"
+	case LogLevelFatal:
+		return "FATAL"
+	}
+	return "UNKNOWN"
+}
+
+// logKind - returns the log kind the server filters on for entries of at
+// least level l, only the most severe level has a kind of its own.
+func (l LogLevel) logKind() LogKind {
+	if l == LogLevelFatal {
+		return LogKindFatal
+	}
+	return LogKindAll
+}
+
+// Level returns the level of the entry, from its level or its kind.
+func (l LogInfo) Level() LogLevel {
+	if level := ParseLogLevel(l.logEntry.Level); level != LogLevelUnknown {
+		return level
+	}
+	return ParseLogLevel(string(l.LogKind))
+}
+
+// IsError returns true for error and fatal entries.
+func (l LogInfo) IsError() bool {
+	return l.Level() >= LogLevelError
+}
+
+// matchesLevel - entries of an unknown level always match so they are not
+// dropped silently, errors of the stream as well.
+func (l LogInfo) matchesLevel(min LogLevel) bool {
+	if l.Err != nil || min == LogLevelUnknown {
+		return true
+	}
+	level := l.Level()
+	return level == LogLevelUnknown || level >= min
+}
+
+// GetLogsByLevel - listen on console log messages of at least minLevel,
+// entries of an unknown level are sent as well. The entries are filtered
+// by the server when it supports it and by the client otherwise.
+func (adm AdminClient) GetLogsByLevel(ctx context.Context, node string, lineCnt int, minLevel LogLevel) <-chan LogInfo {
+	inCh := adm.GetLogs(ctx, node, lineCnt, string(minLevel.logKind()))
+	logCh := make(chan LogInfo, 1)
+	go func() {
+		defer close(logCh)
+		for info := range inCh {
+			if !info.matchesLevel(minLevel) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case logCh <- info:
+			}
+		}
+	}()
+	return logCh
+}
+
+// LogOpts - options of FollowLogs
+type LogOpts struct {
+	// Limit is the number of past log entries sent before new ones.
+	Limit int
+	// Kind of the log entries, defaults to all.
+	Kind LogKind
+	// MinLevel drops the entries of a lower level, entries
+	// of an unknown level are always sent.
+	MinLevel LogLevel
+}
+
+// ErrLogStreamReconnecting is the error of the LogInfo sent by FollowLogs
+// when the log stream was interrupted, it wraps the cause of the
+// interruption. Following entries are sent once the stream is back.
+var ErrLogStreamReconnecting = errors.New("log stream interrupted, reconnecting")
+
+// Backoff limits between two FollowLogs reconnection attempts.
+var (
+	logFollowMinBackoff = time.Second
+	logFollowMaxBackoff = 30 * time.Second
+)
+
+// FollowLogs - follows the console log messages of node, or of all nodes
+// when node is empty. The stream is re-established with an exponential
+// backoff when it is interrupted, a LogInfo with an error wrapping
+// ErrLogStreamReconnecting is sent for each interruption. Entries sent
+// again by the server after a reconnection are skipped. The channel is
+// closed only when ctx is canceled.
+func (adm *AdminClient) FollowLogs(ctx context.Context, node string, opts LogOpts) (<-chan LogInfo, error) {
+	if opts.Limit < 0 {
+		return nil, ErrInvalidArgument("log limit cannot be negative")
+	}
+	if opts.Kind == "" {
+		opts.Kind = opts.MinLevel.logKind()
+	}
+	urlValues := make(url.Values)
+	urlValues.Set("node", node)
+	urlValues.Set("limit", strconv.Itoa(opts.Limit))
+	urlValues.Set("logType", string(opts.Kind))
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/log",
+		queryValues: urlValues,
+	}
+
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	logCh := make(chan LogInfo)
+	go func() {
+		defer close(logCh)
+
+		send := func(info LogInfo) bool {
+			select {
+			case logCh <- info:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var dedup logDeduplicator
+		backoff := logFollowMinBackoff
+		for {
+			var received bool
+			err := decodeLogs(resp, func(info LogInfo) bool {
+				received = true
+				if !dedup.isNew(info) || !info.matchesLevel(opts.MinLevel) {
+					return true
+				}
+				return send(info)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if received {
+				backoff = logFollowMinBackoff
+			}
+
+			for {
+				if err == nil {
+					err = errors.New("log stream closed by the server")
+				}
+				if !send(LogInfo{NodeName: node, Err: fmt.Errorf("%w: %v", ErrLogStreamReconnecting, err)}) {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+				if backoff *= 2; backoff > logFollowMaxBackoff {
+					backoff = logFollowMaxBackoff
+				}
+
+				resp, err = adm.executeMethod(ctx, http.MethodGet, reqData)
+				if err != nil {
+					continue
+				}
+				if resp.StatusCode != http.StatusOK {
+					err = httpRespToErrorResponse(resp)
+					continue
+				}
+				break
+			}
+		}
+	}()
+	return logCh, nil
+}
+
+// decodeLogs - sends all log entries of resp to fn until fn returns false
+// or the stream ends, the response is closed on return.
+func decodeLogs(resp *http.Response, fn func(LogInfo) bool) error {
+	defer closeResponse(resp)
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var info LogInfo
+		if err := dec.Decode(&info); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if !fn(info) {
+			return nil
+		}
+	}
+}
+
+// logDeduplicator - detects log entries already seen, based on the latest
+// entry time of every node and the entries seen at that time.
+type logDeduplicator struct {
+	nodes map[string]*logNodeState
+}
+
+type logNodeState struct {
+	last time.Time
+	seen map[string]struct{}
+}
+
+func (d *logDeduplicator) isNew(info LogInfo) bool {
+	t, err := time.Parse(time.RFC3339Nano, info.Time)
+	if err != nil {
+		// Entries without a valid time cannot be ordered.
+		return true
+	}
+	if d.nodes == nil {
+		d.nodes = make(map[string]*logNodeState)
+	}
+	n := d.nodes[info.NodeName]
+	if n == nil {
+		n = &logNodeState{}
+		d.nodes[info.NodeName] = n
+	}
+	key := info.ConsoleMsg + "\x00" + info.Message + "\x00" + info.RequestID
+	switch {
+	case t.Before(n.last):
+		return false
+	case t.Equal(n.last):
+		if _, ok := n.seen[key]; ok {
+			return false
+		}
+	default:
+		n.last = t
+		n.seen = make(map[string]struct{})
+	}
+	n.seen[key] = struct{}{}
+	return true
+}
+
 // Mask returns the mask based on the error level.
 func (l LogInfo) Mask() uint64 {
 	return l.LogKind.LogMask().Mask()
@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFollowLogs(t *testing.T) {
+	defer func(min, max time.Duration) {
+		logFollowMinBackoff, logFollowMaxBackoff = min, max
+	}(logFollowMinBackoff, logFollowMaxBackoff)
+	logFollowMinBackoff, logFollowMaxBackoff = time.Millisecond, 4*time.Millisecond
+
+	now := time.Now().UTC()
+	entry := func(msg string, t time.Time) LogInfo {
+		info := LogInfo{ConsoleMsg: msg, NodeName: "node1"}
+		info.Time = t.Format(time.RFC3339Nano)
+		return info
+	}
+	e1, e2, e3 := entry("one", now), entry("two", now), entry("three", now.Add(time.Second))
+
+	var requests int
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		enc := json.NewEncoder(w)
+		switch requests {
+		case 1:
+			enc.Encode(e1)
+			enc.Encode(e2)
+		case 2:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			// Past entries are sent again after reconnecting.
+			enc.Encode(e1)
+			enc.Encode(e2)
+			enc.Encode(e3)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := adm.FollowLogs(ctx, "node1", LogOpts{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []string
+	var reconnects int
+	for info := range ch {
+		if info.Err != nil {
+			if !errors.Is(info.Err, ErrLogStreamReconnecting) {
+				t.Fatalf("Unexpected error %v", info.Err)
+			}
+			reconnects++
+			continue
+		}
+		msgs = append(msgs, info.ConsoleMsg)
+		if len(msgs) == 3 {
+			cancel()
+		}
+	}
+	if len(msgs) != 3 || msgs[0] != "one" || msgs[1] != "two" || msgs[2] != "three" {
+		t.Errorf("Unexpected messages %v", msgs)
+	}
+	if reconnects != 2 {
+		t.Errorf("Expected 2 reconnect markers, got %d", reconnects)
+	}
+}
+
+func TestLogInfoLevel(t *testing.T) {
+	entry := func(level string, kind LogKind) LogInfo {
+		var info LogInfo
+		info.logEntry.Level = level
+		info.LogKind = kind
+		return info
+	}
+	tests := []struct {
+		info  LogInfo
+		level LogLevel
+		isErr bool
+	}{
+		{entry("ERROR", LogKindError), LogLevelError, true},
+		{entry("", LogKindFatal), LogLevelFatal, true},
+		{entry("warning", ""), LogLevelWarning, false},
+		{entry("INFO", LogKindInfo), LogLevelInfo, false},
+		{entry("TRACE", LogKindMinio), LogLevelUnknown, false},
+	}
+	for i, test := range tests {
+		if level := test.info.Level(); level != test.level {
+			t.Errorf("Test %d: expected level %s, got %s", i, test.level, level)
+		}
+		if test.info.IsError() != test.isErr {
+			t.Errorf("Test %d: expected IsError %v", i, test.isErr)
+		}
+	}
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if kind := r.URL.Query().Get("logType"); kind != string(LogKindAll) {
+			t.Errorf("Unexpected log type %q", kind)
+		}
+		enc := json.NewEncoder(w)
+		for _, test := range tests {
+			info := test.info
+			info.ConsoleMsg = test.level.String()
+			enc.Encode(info)
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []string
+	for info := range adm.GetLogsByLevel(ctx, "", 0, LogLevelError) {
+		got = append(got, info.ConsoleMsg)
+		if len(got) == 3 {
+			// GetLogs reconnects once the stream ends.
+			cancel()
+		}
+	}
+	if len(got) < 3 || got[0] != "ERROR" || got[1] != "FATAL" || got[2] != "UNKNOWN" {
+		t.Errorf("Unexpected entries %v", got)
+	}
+}
@@ -73,6 +73,14 @@ type AdminClient struct {
 	// Advanced functionality.
 	isTraceEnabled bool
 	traceOutput    io.Writer
+
+	// Overrides the default retry behavior when set.
+	retryPolicy *RetryPolicy
+
+	// Hooks called for every request, see SetRequestHook.
+	requestHook  func(RequestInfo)
+	responseHook func(ResponseInfo)
+	hookNoRedact bool
 }
 
 // Global constants.
@@ -93,11 +101,40 @@ const (
 	libraryUserAgent       = libraryUserAgentPrefix + libraryName + "/" + libraryVersion
 )
 
+// CredentialsProvider - provides the credentials used to sign requests.
+// Retrieve is called again whenever IsExpired reports true, so providers
+// for STS or rotating credentials are picked up by the next request.
+type CredentialsProvider = credentials.Provider
+
+// NewStaticCredentialsProvider - returns a provider of fixed credentials,
+// as used by New.
+func NewStaticCredentialsProvider(accessKeyID, secretAccessKey, sessionToken string) CredentialsProvider {
+	return &credentials.Static{
+		Value: credentials.Value{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    sessionToken,
+			SignerType:      credentials.SignatureV4,
+		},
+	}
+}
+
 // Options for New method
 type Options struct {
-	Creds     *credentials.Credentials
-	Secure    bool
+	Creds *credentials.Credentials
+	// CredsProvider is used to obtain the credentials when Creds is not set.
+	CredsProvider CredentialsProvider
+	// Secure selects https. With a TLS configuration of its own, see
+	// TransportOptions.OwnTLS, the transport still needs Secure to be set
+	// for the client to talk https.
+	Secure bool
+	// Transport is used as is when set, TransportOptions is then ignored.
 	Transport http.RoundTripper
+	// TransportOptions tunes the transport built by the client.
+	TransportOptions *TransportOptions
+	// RetryPolicy replaces the default retry behavior, under which every
+	// request is retried, when set.
+	RetryPolicy *RetryPolicy
 	// Add future fields here
 }
 
@@ -140,6 +177,9 @@ func privateNew(endpoint string, opts *Options) (*AdminClient, error) {
 
 	// Save the credentials.
 	clnt.credsProvider = opts.Creds
+	if clnt.credsProvider == nil && opts.CredsProvider != nil {
+		clnt.credsProvider = credentials.New(opts.CredsProvider)
+	}
 
 	// Remember whether we are using https or not
 	clnt.secure = opts.Secure
@@ -147,9 +187,11 @@ func privateNew(endpoint string, opts *Options) (*AdminClient, error) {
 	// Save endpoint URL, user agent for future uses.
 	clnt.endpointURL = endpointURL
 
+	clnt.retryPolicy = opts.RetryPolicy
+
 	tr := opts.Transport
 	if tr == nil {
-		tr = DefaultTransport(opts.Secure)
+		tr = newTransport(opts.Secure, opts.TransportOptions)
 	}
 
 	// Instantiate http client and bucket location cache.
@@ -197,6 +239,18 @@ func (adm *AdminClient) SetCustomTransport(customHTTPTransport http.RoundTripper
 	}
 }
 
+// SetCredentialsProvider - replaces the credentials used to sign requests
+// with the credentials obtained from p.
+func (adm *AdminClient) SetCredentialsProvider(p CredentialsProvider) {
+	adm.credsProvider = credentials.New(p)
+}
+
+// SetRetryPolicy - set the policy used to retry failed requests, nil
+// restores the default behavior.
+func (adm *AdminClient) SetRetryPolicy(p *RetryPolicy) {
+	adm.retryPolicy = p
+}
+
 // TraceOn - enable HTTP tracing.
 func (adm *AdminClient) TraceOn(outputStream io.Writer) {
 	// if outputStream is nil then default to os.Stdout.
@@ -228,6 +282,8 @@ type requestData struct {
 	endpointOverride *url.URL
 	// isKMS replaces URL prefix with /kms
 	isKMS bool
+	// isS3 drops the URL prefix, for S3 API requests
+	isS3 bool
 }
 
 // Filter out signature value from Authorization header.
@@ -315,7 +371,10 @@ func (adm AdminClient) dumpHTTP(req *http.Request, resp *http.Response) error {
 
 // do - execute http request.
 func (adm AdminClient) do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	adm.callRequestHook(req, start)
 	resp, err := adm.httpClient.Do(req)
+	adm.callResponseHook(req, resp, err, start)
 	if err != nil {
 		// Handle this specifically for now until future Golang versions fix this issue properly.
 		if urlErr, ok := err.(*url.Error); ok {
@@ -374,9 +433,9 @@ func (adm AdminClient) ExecuteMethod(ctx context.Context, method string, reqData
 
 // executeMethod - instantiates a given method, and retries the
 // request upon any error up to maxRetries attempts in a binomially
-// delayed manner using a standard back off algorithm.
+// delayed manner using a standard back off algorithm. When a retry
+// policy is set, see Options.RetryPolicy, the policy is used instead.
 func (adm AdminClient) executeMethod(ctx context.Context, method string, reqData requestData) (res *http.Response, err error) {
-	reqRetry := MaxRetry // Indicates how many times we can retry the request
 	defer func() {
 		if err != nil {
 			// close idle connections before returning, upon error.
@@ -384,6 +443,12 @@ func (adm AdminClient) executeMethod(ctx context.Context, method string, reqData
 		}
 	}()
 
+	if adm.retryPolicy != nil {
+		return adm.executeMethodWithPolicy(ctx, method, reqData, *adm.retryPolicy)
+	}
+
+	reqRetry := MaxRetry // Indicates how many times we can retry the request
+
 	// Create cancel context to control 'newRetryTimer' go routine.
 	retryCtx, cancel := context.WithCancel(ctx)
 
@@ -391,72 +456,100 @@ func (adm AdminClient) executeMethod(ctx context.Context, method string, reqData
 	defer cancel()
 
 	for range adm.newRetryTimer(retryCtx, reqRetry, DefaultRetryUnit, DefaultRetryCap, MaxJitter) {
-		// Instantiate a new request.
-		var req *http.Request
-		req, err = adm.newRequest(ctx, method, reqData)
-		if err != nil {
-			return nil, err
+		var retry bool
+		res, retry, err = adm.executeOnce(ctx, method, reqData)
+		if !retry {
+			return res, err
 		}
+	}
 
-		// Initiate the request.
-		res, err = adm.do(req)
-		if err != nil {
-			// Give up right away if it is a connection refused problem
-			if errors.Is(err, syscall.ECONNREFUSED) {
-				return nil, err
-			}
-			if err == context.Canceled || err == context.DeadlineExceeded {
-				return nil, err
-			}
-			// retry all network errors.
-			continue
-		}
+	// Return an error when retry is canceled or deadlined
+	if e := retryCtx.Err(); e != nil {
+		return nil, e
+	}
+
+	return res, err
+}
 
-		// For any known successful http status, return quickly.
-		for _, httpStatus := range successStatus {
-			if httpStatus == res.StatusCode {
-				return res, nil
+// executeMethodWithPolicy - same as executeMethod but retries according to
+// the retry policy p. Only idempotent requests, or requests whose context
+// was marked with WithRetry, are retried.
+func (adm AdminClient) executeMethodWithPolicy(ctx context.Context, method string, reqData requestData, p RetryPolicy) (res *http.Response, err error) {
+	maxRetry := p.maxRetry()
+	if !isIdempotentMethod(method) && !retryRequested(ctx) {
+		maxRetry = 1
+	}
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		res, retry, err = adm.executeOnce(ctx, method, reqData)
+		if !retry || attempt+1 >= maxRetry {
+			return res, err
+		}
+		wait := p.backoff(adm.random, attempt)
+		if res != nil {
+			if d, ok := parseRetryAfter(res.Header.Get("Retry-After"), nowFunc()); ok {
+				wait = p.capWait(d)
 			}
 		}
-
-		// Read the body to be saved later.
-		errBodyBytes, err := ioutil.ReadAll(res.Body)
-		// res.Body should be closed
-		closeResponse(res)
-		if err != nil {
+		if err = p.sleep(ctx, wait); err != nil {
 			return nil, err
 		}
+	}
+}
 
-		// Save the body.
-		errBodySeeker := bytes.NewReader(errBodyBytes)
-		res.Body = ioutil.NopCloser(errBodySeeker)
-
-		// For errors verify if its retryable otherwise fail quickly.
-		errResponse := ToErrorResponse(httpRespToErrorResponse(res))
-
-		// Save the body back again.
-		errBodySeeker.Seek(0, 0) // Seek back to starting point.
-		res.Body = ioutil.NopCloser(errBodySeeker)
+// executeOnce - performs a single attempt of the request. retry is true
+// when the request failed in a way that is worth retrying, the returned
+// response then holds the error response of the server, if any.
+func (adm AdminClient) executeOnce(ctx context.Context, method string, reqData requestData) (res *http.Response, retry bool, err error) {
+	// Instantiate a new request.
+	req, err := adm.newRequest(ctx, method, reqData)
+	if err != nil {
+		return nil, false, err
+	}
 
-		// Verify if error response code is retryable.
-		if isAdminErrCodeRetryable(errResponse.Code) {
-			continue // Retry.
+	// Initiate the request.
+	res, err = adm.do(req)
+	if err != nil {
+		// Give up right away if it is a connection refused problem
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, false, err
 		}
-
-		// Verify if http status code is retryable.
-		if isHTTPStatusRetryable(res.StatusCode) {
-			continue // Retry.
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return nil, false, err
 		}
+		// retry all network errors.
+		return nil, true, err
+	}
 
-		break
+	// For any known successful http status, return quickly.
+	for _, httpStatus := range successStatus {
+		if httpStatus == res.StatusCode {
+			return res, false, nil
+		}
 	}
 
-	// Return an error when retry is canceled or deadlined
-	if e := retryCtx.Err(); e != nil {
-		return nil, e
+	// Read the body to be saved later.
+	errBodyBytes, err := ioutil.ReadAll(res.Body)
+	// res.Body should be closed
+	closeResponse(res)
+	if err != nil {
+		return nil, false, err
 	}
 
-	return res, err
+	// Save the body.
+	errBodySeeker := bytes.NewReader(errBodyBytes)
+	res.Body = ioutil.NopCloser(errBodySeeker)
+
+	// For errors verify if its retryable otherwise fail quickly.
+	errResponse := ToErrorResponse(httpRespToErrorResponse(res))
+
+	// Save the body back again.
+	errBodySeeker.Seek(0, 0) // Seek back to starting point.
+	res.Body = ioutil.NopCloser(errBodySeeker)
+
+	// Verify if error response code or http status code is retryable.
+	retry = isAdminErrCodeRetryable(errResponse.Code) || isHTTPStatusRetryable(res.StatusCode)
+	return res, retry, nil
 }
 
 // set User agent.
@@ -551,6 +644,9 @@ func (adm AdminClient) makeTargetURL(r requestData) (*url.URL, error) {
 	if r.isKMS {
 		prefix = libraryKMSURLPrefix
 	}
+	if r.isS3 {
+		prefix = ""
+	}
 	urlStr := scheme + "://" + host + prefix + r.relPath
 
 	// If there are any query values, add them to the end.
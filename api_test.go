@@ -21,9 +21,14 @@
 package madmin_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
 func TestMinioAdminClient(t *testing.T) {
@@ -32,3 +37,67 @@ func TestMinioAdminClient(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// rotatingProvider - hands out the next key set every time it is expired.
+type rotatingProvider struct {
+	keys    []credentials.Value
+	next    int
+	expired bool
+}
+
+func (p *rotatingProvider) Retrieve() (credentials.Value, error) {
+	v := p.keys[p.next%len(p.keys)]
+	p.next++
+	p.expired = false
+	return v, nil
+}
+
+func (p *rotatingProvider) IsExpired() bool {
+	return p.expired
+}
+
+func TestCredentialsProvider(t *testing.T) {
+	var accessKeys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if i := strings.Index(auth, "Credential="); i >= 0 {
+			accessKeys = append(accessKeys, strings.SplitN(auth[i+len("Credential="):], "/", 2)[0])
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p := &rotatingProvider{keys: []credentials.Value{
+		{AccessKeyID: "FIRSTKEY", SecretAccessKey: "firstsecret"},
+		{AccessKeyID: "SECONDKEY", SecretAccessKey: "secondsecret"},
+	}}
+	adm, err := madmin.NewWithOptions(strings.TrimPrefix(srv.URL, "http://"), &madmin.Options{CredsProvider: p})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := func() {
+		resp, err := adm.ExecuteMethod(context.Background(), http.MethodGet, madmin.RequestData{RelPath: "/v3/info"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	call()
+	call()
+	p.expired = true
+	call()
+	p.expired = true
+	call()
+
+	expected := []string{"FIRSTKEY", "FIRSTKEY", "SECONDKEY", "FIRSTKEY"}
+	if strings.Join(accessKeys, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected requests signed with %v, got %v", expected, accessKeys)
+	}
+
+	adm.SetCredentialsProvider(madmin.NewStaticCredentialsProvider("STATICKEY", "staticsecret", ""))
+	call()
+	if last := accessKeys[len(accessKeys)-1]; last != "STATICKEY" {
+		t.Errorf("Expected request signed with STATICKEY, got %s", last)
+	}
+}
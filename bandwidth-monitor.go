@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"sync"
+	"time"
+)
+
+type bandwidthSample struct {
+	at    time.Time
+	value float64
+}
+
+// BandwidthMonitor - maintains a rolling average of the bandwidth of every
+// bucket over a window of time, from the reports of GetBucketBandwidth.
+type BandwidthMonitor struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	samples map[string][]bandwidthSample
+	err     error
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func newBandwidthMonitor(window time.Duration) *BandwidthMonitor {
+	return &BandwidthMonitor{
+		window:  window,
+		samples: make(map[string][]bandwidthSample),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// NewBandwidthMonitor - starts consuming the reports of reportCh, as
+// returned by GetBucketBandwidth, until it is closed or Stop is called.
+// Samples older than window are discarded.
+func NewBandwidthMonitor(reportCh <-chan Report, window time.Duration) *BandwidthMonitor {
+	m := newBandwidthMonitor(window)
+	go m.run(reportCh)
+	return m
+}
+
+func (m *BandwidthMonitor) run(reportCh <-chan Report) {
+	defer close(m.doneCh)
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case r, ok := <-reportCh:
+			if !ok {
+				return
+			}
+			if r.Err != nil {
+				m.mu.Lock()
+				m.err = r.Err
+				m.mu.Unlock()
+				continue
+			}
+			m.add(r.Report, nowFunc())
+		}
+	}
+}
+
+func (m *BandwidthMonitor) add(report BucketBandwidthReport, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for bucket, details := range report.BucketStats {
+		m.samples[bucket] = append(m.samples[bucket], bandwidthSample{
+			at:    at,
+			value: details.CurrentBandwidthInBytesPerSecond,
+		})
+	}
+	m.expire(at)
+}
+
+// expire - drops the samples which are out of the window at now.
+// Must be called with the lock held.
+func (m *BandwidthMonitor) expire(now time.Time) {
+	cutoff := now.Add(-m.window)
+	for bucket, samples := range m.samples {
+		i := 0
+		for i < len(samples) && !samples[i].at.After(cutoff) {
+			i++
+		}
+		if i == len(samples) {
+			delete(m.samples, bucket)
+			continue
+		}
+		m.samples[bucket] = samples[i:]
+	}
+}
+
+// Current - returns the average bandwidth of bucket in bytes per second
+// over the window, ok is false when no sample of bucket is in the window.
+func (m *BandwidthMonitor) Current(bucket string) (aggregatedBytesPerSec float64, ok bool) {
+	return m.current(bucket, nowFunc())
+}
+
+func (m *BandwidthMonitor) current(bucket string, now time.Time) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expire(now)
+	samples := m.samples[bucket]
+	if len(samples) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, s := range samples {
+		sum += s.value
+	}
+	return sum / float64(len(samples)), true
+}
+
+// Err - returns the last error reported on the report channel.
+func (m *BandwidthMonitor) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// Stop - stops consuming reports, the averages remain available.
+func (m *BandwidthMonitor) Stop() {
+	m.stopOnce.Do(func() { close(m.stopCh) })
+	<-m.doneCh
+}
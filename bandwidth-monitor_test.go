@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBandwidthMonitorRollingAverage(t *testing.T) {
+	m := newBandwidthMonitor(10 * time.Second)
+	start := time.Now()
+	report := func(v float64) BucketBandwidthReport {
+		return BucketBandwidthReport{BucketStats: map[string]BandwidthDetails{
+			"bucket": {CurrentBandwidthInBytesPerSecond: v},
+		}}
+	}
+
+	// Spiky samples around 100, one per second.
+	for i := 0; i < 10; i++ {
+		v := 50.0
+		if i%2 == 1 {
+			v = 150
+		}
+		m.add(report(v), start.Add(time.Duration(i)*time.Second))
+	}
+	now := start.Add(9 * time.Second)
+	if avg, ok := m.current("bucket", now); !ok || avg != 100 {
+		t.Errorf("Expected average 100, got %v, %v", avg, ok)
+	}
+
+	// The rate settles at 200, the old samples leave the window.
+	var avg float64
+	for i := 10; i < 30; i++ {
+		now = start.Add(time.Duration(i) * time.Second)
+		m.add(report(200), now)
+		got, ok := m.current("bucket", now)
+		if !ok {
+			t.Fatal("Expected a bucket average")
+		}
+		if i > 10 && got < avg {
+			t.Errorf("Expected the average to increase, got %v after %v", got, avg)
+		}
+		avg = got
+	}
+	if math.Abs(avg-200) > 1e-9 {
+		t.Errorf("Expected the average to converge to 200, got %v", avg)
+	}
+
+	if _, ok := m.current("bucket", now.Add(11*time.Second)); ok {
+		t.Error("Expected stale samples to be discarded")
+	}
+	if _, ok := m.current("other", now); ok {
+		t.Error("Expected no average for an unknown bucket")
+	}
+}
+
+func TestBandwidthMonitor(t *testing.T) {
+	ch := make(chan Report)
+	m := NewBandwidthMonitor(ch, time.Minute)
+	ch <- Report{Report: BucketBandwidthReport{BucketStats: map[string]BandwidthDetails{
+		"bucket": {CurrentBandwidthInBytesPerSecond: 42},
+	}}}
+	ch <- Report{Err: errors.New("stream closed")}
+	close(ch)
+	m.Stop()
+
+	if avg, ok := m.Current("bucket"); !ok || avg != 42 {
+		t.Errorf("Expected average 42, got %v, %v", avg, ok)
+	}
+	if m.Err() == nil {
+		t.Error("Expected the report error")
+	}
+}
@@ -0,0 +1,524 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBatchJob is returned when a batch job builder is missing
+// required fields or has invalid values.
+var ErrInvalidBatchJob = errors.New("invalid batch job")
+
+// BatchJobBuilder renders a typed batch job definition into the YAML
+// accepted by StartBatchJob.
+type BatchJobBuilder interface {
+	Type() BatchJobType
+	YAML() (string, error)
+}
+
+// StartBatchJobFromBuilder validates and renders the job definition of the
+// builder and starts it as a new batch job.
+func (adm *AdminClient) StartBatchJobFromBuilder(ctx context.Context, b BatchJobBuilder) (BatchJobResult, error) {
+	if b == nil {
+		return BatchJobResult{}, ErrInvalidArgument("batch job builder cannot be nil")
+	}
+	job, err := b.YAML()
+	if err != nil {
+		return BatchJobResult{}, err
+	}
+	return adm.StartBatchJob(ctx, job)
+}
+
+// BatchJobKV is a key/value pair used by batch job tag and metadata filters.
+// The value may contain wildcards.
+type BatchJobKV struct {
+	Key   string
+	Value string
+}
+
+// BatchJobFilter is the object filtering criteria of replicate and
+// keyrotate batch jobs. Zero values are not rendered.
+type BatchJobFilter struct {
+	NewerThan     time.Duration
+	OlderThan     time.Duration
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Tags          []BatchJobKV
+	Metadata      []BatchJobKV
+	// KMSKey matches objects encrypted with this KMS key, keyrotate only.
+	KMSKey string
+}
+
+// BatchJobReplicateEndpoint is the source or target of a replicate batch
+// job. Endpoint and credentials are left empty for the local deployment.
+type BatchJobReplicateEndpoint struct {
+	Type         string // "s3" or "minio"
+	Bucket       string
+	Prefix       string
+	Endpoint     string
+	Path         string // "on", "off" or "auto"
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+func (e BatchJobReplicateEndpoint) isLocal() bool {
+	return e.Endpoint == ""
+}
+
+func (e BatchJobReplicateEndpoint) validate(name string) error {
+	if e.Bucket == "" {
+		return fmt.Errorf("%w: %s bucket cannot be empty", ErrInvalidBatchJob, name)
+	}
+	switch e.Type {
+	case "", "s3", "minio":
+	default:
+		return fmt.Errorf("%w: %s type must be s3 or minio, got %q", ErrInvalidBatchJob, name, e.Type)
+	}
+	switch e.Path {
+	case "", "on", "off", "auto":
+	default:
+		return fmt.Errorf("%w: %s path must be on, off or auto, got %q", ErrInvalidBatchJob, name, e.Path)
+	}
+	if !e.isLocal() && (e.AccessKey == "" || e.SecretKey == "") {
+		return fmt.Errorf("%w: %s credentials are required for a remote endpoint", ErrInvalidBatchJob, name)
+	}
+	return nil
+}
+
+// ReplicateJobBuilder builds a replicate batch job.
+type ReplicateJobBuilder struct {
+	source, target BatchJobReplicateEndpoint
+	filter         BatchJobFilter
+	notify         batchJobNotify
+	retry          batchJobRetry
+}
+
+// NewReplicateJob returns a builder for a replicate batch job.
+func NewReplicateJob() *ReplicateJobBuilder {
+	return &ReplicateJobBuilder{}
+}
+
+// Type returns BatchJobReplicate.
+func (b *ReplicateJobBuilder) Type() BatchJobType { return BatchJobReplicate }
+
+// Source sets the source of the objects to be replicated.
+func (b *ReplicateJobBuilder) Source(src BatchJobReplicateEndpoint) *ReplicateJobBuilder {
+	b.source = src
+	return b
+}
+
+// Target sets where the objects are replicated to.
+func (b *ReplicateJobBuilder) Target(tgt BatchJobReplicateEndpoint) *ReplicateJobBuilder {
+	b.target = tgt
+	return b
+}
+
+// Filter sets the criteria the source objects must match.
+func (b *ReplicateJobBuilder) Filter(f BatchJobFilter) *ReplicateJobBuilder {
+	b.filter = f
+	return b
+}
+
+// Notify sets the endpoint receiving the job status events.
+func (b *ReplicateJobBuilder) Notify(endpoint, token string) *ReplicateJobBuilder {
+	b.notify = batchJobNotify{endpoint: endpoint, token: token}
+	return b
+}
+
+// Retry sets the number of attempts of the job and the delay between them.
+func (b *ReplicateJobBuilder) Retry(attempts int, delay time.Duration) *ReplicateJobBuilder {
+	b.retry = batchJobRetry{attempts: attempts, delay: delay}
+	return b
+}
+
+// YAML validates the job and renders its definition.
+func (b *ReplicateJobBuilder) YAML() (string, error) {
+	if err := b.source.validate("source"); err != nil {
+		return "", err
+	}
+	if err := b.target.validate("target"); err != nil {
+		return "", err
+	}
+	if !b.source.isLocal() && !b.target.isLocal() {
+		return "", fmt.Errorf("%w: either source or target must be the local deployment", ErrInvalidBatchJob)
+	}
+	if b.filter.KMSKey != "" {
+		return "", fmt.Errorf("%w: kms key filter is not supported by replicate jobs", ErrInvalidBatchJob)
+	}
+	if err := b.retry.validate(); err != nil {
+		return "", err
+	}
+
+	var w batchJobWriter
+	w.section(0, string(BatchJobReplicate))
+	w.value(1, "apiVersion", "v1")
+	for _, e := range []struct {
+		name string
+		ep   BatchJobReplicateEndpoint
+	}{{"source", b.source}, {"target", b.target}} {
+		w.section(1, e.name)
+		w.value(2, "type", e.ep.Type)
+		w.value(2, "bucket", e.ep.Bucket)
+		w.value(2, "prefix", e.ep.Prefix)
+		w.value(2, "endpoint", e.ep.Endpoint)
+		w.value(2, "path", e.ep.Path)
+		if e.ep.AccessKey != "" || e.ep.SecretKey != "" {
+			w.section(2, "credentials")
+			w.value(3, "accessKey", e.ep.AccessKey)
+			w.value(3, "secretKey", e.ep.SecretKey)
+			w.value(3, "sessionToken", e.ep.SessionToken)
+		}
+	}
+	if !b.filter.isZero() || !b.notify.isZero() || !b.retry.isZero() {
+		w.section(1, "flags")
+		b.filter.write(&w, 2)
+		b.notify.write(&w, 2)
+		b.retry.write(&w, 2)
+	}
+	return w.String(), nil
+}
+
+// KeyRotateJobBuilder builds a keyrotate batch job.
+type KeyRotateJobBuilder struct {
+	bucket, prefix string
+	encType        string
+	encKey         string
+	encContext     string
+	filter         BatchJobFilter
+	notify         batchJobNotify
+	retry          batchJobRetry
+}
+
+// NewKeyRotateJob returns a builder for a keyrotate batch job on bucket.
+// Objects are re-encrypted with SSE-S3 unless KMSKey is set.
+func NewKeyRotateJob(bucket string) *KeyRotateJobBuilder {
+	return &KeyRotateJobBuilder{bucket: bucket, encType: "sse-s3"}
+}
+
+// Type returns BatchJobKeyRotate.
+func (b *KeyRotateJobBuilder) Type() BatchJobType { return BatchJobKeyRotate }
+
+// Prefix restricts the job to the objects under prefix.
+func (b *KeyRotateJobBuilder) Prefix(prefix string) *KeyRotateJobBuilder {
+	b.prefix = prefix
+	return b
+}
+
+// KMSKey re-encrypts the objects with SSE-KMS using the given key and
+// optional key context.
+func (b *KeyRotateJobBuilder) KMSKey(key, context string) *KeyRotateJobBuilder {
+	b.encType, b.encKey, b.encContext = "sse-kms", key, context
+	return b
+}
+
+// Filter sets the criteria the objects must match.
+func (b *KeyRotateJobBuilder) Filter(f BatchJobFilter) *KeyRotateJobBuilder {
+	b.filter = f
+	return b
+}
+
+// Notify sets the endpoint receiving the job status events.
+func (b *KeyRotateJobBuilder) Notify(endpoint, token string) *KeyRotateJobBuilder {
+	b.notify = batchJobNotify{endpoint: endpoint, token: token}
+	return b
+}
+
+// Retry sets the number of attempts of the job and the delay between them.
+func (b *KeyRotateJobBuilder) Retry(attempts int, delay time.Duration) *KeyRotateJobBuilder {
+	b.retry = batchJobRetry{attempts: attempts, delay: delay}
+	return b
+}
+
+// YAML validates the job and renders its definition.
+func (b *KeyRotateJobBuilder) YAML() (string, error) {
+	if b.bucket == "" {
+		return "", fmt.Errorf("%w: bucket cannot be empty", ErrInvalidBatchJob)
+	}
+	if b.encType == "sse-kms" && b.encKey == "" {
+		return "", fmt.Errorf("%w: kms key cannot be empty for sse-kms", ErrInvalidBatchJob)
+	}
+	if err := b.retry.validate(); err != nil {
+		return "", err
+	}
+
+	var w batchJobWriter
+	w.section(0, string(BatchJobKeyRotate))
+	w.value(1, "apiVersion", "v1")
+	w.value(1, "bucket", b.bucket)
+	w.value(1, "prefix", b.prefix)
+	w.section(1, "encryption")
+	w.value(2, "type", b.encType)
+	w.value(2, "key", b.encKey)
+	w.value(2, "context", b.encContext)
+	if !b.filter.isZero() || !b.notify.isZero() || !b.retry.isZero() {
+		w.section(1, "flags")
+		b.filter.write(&w, 2)
+		b.notify.write(&w, 2)
+		b.retry.write(&w, 2)
+	}
+	return w.String(), nil
+}
+
+// Expire rule types of an expire batch job.
+const (
+	BatchJobExpireObject  = "object"
+	BatchJobExpireDeleted = "deleted"
+)
+
+// BatchJobExpireRule is a rule of an expire batch job. Zero values are not
+// rendered, size limits are in bytes.
+type BatchJobExpireRule struct {
+	Type            string // BatchJobExpireObject or BatchJobExpireDeleted
+	Name            string
+	OlderThan       time.Duration
+	CreatedBefore   time.Time
+	Tags            []BatchJobKV
+	Metadata        []BatchJobKV
+	SizeLessThan    int64
+	SizeGreaterThan int64
+	RetainVersions  int
+}
+
+func (r BatchJobExpireRule) validate() error {
+	switch r.Type {
+	case BatchJobExpireObject:
+	case BatchJobExpireDeleted:
+		if len(r.Tags) > 0 || len(r.Metadata) > 0 || r.SizeLessThan != 0 || r.SizeGreaterThan != 0 {
+			return fmt.Errorf("%w: tags, metadata and size are not supported by deleted rules", ErrInvalidBatchJob)
+		}
+	default:
+		return fmt.Errorf("%w: rule type must be %s or %s, got %q", ErrInvalidBatchJob, BatchJobExpireObject, BatchJobExpireDeleted, r.Type)
+	}
+	if r.SizeLessThan < 0 || r.SizeGreaterThan < 0 || r.RetainVersions < 0 {
+		return fmt.Errorf("%w: rule size and retained versions cannot be negative", ErrInvalidBatchJob)
+	}
+	return nil
+}
+
+// ExpireJobBuilder builds an expire batch job.
+type ExpireJobBuilder struct {
+	bucket, prefix string
+	rules          []BatchJobExpireRule
+	notify         batchJobNotify
+	retry          batchJobRetry
+}
+
+// NewExpireJob returns a builder for an expire batch job on bucket.
+func NewExpireJob(bucket string) *ExpireJobBuilder {
+	return &ExpireJobBuilder{bucket: bucket}
+}
+
+// Type returns BatchJobExpire.
+func (b *ExpireJobBuilder) Type() BatchJobType { return BatchJobExpire }
+
+// Prefix restricts the job to the objects under prefix.
+func (b *ExpireJobBuilder) Prefix(prefix string) *ExpireJobBuilder {
+	b.prefix = prefix
+	return b
+}
+
+// Rule adds an expiry rule, at least one rule is required.
+func (b *ExpireJobBuilder) Rule(r BatchJobExpireRule) *ExpireJobBuilder {
+	b.rules = append(b.rules, r)
+	return b
+}
+
+// Notify sets the endpoint receiving the job completion status.
+func (b *ExpireJobBuilder) Notify(endpoint, token string) *ExpireJobBuilder {
+	b.notify = batchJobNotify{endpoint: endpoint, token: token}
+	return b
+}
+
+// Retry sets the number of attempts of the job and the delay between them.
+func (b *ExpireJobBuilder) Retry(attempts int, delay time.Duration) *ExpireJobBuilder {
+	b.retry = batchJobRetry{attempts: attempts, delay: delay}
+	return b
+}
+
+// YAML validates the job and renders its definition.
+func (b *ExpireJobBuilder) YAML() (string, error) {
+	if b.bucket == "" {
+		return "", fmt.Errorf("%w: bucket cannot be empty", ErrInvalidBatchJob)
+	}
+	if len(b.rules) == 0 {
+		return "", fmt.Errorf("%w: at least one rule is required", ErrInvalidBatchJob)
+	}
+	for _, r := range b.rules {
+		if err := r.validate(); err != nil {
+			return "", err
+		}
+	}
+	if err := b.retry.validate(); err != nil {
+		return "", err
+	}
+
+	var w batchJobWriter
+	w.section(0, string(BatchJobExpire))
+	w.value(1, "apiVersion", "v1")
+	w.value(1, "bucket", b.bucket)
+	w.value(1, "prefix", b.prefix)
+	w.section(1, "rules")
+	for _, r := range b.rules {
+		w.item(2, "type", r.Type)
+		w.value(3, "name", r.Name)
+		w.duration(3, "olderThan", r.OlderThan)
+		w.time(3, "createdBefore", r.CreatedBefore)
+		w.kvs(3, "tags", r.Tags)
+		w.kvs(3, "metadata", r.Metadata)
+		if r.SizeLessThan > 0 || r.SizeGreaterThan > 0 {
+			w.section(3, "size")
+			w.int(4, "lessThan", r.SizeLessThan)
+			w.int(4, "greaterThan", r.SizeGreaterThan)
+		}
+		if r.RetainVersions > 0 {
+			w.section(3, "purge")
+			w.int(4, "retainVersions", int64(r.RetainVersions))
+		}
+	}
+	b.notify.write(&w, 1)
+	b.retry.write(&w, 1)
+	return w.String(), nil
+}
+
+type batchJobNotify struct {
+	endpoint, token string
+}
+
+func (n batchJobNotify) isZero() bool {
+	return n.endpoint == "" && n.token == ""
+}
+
+func (n batchJobNotify) write(w *batchJobWriter, indent int) {
+	if n.isZero() {
+		return
+	}
+	w.section(indent, "notify")
+	w.value(indent+1, "endpoint", n.endpoint)
+	w.value(indent+1, "token", n.token)
+}
+
+type batchJobRetry struct {
+	attempts int
+	delay    time.Duration
+}
+
+func (r batchJobRetry) isZero() bool {
+	return r.attempts == 0 && r.delay == 0
+}
+
+func (r batchJobRetry) validate() error {
+	if r.attempts < 0 || r.delay < 0 {
+		return fmt.Errorf("%w: retry attempts and delay cannot be negative", ErrInvalidBatchJob)
+	}
+	return nil
+}
+
+func (r batchJobRetry) write(w *batchJobWriter, indent int) {
+	if r.isZero() {
+		return
+	}
+	w.section(indent, "retry")
+	w.int(indent+1, "attempts", int64(r.attempts))
+	w.duration(indent+1, "delay", r.delay)
+}
+
+func (f BatchJobFilter) isZero() bool {
+	return f.NewerThan == 0 && f.OlderThan == 0 && f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero() &&
+		len(f.Tags) == 0 && len(f.Metadata) == 0 && f.KMSKey == ""
+}
+
+func (f BatchJobFilter) write(w *batchJobWriter, indent int) {
+	if f.isZero() {
+		return
+	}
+	w.section(indent, "filter")
+	w.duration(indent+1, "newerThan", f.NewerThan)
+	w.duration(indent+1, "olderThan", f.OlderThan)
+	w.time(indent+1, "createdAfter", f.CreatedAfter)
+	w.time(indent+1, "createdBefore", f.CreatedBefore)
+	w.kvs(indent+1, "tags", f.Tags)
+	w.kvs(indent+1, "metadata", f.Metadata)
+	w.value(indent+1, "kmskey", f.KMSKey)
+}
+
+// batchJobWriter renders the subset of YAML used by batch job definitions,
+// block mappings and sequences of mappings with double quoted scalars.
+type batchJobWriter struct {
+	strings.Builder
+}
+
+func (w *batchJobWriter) line(indent int, s string) {
+	w.WriteString(strings.Repeat("  ", indent))
+	w.WriteString(s)
+	w.WriteByte('\n')
+}
+
+func (w *batchJobWriter) section(indent int, key string) {
+	w.line(indent, key+":")
+}
+
+// value writes a quoted scalar, empty values are skipped.
+func (w *batchJobWriter) value(indent int, key, value string) {
+	if value != "" {
+		w.line(indent, key+": "+strconv.Quote(value))
+	}
+}
+
+// item starts a new mapping of a sequence with the given first entry,
+// following entries are written at indent+1.
+func (w *batchJobWriter) item(indent int, key, value string) {
+	w.line(indent, "- "+key+": "+strconv.Quote(value))
+}
+
+func (w *batchJobWriter) int(indent int, key string, v int64) {
+	if v != 0 {
+		w.line(indent, key+": "+strconv.FormatInt(v, 10))
+	}
+}
+
+func (w *batchJobWriter) duration(indent int, key string, d time.Duration) {
+	if d != 0 {
+		w.value(indent, key, d.String())
+	}
+}
+
+func (w *batchJobWriter) time(indent int, key string, t time.Time) {
+	if !t.IsZero() {
+		w.value(indent, key, t.UTC().Format(time.RFC3339))
+	}
+}
+
+func (w *batchJobWriter) kvs(indent int, key string, kvs []BatchJobKV) {
+	if len(kvs) == 0 {
+		return
+	}
+	w.section(indent, key)
+	for _, kv := range kvs {
+		w.item(indent+1, "key", kv.Key)
+		w.value(indent+2, "value", kv.Value)
+	}
+}
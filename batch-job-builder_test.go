@@ -0,0 +1,255 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseBatchJobYAML is a stub of the server side job parser, it only
+// understands the YAML subset rendered by the batch job builders.
+func parseBatchJobYAML(s string) (map[string]interface{}, error) {
+	type line struct {
+		indent int
+		text   string
+	}
+	var lines []line
+	for _, l := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		t := strings.TrimLeft(l, " ")
+		if (len(l)-len(t))%2 != 0 {
+			return nil, fmt.Errorf("bad indentation: %q", l)
+		}
+		lines = append(lines, line{indent: (len(l) - len(t)) / 2, text: t})
+	}
+
+	scalar := func(v string) (interface{}, error) {
+		if strings.HasPrefix(v, `"`) {
+			return strconv.Unquote(v)
+		}
+		return strconv.ParseInt(v, 10, 64)
+	}
+
+	var parseMap func(i, indent int) (map[string]interface{}, int, error)
+	parseMap = func(i, indent int) (map[string]interface{}, int, error) {
+		m := make(map[string]interface{})
+		for i < len(lines) && lines[i].indent == indent && !strings.HasPrefix(lines[i].text, "- ") {
+			kv := strings.SplitN(lines[i].text, ":", 2)
+			if len(kv) != 2 {
+				return nil, i, fmt.Errorf("bad line: %q", lines[i].text)
+			}
+			key, val := kv[0], strings.TrimSpace(kv[1])
+			i++
+			if val != "" {
+				v, err := scalar(val)
+				if err != nil {
+					return nil, i, err
+				}
+				m[key] = v
+				continue
+			}
+			if i < len(lines) && strings.HasPrefix(lines[i].text, "- ") {
+				var list []interface{}
+				for i < len(lines) && lines[i].indent == indent+1 && strings.HasPrefix(lines[i].text, "- ") {
+					// Parse the item as a mapping indented past the dash.
+					lines[i] = line{indent: indent + 2, text: strings.TrimPrefix(lines[i].text, "- ")}
+					item, next, err := parseMap(i, indent+2)
+					if err != nil {
+						return nil, i, err
+					}
+					list = append(list, item)
+					i = next
+				}
+				m[key] = list
+				continue
+			}
+			sub, next, err := parseMap(i, indent+1)
+			if err != nil {
+				return nil, i, err
+			}
+			m[key] = sub
+			i = next
+		}
+		return m, i, nil
+	}
+
+	m, i, err := parseMap(0, 0)
+	if err == nil && i != len(lines) {
+		err = fmt.Errorf("unexpected line: %q", lines[i].text)
+	}
+	return m, err
+}
+
+func TestReplicateJobBuilder(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := NewReplicateJob().
+		Source(BatchJobReplicateEndpoint{Type: "minio", Bucket: "src", Prefix: "data/"}).
+		Target(BatchJobReplicateEndpoint{
+			Type:      "s3",
+			Bucket:    "dst",
+			Endpoint:  "https://s3.example.com",
+			AccessKey: "access",
+			SecretKey: `se"cret`,
+		}).
+		Filter(BatchJobFilter{
+			NewerThan:    7 * 24 * time.Hour,
+			CreatedAfter: created,
+			Metadata:     []BatchJobKV{{Key: "content-type", Value: "image/*"}},
+		}).
+		Retry(10, 500*time.Millisecond)
+
+	var got map[string]interface{}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if got, err = parseBatchJobYAML(string(body)); err != nil {
+			t.Errorf("unable to parse job %q: %v", body, err)
+		}
+		w.Write([]byte(`{"id":"job-1","type":"replicate"}`))
+	})
+	res, err := adm.StartBatchJobFromBuilder(context.Background(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != "job-1" {
+		t.Errorf("Unexpected result %+v", res)
+	}
+
+	want := map[string]interface{}{
+		"replicate": map[string]interface{}{
+			"apiVersion": "v1",
+			"source": map[string]interface{}{
+				"type":   "minio",
+				"bucket": "src",
+				"prefix": "data/",
+			},
+			"target": map[string]interface{}{
+				"type":     "s3",
+				"bucket":   "dst",
+				"endpoint": "https://s3.example.com",
+				"credentials": map[string]interface{}{
+					"accessKey": "access",
+					"secretKey": `se"cret`,
+				},
+			},
+			"flags": map[string]interface{}{
+				"filter": map[string]interface{}{
+					"newerThan":    "168h0m0s",
+					"createdAfter": "2024-01-02T03:04:05Z",
+					"metadata": []interface{}{
+						map[string]interface{}{"key": "content-type", "value": "image/*"},
+					},
+				},
+				"retry": map[string]interface{}{
+					"attempts": int64(10),
+					"delay":    "500ms",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected job\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestExpireJobBuilder(t *testing.T) {
+	job, err := NewExpireJob("bucket").
+		Rule(BatchJobExpireRule{
+			Type:           BatchJobExpireObject,
+			Name:           "*.log",
+			Tags:           []BatchJobKV{{Key: "name", Value: "pick*"}},
+			SizeLessThan:   1 << 20,
+			RetainVersions: 5,
+		}).
+		Rule(BatchJobExpireRule{Type: BatchJobExpireDeleted, OlderThan: time.Hour}).
+		YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseBatchJobYAML(job)
+	if err != nil {
+		t.Fatalf("unable to parse job %q: %v", job, err)
+	}
+	want := map[string]interface{}{
+		"expire": map[string]interface{}{
+			"apiVersion": "v1",
+			"bucket":     "bucket",
+			"rules": []interface{}{
+				map[string]interface{}{
+					"type": "object",
+					"name": "*.log",
+					"tags": []interface{}{
+						map[string]interface{}{"key": "name", "value": "pick*"},
+					},
+					"size":  map[string]interface{}{"lessThan": int64(1 << 20)},
+					"purge": map[string]interface{}{"retainVersions": int64(5)},
+				},
+				map[string]interface{}{
+					"type":      "deleted",
+					"olderThan": "1h0m0s",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected job\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestBatchJobBuilderValidate(t *testing.T) {
+	local := BatchJobReplicateEndpoint{Bucket: "b"}
+	remote := BatchJobReplicateEndpoint{Bucket: "b", Endpoint: "https://remote", AccessKey: "a", SecretKey: "s"}
+	tests := []struct {
+		name string
+		b    BatchJobBuilder
+	}{
+		{"replicate without source bucket", NewReplicateJob().Target(local)},
+		{"replicate without local side", NewReplicateJob().Source(remote).Target(remote)},
+		{"replicate remote without credentials", NewReplicateJob().Source(local).Target(BatchJobReplicateEndpoint{Bucket: "b", Endpoint: "https://remote"})},
+		{"replicate bad type", NewReplicateJob().Source(local).Target(BatchJobReplicateEndpoint{Bucket: "b", Type: "gcs"})},
+		{"keyrotate without bucket", NewKeyRotateJob("")},
+		{"keyrotate kms without key", NewKeyRotateJob("b").KMSKey("", "")},
+		{"expire without rule", NewExpireJob("b")},
+		{"expire bad rule", NewExpireJob("b").Rule(BatchJobExpireRule{Type: "all"})},
+		{"expire deleted with tags", NewExpireJob("b").Rule(BatchJobExpireRule{Type: BatchJobExpireDeleted, Tags: []BatchJobKV{{Key: "k"}}})},
+	}
+	for _, test := range tests {
+		if _, err := test.b.YAML(); !errors.Is(err, ErrInvalidBatchJob) {
+			t.Errorf("%s: expected ErrInvalidBatchJob, got %v", test.name, err)
+		}
+	}
+
+	if _, err := NewReplicateJob().Source(local).Target(remote).YAML(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if _, err := NewKeyRotateJob("b").KMSKey("my-key", "").YAML(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
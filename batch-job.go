@@ -27,6 +27,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -316,6 +318,103 @@ func (adm *AdminClient) ListBatchJobs(ctx context.Context, fl *ListBatchJobsFilt
 	return result, nil
 }
 
+// ListBatchJobsOpts filters the jobs returned by ListBatchJobsWithOpts,
+// zero values match all jobs.
+type ListBatchJobsOpts struct {
+	Type   BatchJobType
+	Status BatchJobStatus
+	// Submission time range, both bounds are inclusive.
+	SubmittedAfter  time.Time
+	SubmittedBefore time.Time
+}
+
+// matches - returns true if job matches the type and submission time
+// filters, the status is not part of the listed jobs.
+func (o ListBatchJobsOpts) matches(job BatchJobResult) bool {
+	if o.Type != "" && job.Type != o.Type {
+		return false
+	}
+	if !o.SubmittedAfter.IsZero() && job.Started.Before(o.SubmittedAfter) {
+		return false
+	}
+	if !o.SubmittedBefore.IsZero() && job.Started.After(o.SubmittedBefore) {
+		return false
+	}
+	return true
+}
+
+// ListBatchJobsWithOpts lists the batch jobs matching opts, most recently
+// submitted first. Only the job type is filtered by the server, the other
+// filters are applied on the returned jobs. Filtering by status reads the
+// status of every job from the status-job API, ErrNotSupported is returned
+// if the server does not provide it.
+func (adm *AdminClient) ListBatchJobsWithOpts(ctx context.Context, opts ListBatchJobsOpts) (ListBatchJobsResult, error) {
+	all, err := adm.ListBatchJobs(ctx, &ListBatchJobsFilter{ByJobType: string(opts.Type)})
+	if err != nil {
+		return ListBatchJobsResult{}, err
+	}
+
+	result := ListBatchJobsResult{Jobs: make([]BatchJobResult, 0, len(all.Jobs))}
+	for _, job := range all.Jobs {
+		if !opts.matches(job) {
+			continue
+		}
+		if opts.Status != "" {
+			status, err := adm.batchJobStatus(ctx, job.ID)
+			if err != nil {
+				return ListBatchJobsResult{}, err
+			}
+			if status != opts.Status {
+				continue
+			}
+		}
+		result.Jobs = append(result.Jobs, job)
+	}
+	sort.SliceStable(result.Jobs, func(i, j int) bool {
+		return result.Jobs[i].Started.After(result.Jobs[j].Started)
+	})
+	return result, nil
+}
+
+// batchJobStatus - returns the status of the batch job jobID from the
+// last metric reported by the status-job API.
+func (adm *AdminClient) batchJobStatus(ctx context.Context, jobID string) (BatchJobStatus, error) {
+	values := make(url.Values)
+	values.Set("jobId", jobID)
+
+	resp, err := adm.executeMethod(ctx, http.MethodGet,
+		requestData{
+			relPath:     adminAPIPrefix + "/status-job",
+			queryValues: values,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	defer closeResponse(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		err = httpRespToErrorResponse(resp)
+		if strings.Contains(ToErrorResponse(err).Code, "NoSuch") {
+			// The job is gone.
+			return "", err
+		}
+		switch resp.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return "", fmt.Errorf("%w: batch job status: %v", ErrNotSupported, err)
+		}
+		return "", err
+	}
+
+	var status struct {
+		LastMetric JobMetric `json:"lastMetric"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return "", err
+	}
+	return batchJobProgress(status.LastMetric).Status, nil
+}
+
 // CancelBatchJob cancels ongoing batch job.
 func (adm *AdminClient) CancelBatchJob(ctx context.Context, jobID string) error {
 	values := make(url.Values)
@@ -336,3 +435,162 @@ func (adm *AdminClient) CancelBatchJob(ctx context.Context, jobID string) error
 	}
 	return nil
 }
+
+// BatchJobStatus is the state of a batch job.
+type BatchJobStatus string
+
+// Batch job states.
+const (
+	BatchJobStatusRunning   BatchJobStatus = "running"
+	BatchJobStatusCompleted BatchJobStatus = "completed"
+	BatchJobStatusFailed    BatchJobStatus = "failed"
+)
+
+// IsTerminal returns true when the job is no longer running.
+func (s BatchJobStatus) IsTerminal() bool {
+	return s == BatchJobStatusCompleted || s == BatchJobStatusFailed
+}
+
+// BatchJobProgress is a progress event of a batch job sent by WatchBatchJob.
+type BatchJobProgress struct {
+	JobID            string         `json:"jobID"`
+	JobType          BatchJobType   `json:"jobType"`
+	Status           BatchJobStatus `json:"status"`
+	StartTime        time.Time      `json:"startTime"`
+	LastUpdate       time.Time      `json:"lastUpdate"`
+	RetryAttempts    int            `json:"retryAttempts"`
+	Objects          int64          `json:"objects"`
+	ObjectsFailed    int64          `json:"objectsFailed"`
+	BytesTransferred int64          `json:"bytesTransferred,omitempty"`
+	BytesFailed      int64          `json:"bytesFailed,omitempty"`
+
+	// Err is set when watching the job failed, no further
+	// events are sent after it.
+	Err error `json:"-"`
+}
+
+func batchJobProgress(m JobMetric) BatchJobProgress {
+	p := BatchJobProgress{
+		JobID:         m.JobID,
+		JobType:       BatchJobType(m.JobType),
+		Status:        BatchJobStatusRunning,
+		StartTime:     m.StartTime,
+		LastUpdate:    m.LastUpdate,
+		RetryAttempts: m.RetryAttempts,
+	}
+	switch {
+	case m.Failed:
+		p.Status = BatchJobStatusFailed
+	case m.Complete:
+		p.Status = BatchJobStatusCompleted
+	}
+	switch {
+	case m.Replicate != nil:
+		p.Objects, p.ObjectsFailed = m.Replicate.Objects, m.Replicate.ObjectsFailed
+		p.BytesTransferred, p.BytesFailed = m.Replicate.BytesTransferred, m.Replicate.BytesFailed
+	case m.KeyRotate != nil:
+		p.Objects, p.ObjectsFailed = m.KeyRotate.Objects, m.KeyRotate.ObjectsFailed
+	case m.Expired != nil:
+		p.Objects, p.ObjectsFailed = m.Expired.Objects, m.Expired.ObjectsFailed
+	}
+	return p
+}
+
+// batchJobWatchInterval is the interval between two progress samples.
+var batchJobWatchInterval = time.Second
+
+// batchJobMissingSamples is the number of consecutive progress samples, or
+// empty streams, without the job after which WatchBatchJob gives up.
+const batchJobMissingSamples = 10
+
+// ErrBatchJobNotFound is sent by WatchBatchJob when the job is not reported
+// by the server.
+var ErrBatchJobNotFound = errors.New("batch job not found")
+
+// WatchBatchJob streams the progress of a batch job from the batch job
+// metrics until it reaches a terminal state. The last event carries the
+// terminal status, the channel is closed afterwards, on error or when ctx
+// is canceled. The stream is re-established if the server closes it
+// before the job is done.
+//
+// The job is looked up with DescribeBatchJob first, an unknown job is
+// returned as an error. A job which stops being reported by the metrics,
+// e.g. because it finished and was removed, ends the stream with an event
+// carrying ErrBatchJobNotFound.
+func (adm *AdminClient) WatchBatchJob(ctx context.Context, jobID string) (<-chan BatchJobProgress, error) {
+	if jobID == "" {
+		return nil, ErrInvalidArgument("job ID cannot be empty")
+	}
+	if _, err := adm.DescribeBatchJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	progressCh := make(chan BatchJobProgress)
+	go func() {
+		defer close(progressCh)
+
+		send := func(p BatchJobProgress) bool {
+			select {
+			case progressCh <- p:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var missing int
+		for {
+			var done, sampled bool
+			streamCtx, cancel := context.WithCancel(ctx)
+			err := adm.Metrics(streamCtx, MetricsOptions{
+				Type:     MetricsBatchJobs,
+				Interval: batchJobWatchInterval,
+				ByJobID:  jobID,
+			}, func(m RealtimeMetrics) {
+				if done {
+					return
+				}
+				sampled = true
+				var job JobMetric
+				ok := false
+				if m.Aggregated.BatchJobs != nil {
+					job, ok = m.Aggregated.BatchJobs.Jobs[jobID]
+				}
+				if !ok {
+					if missing++; missing >= batchJobMissingSamples {
+						send(BatchJobProgress{JobID: jobID, Err: ErrBatchJobNotFound})
+						done = true
+						cancel()
+					}
+					return
+				}
+				missing = 0
+				p := batchJobProgress(job)
+				if !send(p) || p.Status.IsTerminal() {
+					done = true
+					cancel()
+				}
+			})
+			cancel()
+			if done || ctx.Err() != nil {
+				return
+			}
+			if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+				send(BatchJobProgress{JobID: jobID, Err: err})
+				return
+			}
+			if !sampled {
+				if missing++; missing >= batchJobMissingSamples {
+					send(BatchJobProgress{JobID: jobID, Err: ErrBatchJobNotFound})
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(batchJobWatchInterval):
+			}
+		}
+	}()
+	return progressCh, nil
+}
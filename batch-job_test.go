@@ -0,0 +1,239 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchBatchJob(t *testing.T) {
+	defer func(d time.Duration) { batchJobWatchInterval = d }(batchJobWatchInterval)
+	batchJobWatchInterval = 10 * time.Millisecond
+
+	job := func(objects int64, complete bool) RealtimeMetrics {
+		return RealtimeMetrics{Aggregated: Metrics{BatchJobs: &BatchJobMetrics{Jobs: map[string]JobMetric{
+			"job-1": {
+				JobID:     "job-1",
+				JobType:   string(BatchJobReplicate),
+				Complete:  complete,
+				Replicate: &ReplicateInfo{Objects: objects, BytesTransferred: objects * 10, ObjectsFailed: 1},
+			},
+		}}}}
+	}
+
+	var requests int
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == libraryAdminURLPrefix+adminAPIPrefix+"/describe-job" {
+			describeJob(w, r)
+			return
+		}
+		if r.URL.Query().Get("by-jobID") != "job-1" {
+			t.Errorf("Unexpected query %v", r.URL.Query())
+		}
+		requests++
+		enc := json.NewEncoder(w)
+		if requests == 1 {
+			// The job is not reported yet, then the stream ends early.
+			enc.Encode(RealtimeMetrics{})
+			enc.Encode(job(1, false))
+			return
+		}
+		enc.Encode(job(2, false))
+		enc.Encode(job(3, true))
+		// Must not be sent after the terminal event.
+		enc.Encode(job(4, true))
+	})
+
+	ch, err := adm.WatchBatchJob(context.Background(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []BatchJobProgress
+	for p := range ch {
+		if p.Err != nil {
+			t.Fatal(p.Err)
+		}
+		events = append(events, p)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	for i, p := range events {
+		if p.Objects != int64(i+1) || p.BytesTransferred != int64(i+1)*10 || p.ObjectsFailed != 1 {
+			t.Errorf("Unexpected event %d: %+v", i, p)
+		}
+	}
+	if last := events[2]; last.Status != BatchJobStatusCompleted || last.JobType != BatchJobReplicate {
+		t.Errorf("Unexpected final event %+v", last)
+	}
+	if events[0].Status != BatchJobStatusRunning {
+		t.Errorf("Unexpected status %q", events[0].Status)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the stream to be re-established once, got %d requests", requests)
+	}
+
+	if _, err = adm.WatchBatchJob(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty job ID")
+	}
+}
+
+// describeJob - serves describe-job for job-1 only.
+func describeJob(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("jobId") != "job-1" {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioAdminNoSuchJob", Message: "The specified job does not exist."})
+		return
+	}
+	w.Write([]byte("replicate:\n  apiVersion: v1\n"))
+}
+
+func TestWatchBatchJobNotFound(t *testing.T) {
+	defer func(d time.Duration) { batchJobWatchInterval = d }(batchJobWatchInterval)
+	batchJobWatchInterval = time.Millisecond
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == libraryAdminURLPrefix+adminAPIPrefix+"/describe-job" {
+			describeJob(w, r)
+			return
+		}
+		// The job finished and is no longer part of the metrics.
+		json.NewEncoder(w).Encode(RealtimeMetrics{})
+	})
+
+	if _, err := adm.WatchBatchJob(context.Background(), "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected an unknown job to fail, got %v", err)
+	}
+
+	ch, err := adm.WatchBatchJob(context.Background(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last BatchJobProgress
+	for p := range ch {
+		last = p
+	}
+	if !errors.Is(last.Err, ErrBatchJobNotFound) {
+		t.Errorf("Expected the stream to end with ErrBatchJobNotFound, got %+v", last)
+	}
+}
+
+func TestListBatchJobsWithOpts(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	jobs := ListBatchJobsResult{Jobs: []BatchJobResult{
+		{ID: "r-failed-old", Type: BatchJobReplicate, Started: now.Add(-3 * time.Hour)},
+		{ID: "r-running", Type: BatchJobReplicate, Started: now.Add(-2 * time.Hour)},
+		{ID: "e-failed", Type: BatchJobExpire, Started: now.Add(-time.Hour)},
+		{ID: "r-failed-new", Type: BatchJobReplicate, Started: now},
+		{ID: "r-completed", Type: BatchJobReplicate, Started: now},
+	}}
+	metrics := map[string]JobMetric{
+		"r-failed-old": {JobID: "r-failed-old", Failed: true},
+		"r-running":    {JobID: "r-running"},
+		"e-failed":     {JobID: "e-failed", Failed: true},
+		"r-failed-new": {JobID: "r-failed-new", Failed: true},
+		"r-completed":  {JobID: "r-completed", Complete: true},
+	}
+	testCases := []struct {
+		name         string
+		opts         ListBatchJobsOpts
+		noStatusAPI  bool
+		wantJobs     []string
+		wantStatuses int
+		wantErr      error
+	}{
+		{
+			name:         "failed replicate jobs",
+			opts:         ListBatchJobsOpts{Type: BatchJobReplicate, Status: BatchJobStatusFailed},
+			wantJobs:     []string{"r-failed-new", "r-failed-old"},
+			wantStatuses: 4,
+		},
+		{
+			name:         "completed jobs",
+			opts:         ListBatchJobsOpts{Status: BatchJobStatusCompleted},
+			wantJobs:     []string{"r-completed"},
+			wantStatuses: 5,
+		},
+		{
+			name:     "submission time range",
+			opts:     ListBatchJobsOpts{SubmittedAfter: now.Add(-150 * time.Minute), SubmittedBefore: now.Add(-time.Hour)},
+			wantJobs: []string{"e-failed", "r-running"},
+		},
+		{
+			name:        "status without status API",
+			opts:        ListBatchJobsOpts{Status: BatchJobStatusFailed},
+			noStatusAPI: true,
+			wantErr:     ErrNotSupported,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var statuses int
+			adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				switch r.URL.Path {
+				case libraryAdminURLPrefix + adminAPIPrefix + "/list-jobs":
+					for key := range q {
+						if key != "jobType" {
+							t.Errorf("Unexpected query parameter %s", key)
+						}
+					}
+					res := ListBatchJobsResult{Jobs: []BatchJobResult{}}
+					for _, job := range jobs.Jobs {
+						if q.Get("jobType") == "" || string(job.Type) == q.Get("jobType") {
+							res.Jobs = append(res.Jobs, job)
+						}
+					}
+					json.NewEncoder(w).Encode(res)
+				case libraryAdminURLPrefix + adminAPIPrefix + "/status-job":
+					if tc.noStatusAPI {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					statuses++
+					json.NewEncoder(w).Encode(map[string]JobMetric{"lastMetric": metrics[q.Get("jobId")]})
+				default:
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+			})
+
+			res, err := adm.ListBatchJobsWithOpts(context.Background(), tc.opts)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tc.wantErr, err)
+			}
+			var got []string
+			for _, job := range res.Jobs {
+				got = append(got, job.ID)
+			}
+			if !reflect.DeepEqual(got, tc.wantJobs) {
+				t.Errorf("Expected jobs %v, got %v", tc.wantJobs, got)
+			}
+			if statuses != tc.wantStatuses {
+				t.Errorf("Expected %d status requests, got %d", tc.wantStatuses, statuses)
+			}
+		})
+	}
+}
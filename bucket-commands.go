@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+// RetentionMode - object lock retention mode
+type RetentionMode string
+
+const (
+	// RetentionGovernance - retention can be bypassed with special permissions
+	RetentionGovernance RetentionMode = "GOVERNANCE"
+	// RetentionCompliance - retention cannot be bypassed
+	RetentionCompliance RetentionMode = "COMPLIANCE"
+)
+
+// RetentionUnit - unit of a default retention period
+type RetentionUnit string
+
+const (
+	// RetentionDays - retention period in days
+	RetentionDays RetentionUnit = "DAYS"
+	// RetentionYears - retention period in years
+	RetentionYears RetentionUnit = "YEARS"
+)
+
+// BucketRetention - default retention applied to new objects of a bucket
+type BucketRetention struct {
+	Mode     RetentionMode
+	Validity uint
+	Unit     RetentionUnit
+}
+
+// BucketOpts - options of MakeBucketWithAdmin
+type BucketOpts struct {
+	// Region of the bucket, the server region when empty.
+	Region     string
+	Versioning bool
+	// ObjectLock requires Versioning.
+	ObjectLock bool
+	// Retention requires ObjectLock.
+	Retention *BucketRetention
+}
+
+// Validate - checks the options for consistency.
+func (o BucketOpts) Validate() error {
+	if o.ObjectLock && !o.Versioning {
+		return ErrInvalidArgument("object lock requires versioning to be enabled")
+	}
+	if r := o.Retention; r != nil {
+		if !o.ObjectLock {
+			return ErrInvalidArgument("default retention requires object lock to be enabled")
+		}
+		if r.Mode != RetentionGovernance && r.Mode != RetentionCompliance {
+			return ErrInvalidArgument(fmt.Sprintf("invalid retention mode %q", r.Mode))
+		}
+		if r.Unit != RetentionDays && r.Unit != RetentionYears {
+			return ErrInvalidArgument(fmt.Sprintf("invalid retention unit %q", r.Unit))
+		}
+		if r.Validity == 0 {
+			return ErrInvalidArgument("retention validity must be positive")
+		}
+	}
+	return nil
+}
+
+type createBucketConfiguration struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CreateBucketConfiguration"`
+	Location string   `xml:"LocationConstraint"`
+}
+
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration"`
+	Status  string   `xml:"Status"`
+}
+
+type objectLockConfiguration struct {
+	XMLName           xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ObjectLockConfiguration"`
+	ObjectLockEnabled string   `xml:"ObjectLockEnabled"`
+	Rule              struct {
+		DefaultRetention struct {
+			Mode  RetentionMode `xml:"Mode"`
+			Days  uint          `xml:"Days,omitempty"`
+			Years uint          `xml:"Years,omitempty"`
+		} `xml:"DefaultRetention"`
+	} `xml:"Rule"`
+}
+
+// MakeBucketWithAdmin - creates bucket with the admin credentials through
+// the S3 API of the server, enabling versioning, object lock and default
+// retention as requested. If configuring the bucket fails, the bucket is
+// left in place and the error is returned.
+func (adm *AdminClient) MakeBucketWithAdmin(ctx context.Context, bucket string, opts BucketOpts) error {
+	if err := s3utils.CheckValidBucketNameStrict(bucket); err != nil {
+		return ErrInvalidArgument(err.Error())
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	var content []byte
+	if opts.Region != "" && opts.Region != "us-east-1" {
+		var err error
+		content, err = xml.Marshal(createBucketConfiguration{Location: opts.Region})
+		if err != nil {
+			return err
+		}
+	}
+	headers := make(http.Header)
+	if opts.ObjectLock {
+		// Enables versioning as well.
+		headers.Set("X-Amz-Bucket-Object-Lock-Enabled", "true")
+	}
+	err := adm.bucketRequest(ctx, http.MethodPut, bucket, nil, headers, content)
+	if err != nil {
+		return err
+	}
+
+	if opts.Versioning && !opts.ObjectLock {
+		content, err = xml.Marshal(versioningConfiguration{Status: "Enabled"})
+		if err != nil {
+			return err
+		}
+		if err = adm.bucketRequest(ctx, http.MethodPut, bucket, url.Values{"versioning": []string{""}}, nil, content); err != nil {
+			return fmt.Errorf("bucket %s created but enabling versioning failed: %w", bucket, err)
+		}
+	}
+
+	if r := opts.Retention; r != nil {
+		cfg := objectLockConfiguration{ObjectLockEnabled: "Enabled"}
+		cfg.Rule.DefaultRetention.Mode = r.Mode
+		if r.Unit == RetentionDays {
+			cfg.Rule.DefaultRetention.Days = r.Validity
+		} else {
+			cfg.Rule.DefaultRetention.Years = r.Validity
+		}
+		content, err = xml.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+		if err = adm.bucketRequest(ctx, http.MethodPut, bucket, url.Values{"object-lock": []string{""}}, nil, content); err != nil {
+			return fmt.Errorf("bucket %s created but setting default retention failed: %w", bucket, err)
+		}
+	}
+	return nil
+}
+
+// RemoveBucketWithAdmin - removes bucket with the admin credentials through
+// the S3 API of the server. With force the bucket is removed along with
+// its objects.
+func (adm *AdminClient) RemoveBucketWithAdmin(ctx context.Context, bucket string, force bool) error {
+	if err := s3utils.CheckValidBucketName(bucket); err != nil {
+		return ErrInvalidArgument(err.Error())
+	}
+	headers := make(http.Header)
+	if force {
+		headers.Set("X-Minio-Force-Delete", "true")
+	}
+	return adm.bucketRequest(ctx, http.MethodDelete, bucket, nil, headers, nil)
+}
+
+// bucketRequest - sends an S3 API request for bucket.
+func (adm *AdminClient) bucketRequest(ctx context.Context, method, bucket string, q url.Values, headers http.Header, content []byte) error {
+	if len(content) > 0 {
+		if headers == nil {
+			headers = make(http.Header)
+		}
+		sum := md5.Sum(content)
+		headers.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	resp, err := adm.executeMethod(ctx, method, requestData{
+		relPath:       "/" + bucket,
+		queryValues:   q,
+		customHeaders: headers,
+		content:       content,
+		isS3:          true,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
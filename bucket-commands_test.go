@@ -0,0 +1,87 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMakeBucketWithAdmin(t *testing.T) {
+	var reqs []string
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := r.Method + " " + r.URL.RequestURI()
+		if r.Header.Get("X-Amz-Bucket-Object-Lock-Enabled") == "true" {
+			req += " lock"
+		}
+		if strings.Contains(string(body), "<Days>30</Days>") {
+			req += " 30d"
+		}
+		reqs = append(reqs, req)
+	})
+
+	for i, opts := range []BucketOpts{
+		{ObjectLock: true},
+		{Retention: &BucketRetention{Mode: RetentionGovernance, Validity: 1, Unit: RetentionDays}},
+		{Versioning: true, ObjectLock: true, Retention: &BucketRetention{Mode: "LEGAL", Validity: 1, Unit: RetentionDays}},
+		{Versioning: true, ObjectLock: true, Retention: &BucketRetention{Mode: RetentionCompliance, Unit: RetentionYears}},
+	} {
+		if err := adm.MakeBucketWithAdmin(context.Background(), "bucket", opts); err == nil {
+			t.Errorf("Test %d: expected invalid options %+v to be rejected", i+1, opts)
+		}
+	}
+	if err := adm.MakeBucketWithAdmin(context.Background(), "Bad_Bucket", BucketOpts{}); err == nil {
+		t.Error("Expected an invalid bucket name to be rejected")
+	}
+	if len(reqs) != 0 {
+		t.Fatalf("Expected no request for invalid options, got %v", reqs)
+	}
+
+	err := adm.MakeBucketWithAdmin(context.Background(), "versioned", BucketOpts{Versioning: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = adm.MakeBucketWithAdmin(context.Background(), "locked", BucketOpts{
+		Versioning: true,
+		ObjectLock: true,
+		Retention:  &BucketRetention{Mode: RetentionGovernance, Validity: 30, Unit: RetentionDays},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = adm.RemoveBucketWithAdmin(context.Background(), "versioned", true); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /versioned",
+		"PUT /versioned?versioning=",
+		"PUT /locked lock",
+		"PUT /locked?object-lock= 30d",
+		"DELETE /versioned",
+	}
+	if strings.Join(reqs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected requests\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(reqs, "\n"))
+	}
+}
@@ -22,6 +22,8 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -924,7 +926,7 @@ func (o *SREditOptions) getURLValues() url.Values {
 func (adm *AdminClient) SiteReplicationEdit(ctx context.Context, site PeerInfo, opts SREditOptions) (ReplicateEditStatus, error) {
 	sitesBytes, err := json.Marshal(site)
 	if err != nil {
-		return ReplicateEditStatus{}, nil
+		return ReplicateEditStatus{}, err
 	}
 	encBytes, err := EncryptData(adm.getSecretKey(), sitesBytes)
 	if err != nil {
@@ -955,6 +957,59 @@ func (adm *AdminClient) SiteReplicationEdit(ctx context.Context, site PeerInfo,
 	return res, err
 }
 
+// ErrSiteReplicationPeerNotFound is returned when no site of the site
+// replication setup has the requested deployment ID.
+var ErrSiteReplicationPeerNotFound = errors.New("site replication peer not found")
+
+// SiteReplicationEditPeer - updates the peer identified by its deployment ID
+// in place, without removing it from the site replication setup. Empty
+// endpoint and name are kept unchanged, the credentials of the peer are not
+// affected. Returns ErrSiteReplicationPeerNotFound if the deployment ID is not
+// part of the setup.
+func (adm *AdminClient) SiteReplicationEditPeer(ctx context.Context, peer PeerInfo) error {
+	if peer.DeploymentID == "" {
+		return ErrInvalidArgument("peer deployment ID cannot be empty")
+	}
+	info, err := adm.SiteReplicationInfo(ctx)
+	if err != nil {
+		return err
+	}
+	var existing *PeerInfo
+	for i := range info.Sites {
+		if info.Sites[i].DeploymentID == peer.DeploymentID {
+			existing = &info.Sites[i]
+			break
+		}
+	}
+	if existing == nil {
+		return fmt.Errorf("%w: %s", ErrSiteReplicationPeerNotFound, peer.DeploymentID)
+	}
+	if peer.Endpoint == "" {
+		peer.Endpoint = existing.Endpoint
+	}
+	if peer.Name == "" {
+		peer.Name = existing.Name
+	}
+
+	res, err := adm.SiteReplicationEdit(ctx, peer, SREditOptions{})
+	if err != nil {
+		return err
+	}
+	if !res.Success {
+		return fmt.Errorf("unable to edit site replication peer %s: %s %s", peer.DeploymentID, res.Status, res.ErrDetail)
+	}
+	return nil
+}
+
+// SiteReplicationEditEndpoint - updates only the endpoint of the peer
+// identified by its deployment ID, see SiteReplicationEditPeer.
+func (adm *AdminClient) SiteReplicationEditEndpoint(ctx context.Context, deploymentID, endpoint string) error {
+	if endpoint == "" {
+		return ErrInvalidArgument("peer endpoint cannot be empty")
+	}
+	return adm.SiteReplicationEditPeer(ctx, PeerInfo{DeploymentID: deploymentID, Endpoint: endpoint})
+}
+
 // SRPeerEdit - used only by minio server to update peer endpoint
 // for a server already in the site replication setup
 func (adm *AdminClient) SRPeerEdit(ctx context.Context, pi PeerInfo) error {
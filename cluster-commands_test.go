@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestSiteReplicationEditEndpoint(t *testing.T) {
+	info := SiteReplicationInfo{
+		Enabled: true,
+		Sites: []PeerInfo{
+			{Name: "site-a", Endpoint: "https://a.example.com", DeploymentID: "dep-1"},
+			{Name: "site-b", Endpoint: "https://b.example.com", DeploymentID: "dep-2"},
+		},
+	}
+	var edited *PeerInfo
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/site-replication/info":
+			json.NewEncoder(w).Encode(info)
+		case libraryAdminURLPrefix + adminAPIPrefix + "/site-replication/edit":
+			data, err := DecryptData(testSecretKey, r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			edited = &PeerInfo{}
+			if err = json.Unmarshal(data, edited); err != nil {
+				t.Error(err)
+			}
+			json.NewEncoder(w).Encode(ReplicateEditStatus{Success: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ctx := context.Background()
+	if err := adm.SiteReplicationEditEndpoint(ctx, "dep-2", "https://lb.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if edited == nil || edited.Endpoint != "https://lb.example.com" || edited.Name != "site-b" || edited.DeploymentID != "dep-2" {
+		t.Errorf("Unexpected edited peer %+v", edited)
+	}
+
+	edited = nil
+	err := adm.SiteReplicationEditEndpoint(ctx, "dep-3", "https://lb.example.com")
+	if !errors.Is(err, ErrSiteReplicationPeerNotFound) {
+		t.Errorf("Expected ErrSiteReplicationPeerNotFound, got %v", err)
+	}
+	if edited != nil {
+		t.Error("Expected no edit of an unknown peer")
+	}
+}
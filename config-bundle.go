@@ -0,0 +1,230 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ConfigBundleVersion is the version of the config bundle format
+	// written by ExportConfig.
+	ConfigBundleVersion = "1"
+
+	configBundleManifest = "manifest.json"
+	configBundleExt      = ".conf"
+
+	// maxConfigBundleSize limits the size of a config bundle read by
+	// ImportConfig, both compressed and once all of its files are
+	// uncompressed.
+	maxConfigBundleSize = 4 << 20
+)
+
+// ErrInvalidConfigBundle is returned by ImportConfig for a malformed config bundle.
+var ErrInvalidConfigBundle = errors.New("invalid config bundle")
+
+// ConfigBundleManifest describes the content of a config bundle.
+type ConfigBundleManifest struct {
+	Version    string    `json:"version"`
+	CreatedAt  time.Time `json:"createdAt"`
+	SubSystems []string  `json:"subSystems"`
+}
+
+// ExportConfig - returns the server config as a zip archive holding one
+// file per config subsystem, along with a manifest.json describing the
+// bundle. The bundle can be restored with ImportConfig.
+func (adm *AdminClient) ExportConfig(ctx context.Context) (io.ReadCloser, error) {
+	config, err := adm.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subSysLines := make(map[string][]string)
+	for _, line := range strings.Split(string(config), KvNewline) {
+		line = strings.TrimSpace(line)
+		if line == "" || isCommentLine(line) {
+			continue
+		}
+		subSys, _ := getConfigLineSubSystemAndTarget(line)
+		subSysLines[subSys] = append(subSysLines[subSys], line)
+	}
+
+	manifest := ConfigBundleManifest{
+		Version:    ConfigBundleVersion,
+		CreatedAt:  time.Now().UTC(),
+		SubSystems: make([]string, 0, len(subSysLines)),
+	}
+	for subSys := range subSysLines {
+		manifest.SubSystems = append(manifest.SubSystems, subSys)
+	}
+	sort.Strings(manifest.SubSystems)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(configBundleManifest)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.NewEncoder(w).Encode(manifest); err != nil {
+		return nil, err
+	}
+	for _, subSys := range manifest.SubSystems {
+		w, err := zw.Create(subSys + configBundleExt)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(w, strings.Join(subSysLines[subSys], KvNewline)+KvNewline); err != nil {
+			return nil, err
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(&buf), nil
+}
+
+// ImportConfig - restores the server config from a zip archive created by
+// ExportConfig. All the subsystem files are validated before anything is
+// applied, and they are then applied in a single SetConfig call, so either
+// the whole bundle is applied or none of it.
+func (adm *AdminClient) ImportConfig(ctx context.Context, r io.Reader) error {
+	config, err := readConfigBundle(r)
+	if err != nil {
+		return err
+	}
+	return adm.SetConfig(ctx, bytes.NewReader(config))
+}
+
+// readConfigBundle - validates the config bundle and returns the config
+// lines of all of its subsystems.
+func readConfigBundle(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxConfigBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxConfigBundleSize {
+		return nil, bytes.ErrTooLarge
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfigBundle, err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	mf, ok := files[configBundleManifest]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidConfigBundle, configBundleManifest)
+	}
+	remaining := int64(maxConfigBundleSize)
+	var manifest ConfigBundleManifest
+	if err = readConfigBundleFile(mf, &remaining, func(b []byte) error { return json.Unmarshal(b, &manifest) }); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfigBundle, configBundleManifest, err)
+	}
+	if manifest.Version != ConfigBundleVersion {
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidConfigBundle, manifest.Version)
+	}
+
+	var config bytes.Buffer
+	listed := make(map[string]bool, len(manifest.SubSystems))
+	for _, subSys := range manifest.SubSystems {
+		name := subSys + configBundleExt
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidConfigBundle, name)
+		}
+		err = readConfigBundleFile(f, &remaining, func(b []byte) error {
+			return validateConfigBundleFile(subSys, string(b), &config)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfigBundle, name, err)
+		}
+		listed[name] = true
+	}
+	for name := range files {
+		if path.Ext(name) == configBundleExt && !listed[name] {
+			return nil, fmt.Errorf("%w: %s is not listed in %s", ErrInvalidConfigBundle, name, configBundleManifest)
+		}
+	}
+	return config.Bytes(), nil
+}
+
+// readConfigBundleFile - uncompresses f and passes its content to fn. At
+// most remaining bytes are read, remaining is reduced by the size of f so
+// that the uncompressed size of the whole bundle stays bounded.
+func readConfigBundleFile(f *zip.File, remaining *int64, fn func(b []byte) error) error {
+	if f.UncompressedSize64 > uint64(*remaining) {
+		return bytes.ErrTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	// The header may lie about the uncompressed size.
+	b, err := ioutil.ReadAll(io.LimitReader(rc, *remaining+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(b)) > *remaining {
+		return bytes.ErrTooLarge
+	}
+	*remaining -= int64(len(b))
+	return fn(b)
+}
+
+// validateConfigBundleFile - checks that every config line of the file
+// belongs to subSys and parses, valid lines are written to w.
+func validateConfigBundleFile(subSys, content string, w io.Writer) error {
+	if !SubSystems.Contains(subSys) {
+		return fmt.Errorf("unknown config subsystem %s", subSys)
+	}
+	for _, line := range strings.Split(content, KvNewline) {
+		line = strings.TrimSpace(line)
+		if line == "" || isCommentLine(line) {
+			continue
+		}
+		c, err := parseConfigLine(line)
+		if err != nil {
+			return err
+		}
+		if c.SubSystem != subSys {
+			return fmt.Errorf("unexpected config subsystem %s", c.SubSystem)
+		}
+		if _, err = io.WriteString(w, line+KvNewline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
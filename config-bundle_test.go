@@ -0,0 +1,153 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestConfigBundle(t *testing.T) {
+	const serverConfig = `# MINIO_API_REQUESTS_MAX=1000
+api requests_max=1000 cluster_deadline=10s
+site name=dc1 region=us-east-1
+notify_webhook:primary endpoint=http://localhost:8080 queue_limit=0
+notify_webhook:secondary endpoint=http://localhost:8081 queue_limit=0
+`
+	var applied []byte
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			data, err := EncryptData(testSecretKey, []byte(serverConfig))
+			if err != nil {
+				t.Error(err)
+			}
+			w.Write(data)
+		case http.MethodPut:
+			data, err := DecryptData(testSecretKey, r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			applied = data
+		}
+	})
+
+	ctx := context.Background()
+	rc, err := adm.ExportConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	var bundle bytes.Buffer
+	if _, err = bundle.ReadFrom(rc); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(bundle.Bytes()), int64(bundle.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	expectedNames := []string{"manifest.json", "api.conf", "notify_webhook.conf", "site.conf"}
+	if len(names) != len(expectedNames) {
+		t.Fatalf("Expected files %v, got %v", expectedNames, names)
+	}
+	for i := range names {
+		if names[i] != expectedNames[i] {
+			t.Errorf("Expected files %v, got %v", expectedNames, names)
+			break
+		}
+	}
+
+	if err = adm.ImportConfig(ctx, bytes.NewReader(bundle.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	expected := `api requests_max=1000 cluster_deadline=10s
+notify_webhook:primary endpoint=http://localhost:8080 queue_limit=0
+notify_webhook:secondary endpoint=http://localhost:8081 queue_limit=0
+site name=dc1 region=us-east-1
+`
+	if string(applied) != expected {
+		t.Errorf("Expected applied config %q, got %q", expected, applied)
+	}
+
+	// A single invalid subsystem must prevent the whole import.
+	var invalid bytes.Buffer
+	zw := zip.NewWriter(&invalid)
+	for _, f := range zr.File {
+		w, err := zw.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name == "site.conf" {
+			w.Write([]byte("site name\n"))
+			continue
+		}
+		remaining := int64(maxConfigBundleSize)
+		if err = readConfigBundleFile(f, &remaining, func(b []byte) error { _, err := w.Write(b); return err }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	applied = nil
+	if err = adm.ImportConfig(ctx, &invalid); !errors.Is(err, ErrInvalidConfigBundle) {
+		t.Errorf("Expected ErrInvalidConfigBundle, got %v", err)
+	}
+	if applied != nil {
+		t.Errorf("Expected nothing to be applied, got %q", applied)
+	}
+}
+
+func TestConfigBundleUncompressedLimit(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	var bomb bytes.Buffer
+	zw := zip.NewWriter(&bomb)
+	w, err := zw.Create(configBundleManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(`{"version":"` + ConfigBundleVersion + `","subSystems":["api"]}`))
+	if w, err = zw.Create("api" + configBundleExt); err != nil {
+		t.Fatal(err)
+	}
+	w.Write(bytes.Repeat([]byte("\n"), maxConfigBundleSize+1))
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if bomb.Len() > maxConfigBundleSize {
+		t.Fatalf("Expected a small compressed bundle, got %d bytes", bomb.Len())
+	}
+
+	if err = adm.ImportConfig(context.Background(), &bomb); !errors.Is(err, ErrInvalidConfigBundle) {
+		t.Errorf("Expected ErrInvalidConfigBundle, got %v", err)
+	}
+}
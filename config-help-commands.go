@@ -22,6 +22,7 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 )
@@ -42,6 +43,11 @@ type HelpKV struct {
 	Optional        bool   `json:"optional"`
 	Type            string `json:"type"`
 	MultipleTargets bool   `json:"multipleTargets"`
+
+	// Sensitive and Secret mark keys whose value must not be
+	// displayed. Only reported by newer servers.
+	Sensitive bool `json:"sensitive,omitempty"`
+	Secret    bool `json:"secret,omitempty"`
 }
 
 // HelpKVS - implement order of keys help messages.
@@ -58,6 +64,35 @@ func (h Help) Keys() []string {
 
 // HelpConfigKV - return help for a given sub-system.
 func (adm *AdminClient) HelpConfigKV(ctx context.Context, subSys, key string, envOnly bool) (Help, error) {
+	resp, err := adm.helpConfigKV(ctx, subSys, key, envOnly)
+	if err != nil {
+		return Help{}, err
+	}
+	defer closeResponse(resp)
+
+	help := Help{}
+	d := json.NewDecoder(resp.Body)
+	d.DisallowUnknownFields()
+	if err = d.Decode(&help); err != nil {
+		return help, err
+	}
+
+	return help, nil
+}
+
+// HelpConfigKVRaw - return help for a given sub-system as sent by the server,
+// for callers decoding fields not known to Help yet.
+func (adm *AdminClient) HelpConfigKVRaw(ctx context.Context, subSys, key string, envOnly bool) ([]byte, error) {
+	resp, err := adm.helpConfigKV(ctx, subSys, key, envOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponse(resp)
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (adm *AdminClient) helpConfigKV(ctx context.Context, subSys, key string, envOnly bool) (*http.Response, error) {
 	v := url.Values{}
 	v.Set("subSys", subSys)
 	v.Set("key", key)
@@ -73,20 +108,13 @@ func (adm *AdminClient) HelpConfigKV(ctx context.Context, subSys, key string, en
 	// Execute GET on /minio/admin/v3/help-config-kv
 	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
 	if err != nil {
-		return Help{}, err
+		closeResponse(resp)
+		return nil, err
 	}
-	defer closeResponse(resp)
 
 	if resp.StatusCode != http.StatusOK {
-		return Help{}, httpRespToErrorResponse(resp)
+		return nil, httpRespToErrorResponse(resp)
 	}
 
-	help := Help{}
-	d := json.NewDecoder(resp.Body)
-	d.DisallowUnknownFields()
-	if err = d.Decode(&help); err != nil {
-		return help, err
-	}
-
-	return help, nil
+	return resp, nil
 }
@@ -0,0 +1,72 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestHelpConfigKV(t *testing.T) {
+	const apiHelp = `{"subSys":"api","description":"manage global HTTP API call specific features","multipleTargets":false,"keysHelp":[` +
+		`{"key":"requests_max","description":"set the maximum number of concurrent requests","optional":true,"type":"number","multipleTargets":false},` +
+		`{"key":"cors_allow_origin","description":"set comma separated list of origins allowed for CORS requests","optional":true,"type":"csv","multipleTargets":false}]}`
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("subSys") != "api" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(apiHelp))
+	})
+
+	ctx := context.Background()
+	help, err := adm.HelpConfigKV(ctx, "api", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if help.SubSys != "api" || help.MultipleTargets {
+		t.Errorf("Unexpected help %+v", help)
+	}
+	expected := HelpKVS{
+		{Key: "requests_max", Description: "set the maximum number of concurrent requests", Optional: true, Type: "number"},
+		{Key: "cors_allow_origin", Description: "set comma separated list of origins allowed for CORS requests", Optional: true, Type: "csv"},
+	}
+	if len(help.KeysHelp) != len(expected) {
+		t.Fatalf("Expected %d keys, got %d", len(expected), len(help.KeysHelp))
+	}
+	for i, kh := range help.KeysHelp {
+		if kh != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], kh)
+		}
+	}
+
+	raw, err := adm.HelpConfigKVRaw(ctx, "api", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != apiHelp {
+		t.Errorf("Expected raw help %s, got %s", apiHelp, raw)
+	}
+
+	if _, err = adm.HelpConfigKV(ctx, "unknown", "", false); err == nil {
+		t.Error("Expected an error for an unknown subsystem")
+	}
+}
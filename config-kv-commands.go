@@ -21,8 +21,10 @@ package madmin
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // DelConfigKV - delete key from server config.
@@ -52,6 +54,21 @@ func (adm *AdminClient) DelConfigKV(ctx context.Context, k string) (restart bool
 	return resp.Header.Get(ConfigAppliedHeader) != ConfigAppliedTrue, nil
 }
 
+// DelConfigKVSubKey - resets subKey of the config key, a subsystem with an
+// optional target such as `notify_webhook:primary`, to its default value.
+// The other parameters of key are left unchanged.
+func (adm *AdminClient) DelConfigKVSubKey(ctx context.Context, key, subKey string) error {
+	if key == "" || subKey == "" {
+		return ErrInvalidArgument("config key and sub-key cannot be empty")
+	}
+	if HasSpace(key) || HasSpace(subKey) || strings.Contains(subKey, KvSeparator) {
+		return ErrInvalidArgument(fmt.Sprintf("invalid config sub-key %s %s", key, subKey))
+	}
+	// The server only resets the listed parameters when any are given.
+	_, err := adm.DelConfigKV(ctx, key+KvSpaceSeparator+subKey)
+	return err
+}
+
 const (
 	// ConfigAppliedHeader is the header indicating whether the config was applied without requiring a restart.
 	ConfigAppliedHeader = "x-minio-config-applied"
@@ -60,11 +77,27 @@ const (
 	ConfigAppliedTrue = "true"
 )
 
+// SetConfigResult - result of a SetConfigKVWithResult call
+type SetConfigResult struct {
+	// RestartRequired is true if the server could not apply the
+	// config dynamically and needs a restart for it to take effect.
+	RestartRequired bool `json:"restartRequired"`
+	// SubSystems lists the config subsystems changed by the call.
+	SubSystems []string `json:"subSystems"`
+}
+
 // SetConfigKV - set key value config to server.
 func (adm *AdminClient) SetConfigKV(ctx context.Context, kv string) (restart bool, err error) {
+	res, err := adm.SetConfigKVWithResult(ctx, kv)
+	return res.RestartRequired, err
+}
+
+// SetConfigKVWithResult - set key value config to server, returns whether
+// a server restart is required to apply it along with the changed subsystems.
+func (adm *AdminClient) SetConfigKVWithResult(ctx context.Context, kv string) (SetConfigResult, error) {
 	econfigBytes, err := EncryptData(adm.getSecretKey(), []byte(kv))
 	if err != nil {
-		return false, err
+		return SetConfigResult{}, err
 	}
 
 	reqData := requestData{
@@ -77,14 +110,35 @@ func (adm *AdminClient) SetConfigKV(ctx context.Context, kv string) (restart boo
 
 	defer closeResponse(resp)
 	if err != nil {
-		return false, err
+		return SetConfigResult{}, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return false, httpRespToErrorResponse(resp)
+		return SetConfigResult{}, httpRespToErrorResponse(resp)
 	}
 
-	return resp.Header.Get(ConfigAppliedHeader) != ConfigAppliedTrue, nil
+	return SetConfigResult{
+		RestartRequired: resp.Header.Get(ConfigAppliedHeader) != ConfigAppliedTrue,
+		SubSystems:      configKVSubSystems(kv),
+	}, nil
+}
+
+// configKVSubSystems - returns the distinct subsystems of the config lines in kv.
+func configKVSubSystems(kv string) []string {
+	var subSystems []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(kv, KvNewline) {
+		line = strings.TrimSpace(line)
+		if line == "" || isCommentLine(line) {
+			continue
+		}
+		subSys, _ := getConfigLineSubSystemAndTarget(line)
+		if !seen[subSys] {
+			seen[subSys] = true
+			subSystems = append(subSystems, subSys)
+		}
+	}
+	return subSystems
 }
 
 // GetConfigKV - returns the key, value of the requested key, incoming data is encrypted.
@@ -144,3 +198,78 @@ func (adm *AdminClient) GetConfigKVWithOptions(ctx context.Context, key string,
 
 	return DecryptData(adm.getSecretKey(), resp.Body)
 }
+
+// ConfigKVDefault - a config parameter along with whether it has its
+// default value. Value is masked with RedactedValue for secret keys.
+//
+// The server reports neither the default values, the help of a key does
+// not include it, nor which parameters are set: the config it returns
+// has the default value filled in for every parameter not set. IsDefault
+// is therefore left nil, meaning unknown.
+type ConfigKVDefault struct {
+	Key         string       `json:"key"`
+	Value       string       `json:"value"`
+	IsDefault   *bool        `json:"isDefault,omitempty"`
+	IsSecret    bool         `json:"isSecret,omitempty"`
+	EnvOverride *EnvOverride `json:"envOverride,omitempty"`
+}
+
+// ConfigKVWithDefaults - the config of a subsystem target with every
+// config parameter listed in its help.
+type ConfigKVWithDefaults struct {
+	SubSystem string            `json:"subSystem"`
+	Target    string            `json:"target,omitempty"`
+	KV        []ConfigKVDefault `json:"kv"`
+}
+
+// GetConfigKVWithDefaults - returns the config of key, a subsystem with an
+// optional target such as `notify_webhook:primary`, with every parameter
+// listed by HelpConfigKV, secret parameters being masked. See
+// ConfigKVDefault on why whether a value is the default is not known.
+func (adm *AdminClient) GetConfigKVWithDefaults(ctx context.Context, key string) (ConfigKVWithDefaults, error) {
+	subSys := strings.SplitN(key, SubSystemSeparator, 2)[0]
+	help, err := adm.HelpConfigKV(ctx, subSys, "", false)
+	if err != nil {
+		return ConfigKVWithDefaults{}, err
+	}
+	buf, err := adm.GetConfigKV(ctx, key)
+	if err != nil {
+		return ConfigKVWithDefaults{}, err
+	}
+	cfgs, err := ParseServerConfigOutput(string(buf))
+	if err != nil {
+		return ConfigKVWithDefaults{}, err
+	}
+	if len(cfgs) == 0 {
+		return ConfigKVWithDefaults{}, fmt.Errorf("no config found for %s", key)
+	}
+	return configKVWithDefaults(cfgs[0], help), nil
+}
+
+func configKVWithDefaults(cfg SubsysConfig, help Help) ConfigKVWithDefaults {
+	res := ConfigKVWithDefaults{
+		SubSystem: cfg.SubSystem,
+		Target:    cfg.Target,
+		KV:        make([]ConfigKVDefault, 0, len(help.KeysHelp)),
+	}
+	for _, kh := range help.KeysHelp {
+		kv := ConfigKVDefault{
+			Key:      kh.Key,
+			IsSecret: kh.Secret || kh.Sensitive,
+		}
+		if idx, ok := cfg.kvIndexMap[kh.Key]; ok {
+			kv.Value = cfg.KV[idx].Value
+			kv.EnvOverride = cfg.KV[idx].EnvOverride
+		}
+		if kv.IsSecret {
+			if kv.Value != "" {
+				kv.Value = RedactedValue
+			}
+			if kv.EnvOverride != nil {
+				kv.EnvOverride = &EnvOverride{Name: kv.EnvOverride.Name, Value: RedactedValue}
+			}
+		}
+		res.KV = append(res.KV, kv)
+	}
+	return res
+}
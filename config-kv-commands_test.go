@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetConfigKVWithDefaults(t *testing.T) {
+	help := Help{
+		SubSys: "notify_webhook",
+		KeysHelp: HelpKVS{
+			{Key: "endpoint", Type: "url"},
+			{Key: "queue_limit", Type: "number"},
+			{Key: "queue_dir", Type: "path", Optional: true},
+			{Key: "auth_token", Type: "string", Secret: true},
+		},
+	}
+	const config = "notify_webhook:primary endpoint=http://localhost:8080 queue_limit=100000 auth_token=s3cr3t\n"
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/help-config-kv":
+			json.NewEncoder(w).Encode(help)
+		case libraryAdminURLPrefix + adminAPIPrefix + "/get-config-kv":
+			data, err := EncryptData(testSecretKey, []byte(config))
+			if err != nil {
+				t.Error(err)
+			}
+			w.Write(data)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	cfg, err := adm.GetConfigKVWithDefaults(context.Background(), "notify_webhook:primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SubSystem != "notify_webhook" || cfg.Target != "primary" {
+		t.Errorf("Unexpected subsystem %s:%s", cfg.SubSystem, cfg.Target)
+	}
+	expected := []ConfigKVDefault{
+		{Key: "endpoint", Value: "http://localhost:8080"},
+		{Key: "queue_limit", Value: "100000"},
+		{Key: "queue_dir"},
+		{Key: "auth_token", Value: RedactedValue, IsSecret: true},
+	}
+	if len(cfg.KV) != len(expected) {
+		t.Fatalf("Expected %d keys, got %d", len(expected), len(cfg.KV))
+	}
+	for i, kv := range cfg.KV {
+		if !reflect.DeepEqual(kv, expected[i]) {
+			t.Errorf("Expected %+v, got %+v", expected[i], kv)
+		}
+	}
+}
+
+func TestDelConfigKVSubKey(t *testing.T) {
+	cfg, err := parseConfigLine("api requests_max=1000 cluster_deadline=20s cors_allow_origin=*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/del-config-kv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, err := DecryptData(testSecretKey, r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		// Mimic the server, only the listed parameters are reset.
+		fields := strings.Fields(string(data))
+		if len(fields) < 2 || fields[0] != cfg.SubSystem {
+			t.Errorf("Unexpected del-config-kv request %q", data)
+		}
+		var kvs []ConfigKV
+		for _, kv := range cfg.KV {
+			if kv.Key != fields[len(fields)-1] {
+				kvs = append(kvs, kv)
+			}
+		}
+		cfg = SubsysConfig{SubSystem: cfg.SubSystem}
+		for _, kv := range kvs {
+			cfg.AddConfigKV(kv)
+		}
+		w.Header().Set(ConfigAppliedHeader, ConfigAppliedTrue)
+	})
+
+	if err = adm.DelConfigKVSubKey(context.Background(), "api", "cluster_deadline"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Lookup("cluster_deadline"); ok {
+		t.Error("Expected cluster_deadline to be reset")
+	}
+	for _, k := range []string{"requests_max", "cors_allow_origin"} {
+		if _, ok := cfg.Lookup(k); !ok {
+			t.Errorf("Expected sibling sub-key %s to be preserved", k)
+		}
+	}
+
+	if err = adm.DelConfigKVSubKey(context.Background(), "api", "requests_max=1"); err == nil {
+		t.Error("Expected invalid sub-key to be rejected")
+	}
+}
+
+func TestSetConfigKVWithResult(t *testing.T) {
+	// Subsystems the server applies without a restart.
+	dynamic := map[string]bool{"api": true, "scanner": true}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, err := DecryptData(testSecretKey, r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		applied := true
+		for _, subSys := range configKVSubSystems(string(data)) {
+			applied = applied && dynamic[subSys]
+		}
+		if applied {
+			w.Header().Set(ConfigAppliedHeader, ConfigAppliedTrue)
+		}
+	})
+
+	testCases := []struct {
+		kv         string
+		restart    bool
+		subSystems []string
+	}{
+		{kv: "api requests_max=1000", subSystems: []string{"api"}},
+		{kv: "identity_openid config_url=http://idp/.well-known/openid-configuration", restart: true, subSystems: []string{"identity_openid"}},
+		{kv: "api requests_max=1000\nidentity_openid:okta client_id=minio\napi cors_allow_origin=*", restart: true, subSystems: []string{"api", "identity_openid"}},
+	}
+	for i, tc := range testCases {
+		res, err := adm.SetConfigKVWithResult(context.Background(), tc.kv)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if res.RestartRequired != tc.restart {
+			t.Errorf("Test %d: expected RestartRequired=%v", i+1, tc.restart)
+		}
+		if strings.Join(res.SubSystems, ",") != strings.Join(tc.subSystems, ",") {
+			t.Errorf("Test %d: expected subsystems %v, got %v", i+1, tc.subSystems, res.SubSystems)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"sort"
+	"time"
+)
+
+// DataUsageScannerInfo - progress of the scanner cycle refreshing the data usage
+type DataUsageScannerInfo struct {
+	// Cycle is the current scanner cycle.
+	Cycle uint64 `json:"cycle"`
+	// CycleStarted is the start time of the current cycle.
+	CycleStarted time.Time `json:"cycleStarted,omitempty"`
+	// ObjectsScanned is the number of objects scanned in the current cycle.
+	ObjectsScanned uint64 `json:"objectsScanned"`
+	// Running is true while the scanner is scanning.
+	Running bool `json:"running"`
+}
+
+// ScanCycle - returns the current scanner cycle, zero if not reported.
+func (d DataUsageInfo) ScanCycle() uint64 {
+	if d.Scanner == nil {
+		return 0
+	}
+	return d.Scanner.Cycle
+}
+
+// ObjectsScannedThisCycle - returns the number of objects scanned in the
+// current scanner cycle, zero if not reported.
+func (d DataUsageInfo) ObjectsScannedThisCycle() uint64 {
+	if d.Scanner == nil {
+		return 0
+	}
+	return d.Scanner.ObjectsScanned
+}
+
+// ScanInProgress - returns true while a scan is running, in which case the
+// usage numbers are provisional. False if the server does not report it.
+func (d DataUsageInfo) ScanInProgress() bool {
+	return d.Scanner != nil && d.Scanner.Running
+}
+
+// BucketUsageGrowth - usage change of a bucket between two data usage snapshots
+type BucketUsageGrowth struct {
+	Bucket       string  `json:"bucket"`
+	BytesDelta   int64   `json:"bytesDelta"`
+	ObjectsDelta int64   `json:"objectsDelta"`
+	BytesPerDay  float64 `json:"bytesPerDay"`
+	// Created is true if the bucket is only present in the current
+	// snapshot, Deleted if it is only present in the previous one.
+	Created bool `json:"created,omitempty"`
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// DataUsageGrowthReport - usage change between two data usage snapshots
+type DataUsageGrowthReport struct {
+	Interval     time.Duration       `json:"interval"`
+	BytesDelta   int64               `json:"bytesDelta"`
+	ObjectsDelta int64               `json:"objectsDelta"`
+	BytesPerDay  float64             `json:"bytesPerDay"`
+	Buckets      []BucketUsageGrowth `json:"buckets"`
+}
+
+// DataUsageGrowth - computes the usage change from prev to cur, per bucket and
+// in total. Rates are in bytes per day over the time between the LastUpdate
+// of both snapshots, they are zero if both snapshots have the same LastUpdate.
+// Buckets are sorted by name.
+func DataUsageGrowth(prev, cur DataUsageInfo) DataUsageGrowthReport {
+	report := DataUsageGrowthReport{
+		Interval:     cur.LastUpdate.Sub(prev.LastUpdate),
+		BytesDelta:   int64(cur.ObjectsTotalSize) - int64(prev.ObjectsTotalSize),
+		ObjectsDelta: int64(cur.ObjectsTotalCount) - int64(prev.ObjectsTotalCount),
+	}
+	perDay := func(delta int64) float64 {
+		if report.Interval == 0 {
+			return 0
+		}
+		return float64(delta) / (float64(report.Interval) / float64(24*time.Hour))
+	}
+	report.BytesPerDay = perDay(report.BytesDelta)
+
+	for bucket, c := range cur.BucketsUsage {
+		p, ok := prev.BucketsUsage[bucket]
+		g := BucketUsageGrowth{
+			Bucket:       bucket,
+			BytesDelta:   int64(c.Size) - int64(p.Size),
+			ObjectsDelta: int64(c.ObjectsCount) - int64(p.ObjectsCount),
+			Created:      !ok,
+		}
+		g.BytesPerDay = perDay(g.BytesDelta)
+		report.Buckets = append(report.Buckets, g)
+	}
+	for bucket, p := range prev.BucketsUsage {
+		if _, ok := cur.BucketsUsage[bucket]; ok {
+			continue
+		}
+		g := BucketUsageGrowth{
+			Bucket:       bucket,
+			BytesDelta:   -int64(p.Size),
+			ObjectsDelta: -int64(p.ObjectsCount),
+			Deleted:      true,
+		}
+		g.BytesPerDay = perDay(g.BytesDelta)
+		report.Buckets = append(report.Buckets, g)
+	}
+	sort.Slice(report.Buckets, func(i, j int) bool {
+		return report.Buckets[i].Bucket < report.Buckets[j].Bucket
+	})
+	return report
+}
+
+// BucketUsageEntry - usage of a single bucket
+type BucketUsageEntry struct {
+	Name string `json:"name"`
+	BucketUsageInfo
+}
+
+// SortedBuckets - returns the usage of all buckets sorted by size
+// descending, buckets of the same size are sorted by name.
+func (d DataUsageInfo) SortedBuckets() []BucketUsageEntry {
+	entries := make([]BucketUsageEntry, 0, len(d.BucketsUsage))
+	for name, usage := range d.BucketsUsage {
+		entries = append(entries, BucketUsageEntry{Name: name, BucketUsageInfo: usage})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Size != entries[j].Size {
+			return entries[i].Size > entries[j].Size
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// TopBucketsBySize - returns the usage of the n largest buckets, as sorted
+// by SortedBuckets. All buckets are returned if n is not positive.
+func TopBucketsBySize(info DataUsageInfo, n int) []BucketUsageEntry {
+	entries := info.SortedBuckets()
+	if n > 0 && n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDataUsageGrowth(t *testing.T) {
+	now := time.Now().UTC()
+	prev := DataUsageInfo{
+		LastUpdate:        now.Add(-48 * time.Hour),
+		ObjectsTotalSize:  3000,
+		ObjectsTotalCount: 30,
+		BucketsUsage: map[string]BucketUsageInfo{
+			"kept":    {Size: 1000, ObjectsCount: 10},
+			"removed": {Size: 2000, ObjectsCount: 20},
+		},
+	}
+	cur := DataUsageInfo{
+		LastUpdate:        now,
+		ObjectsTotalSize:  5000,
+		ObjectsTotalCount: 25,
+		BucketsUsage: map[string]BucketUsageInfo{
+			"kept":  {Size: 3000, ObjectsCount: 15},
+			"added": {Size: 2000, ObjectsCount: 10},
+		},
+	}
+
+	report := DataUsageGrowth(prev, cur)
+	if report.Interval != 48*time.Hour || report.BytesDelta != 2000 || report.ObjectsDelta != -5 || report.BytesPerDay != 1000 {
+		t.Errorf("Unexpected totals %+v", report)
+	}
+	expected := []BucketUsageGrowth{
+		{Bucket: "added", BytesDelta: 2000, ObjectsDelta: 10, BytesPerDay: 1000, Created: true},
+		{Bucket: "kept", BytesDelta: 2000, ObjectsDelta: 5, BytesPerDay: 1000},
+		{Bucket: "removed", BytesDelta: -2000, ObjectsDelta: -20, BytesPerDay: -1000, Deleted: true},
+	}
+	if !reflect.DeepEqual(report.Buckets, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, report.Buckets)
+	}
+
+	prev.LastUpdate = cur.LastUpdate
+	report = DataUsageGrowth(prev, cur)
+	if report.BytesPerDay != 0 || report.Buckets[0].BytesPerDay != 0 {
+		t.Errorf("Expected zero rates for identical timestamps, got %+v", report)
+	}
+}
+
+func TestSortedBuckets(t *testing.T) {
+	info := DataUsageInfo{
+		BucketsUsage: map[string]BucketUsageInfo{
+			"small":   {Size: 10},
+			"beta":    {Size: 100},
+			"alpha":   {Size: 100},
+			"largest": {Size: 1000},
+		},
+	}
+	var names []string
+	for _, e := range info.SortedBuckets() {
+		names = append(names, e.Name)
+	}
+	expected := []string{"largest", "alpha", "beta", "small"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	top := TopBucketsBySize(info, 2)
+	if len(top) != 2 || top[0].Name != "largest" || top[1].Name != "alpha" || top[1].Size != 100 {
+		t.Errorf("Unexpected top buckets %+v", top)
+	}
+	if len(TopBucketsBySize(info, 10)) != 4 {
+		t.Error("Expected all buckets when n exceeds the bucket count")
+	}
+	if len(info.BucketsUsage) != 4 {
+		t.Error("Expected the buckets usage map to be untouched")
+	}
+}
+
+func TestDataUsageInfoScanner(t *testing.T) {
+	var d DataUsageInfo
+	if err := json.Unmarshal([]byte(`{"lastUpdate":"2024-01-01T00:00:00Z","objectsCount":10}`), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.ScanCycle() != 0 || d.ObjectsScannedThisCycle() != 0 || d.ScanInProgress() {
+		t.Errorf("Expected no scanner info, got %+v", d.Scanner)
+	}
+
+	data := []byte(`{"lastUpdate":"2024-01-01T00:00:00Z","scanner":{"cycle":42,"cycleStarted":"2024-01-01T00:00:00Z","objectsScanned":1500,"running":true}}`)
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.ScanCycle() != 42 || d.ObjectsScannedThisCycle() != 1500 || !d.ScanInProgress() {
+		t.Errorf("Unexpected scanner info %+v", d.Scanner)
+	}
+}
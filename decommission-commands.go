@@ -22,11 +22,25 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
 	"time"
 )
 
+// minDecommissionPollInterval is the minimum interval between
+// two pool status requests of WaitForDecommission.
+const minDecommissionPollInterval = time.Second
+
+var (
+	// ErrDecommissionFailed is returned by WaitForDecommission if the decommission failed.
+	ErrDecommissionFailed = errors.New("pool decommission failed")
+	// ErrDecommissionCanceled is returned by WaitForDecommission if the decommission was canceled.
+	ErrDecommissionCanceled = errors.New("pool decommission canceled")
+	// ErrNoDecommission is returned by WaitForDecommission if the pool is not being decommissioned.
+	ErrNoDecommission = errors.New("pool is not being decommissioned")
+)
+
 // PoolDecommissionInfo currently draining information
 type PoolDecommissionInfo struct {
 	StartTime   time.Time `json:"startTime"`
@@ -43,6 +57,39 @@ type PoolDecommissionInfo struct {
 	BytesFailed               int64 `json:"bytesDecommissionedFailed"`
 }
 
+// PercentComplete returns the share of the data of the pool at the start of
+// the decommission that has been moved so far, between 0 and 100. StartSize
+// and CurrentSize are the free space of the pool at the start and now. It
+// returns 0 if the decommission hasn't started or the pool size is unknown.
+func (d PoolDecommissionInfo) PercentComplete() float64 {
+	toMove := d.TotalSize - d.StartSize
+	if d.StartTime.IsZero() || d.TotalSize <= 0 || toMove <= 0 {
+		return 0
+	}
+	if d.Complete {
+		return 100
+	}
+	pct := float64(d.CurrentSize-d.StartSize) / float64(toMove) * 100
+	switch {
+	case pct < 0:
+		return 0
+	case pct > 100:
+		return 100
+	}
+	return pct
+}
+
+// EstimatedCompletion returns when the decommission is expected to complete,
+// assuming the progress made during elapsed since StartTime continues at the
+// same rate. It returns the zero time if no progress has been made yet.
+func (d PoolDecommissionInfo) EstimatedCompletion(elapsed time.Duration) time.Time {
+	pct := d.PercentComplete()
+	if pct <= 0 {
+		return time.Time{}
+	}
+	return d.StartTime.Add(time.Duration(float64(elapsed) * 100 / pct))
+}
+
 // PoolStatus captures current pool status
 type PoolStatus struct {
 	ID           int                   `json:"id"`
@@ -119,6 +166,44 @@ func (adm *AdminClient) StatusPool(ctx context.Context, pool string) (PoolStatus
 	return info, nil
 }
 
+// WaitForDecommission polls the status of the pool every pollInterval, at
+// least every second, until its decommission completes. progress, if not
+// nil, is called with every status. It returns ErrDecommissionFailed or
+// ErrDecommissionCanceled if the decommission did not complete, and the
+// context error if ctx is done first.
+func (adm *AdminClient) WaitForDecommission(ctx context.Context, pool string, pollInterval time.Duration, progress func(PoolStatus)) error {
+	if pollInterval < minDecommissionPollInterval {
+		pollInterval = minDecommissionPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := adm.StatusPool(ctx, pool)
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(status)
+		}
+		d := status.Decommission
+		switch {
+		case d == nil:
+			return ErrNoDecommission
+		case d.Complete:
+			return nil
+		case d.Failed:
+			return ErrDecommissionFailed
+		case d.Canceled:
+			return ErrDecommissionCanceled
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // ListPoolsStatus returns list of pools currently configured and being used
 // on the cluster.
 func (adm *AdminClient) ListPoolsStatus(ctx context.Context) ([]PoolStatus, error) {
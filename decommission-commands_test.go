@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPoolDecommissionProgress(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		info     PoolDecommissionInfo
+		pct      float64
+		expected time.Time
+	}{
+		// Not started yet.
+		{info: PoolDecommissionInfo{TotalSize: 1000, StartSize: 200, CurrentSize: 200}},
+		// Unknown pool size.
+		{info: PoolDecommissionInfo{StartTime: start}},
+		// Nothing moved yet.
+		{info: PoolDecommissionInfo{StartTime: start, TotalSize: 1000, StartSize: 200, CurrentSize: 200}},
+		// 400 of the 800 used bytes moved.
+		{info: PoolDecommissionInfo{StartTime: start, TotalSize: 1000, StartSize: 200, CurrentSize: 600}, pct: 50, expected: start.Add(2 * time.Hour)},
+		// All used bytes moved.
+		{info: PoolDecommissionInfo{StartTime: start, TotalSize: 1000, StartSize: 200, CurrentSize: 1000}, pct: 100, expected: start.Add(time.Hour)},
+		{info: PoolDecommissionInfo{StartTime: start, TotalSize: 1000, StartSize: 200, CurrentSize: 900, Complete: true}, pct: 100, expected: start.Add(time.Hour)},
+	}
+	for i, tc := range testCases {
+		if pct := tc.info.PercentComplete(); pct != tc.pct {
+			t.Errorf("Test %d: expected %v%%, got %v%%", i+1, tc.pct, pct)
+		}
+		if eta := tc.info.EstimatedCompletion(time.Hour); !eta.Equal(tc.expected) {
+			t.Errorf("Test %d: expected ETA %v, got %v", i+1, tc.expected, eta)
+		}
+	}
+}
+
+func TestWaitForDecommission(t *testing.T) {
+	testCases := []struct {
+		final    PoolDecommissionInfo
+		expected error
+	}{
+		{final: PoolDecommissionInfo{Complete: true}},
+		{final: PoolDecommissionInfo{Failed: true}, expected: ErrDecommissionFailed},
+		{final: PoolDecommissionInfo{Canceled: true}, expected: ErrDecommissionCanceled},
+	}
+	for i, tc := range testCases {
+		polls := 0
+		final := tc.final
+		adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+			polls++
+			status := PoolStatus{ID: 0, Decommission: &PoolDecommissionInfo{}}
+			if polls == 2 {
+				status.Decommission = &final
+			}
+			json.NewEncoder(w).Encode(status)
+		})
+		var progress []PoolStatus
+		err := adm.WaitForDecommission(context.Background(), "pool", 0, func(s PoolStatus) {
+			progress = append(progress, s)
+		})
+		if !errors.Is(err, tc.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, tc.expected, err)
+		}
+		if len(progress) != 2 {
+			t.Errorf("Test %d: expected 2 progress calls, got %d", i+1, len(progress))
+		}
+	}
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(PoolStatus{Decommission: &PoolDecommissionInfo{}})
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := adm.WaitForDecommission(ctx, "pool", time.Minute, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+}
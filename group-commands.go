@@ -25,6 +25,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
 )
 
@@ -168,3 +169,58 @@ func (adm *AdminClient) SetGroupStatus(ctx context.Context, group string, status
 
 	return nil
 }
+
+// ReconcileGroupMembers - updates the members of a group to match desired,
+// adding and removing only the members that differ. The group is created if
+// it does not exist. The added and removed members are returned sorted.
+func (adm *AdminClient) ReconcileGroupMembers(ctx context.Context, group string, desired []string) (added, removed []string, err error) {
+	var current []string
+	gd, err := adm.GetGroupDescription(ctx, group)
+	if err != nil {
+		if ToErrorResponse(err).Code != "XMinioAdminNoSuchGroup" {
+			return nil, nil, err
+		}
+	} else {
+		current = gd.Members
+	}
+
+	added, removed = diffGroupMembers(current, desired)
+	if len(added) > 0 {
+		if err = adm.UpdateGroupMembers(ctx, GroupAddRemove{Group: group, Members: added}); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(removed) > 0 {
+		if err = adm.UpdateGroupMembers(ctx, GroupAddRemove{Group: group, Members: removed, IsRemove: true}); err != nil {
+			return added, nil, err
+		}
+	}
+	return added, removed, nil
+}
+
+// diffGroupMembers - returns the members of desired missing from current,
+// and the members of current missing from desired.
+func diffGroupMembers(current, desired []string) (added, removed []string) {
+	have := make(map[string]struct{}, len(current))
+	for _, m := range current {
+		have[m] = struct{}{}
+	}
+	want := make(map[string]struct{}, len(desired))
+	for _, m := range desired {
+		if _, ok := want[m]; ok {
+			continue
+		}
+		want[m] = struct{}{}
+		if _, ok := have[m]; !ok {
+			added = append(added, m)
+		}
+	}
+	for m := range have {
+		if _, ok := want[m]; !ok {
+			removed = append(removed, m)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
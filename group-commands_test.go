@@ -0,0 +1,120 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestReconcileGroupMembers(t *testing.T) {
+	testCases := []struct {
+		name        string
+		current     []string
+		noGroup     bool
+		desired     []string
+		wantAdded   []string
+		wantRemoved []string
+		wantUpdates []GroupAddRemove
+	}{
+		{
+			name:        "add and remove",
+			current:     []string{"alice", "bob"},
+			desired:     []string{"carol", "bob"},
+			wantAdded:   []string{"carol"},
+			wantRemoved: []string{"alice"},
+			wantUpdates: []GroupAddRemove{
+				{Group: "devs", Members: []string{"carol"}},
+				{Group: "devs", Members: []string{"alice"}, IsRemove: true},
+			},
+		},
+		{
+			name:    "already in sync",
+			current: []string{"alice", "bob"},
+			desired: []string{"bob", "alice", "alice"},
+		},
+		{
+			name:        "new group",
+			noGroup:     true,
+			desired:     []string{"bob", "alice"},
+			wantAdded:   []string{"alice", "bob"},
+			wantUpdates: []GroupAddRemove{{Group: "devs", Members: []string{"alice", "bob"}}},
+		},
+		{
+			name:        "remove all",
+			current:     []string{"alice"},
+			wantRemoved: []string{"alice"},
+			wantUpdates: []GroupAddRemove{{Group: "devs", Members: []string{"alice"}, IsRemove: true}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var updates []GroupAddRemove
+			adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case libraryAdminURLPrefix + adminAPIPrefix + "/group":
+					if tc.noGroup {
+						w.WriteHeader(http.StatusNotFound)
+						json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioAdminNoSuchGroup", Message: "The specified group does not exist."})
+						return
+					}
+					json.NewEncoder(w).Encode(GroupDesc{Name: r.URL.Query().Get("group"), Members: tc.current})
+				case libraryAdminURLPrefix + adminAPIPrefix + "/update-group-members":
+					var g GroupAddRemove
+					if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+						t.Error(err)
+					}
+					updates = append(updates, g)
+				default:
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+			})
+
+			added, removed, err := adm.ReconcileGroupMembers(context.Background(), "devs", tc.desired)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(added, tc.wantAdded) || !reflect.DeepEqual(removed, tc.wantRemoved) {
+				t.Errorf("Expected added %v removed %v, got added %v removed %v", tc.wantAdded, tc.wantRemoved, added, removed)
+			}
+			if !reflect.DeepEqual(updates, tc.wantUpdates) {
+				t.Errorf("Expected updates %+v, got %+v", tc.wantUpdates, updates)
+			}
+		})
+	}
+}
+
+func TestReconcileGroupMembersError(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/group" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(ErrorResponse{Code: "AccessDenied", Message: "Access Denied."})
+	})
+
+	if _, _, err := adm.ReconcileGroupMembers(context.Background(), "devs", []string{"alice"}); ToErrorResponse(err).Code != "AccessDenied" {
+		t.Fatalf("Expected AccessDenied, got %v", err)
+	}
+}
@@ -22,11 +22,13 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"time"
 )
 
@@ -227,6 +229,71 @@ func (hri *HealResultItem) GetOnlineCounts() (b, a int) {
 	return
 }
 
+// healItemOutcome - outcome of a heal result item
+type healItemOutcome int
+
+const (
+	healItemHealed healItemOutcome = iota
+	healItemFailed
+	healItemSkipped
+	healItemInProgress
+)
+
+// outcome - an item without drives after heal is still in progress, an
+// item with all drives ok before heal was skipped, an item with any drive
+// not ok after heal failed.
+func (hri *HealResultItem) outcome() healItemOutcome {
+	if len(hri.After.Drives) == 0 {
+		return healItemInProgress
+	}
+	if b, _ := hri.GetOnlineCounts(); b == len(hri.Before.Drives) {
+		return healItemSkipped
+	}
+	if _, a := hri.GetOnlineCounts(); a != len(hri.After.Drives) {
+		return healItemFailed
+	}
+	return healItemHealed
+}
+
+// HealSummaryCounts - number of heal result items by outcome
+type HealSummaryCounts struct {
+	Healed     int `json:"healed"`
+	Failed     int `json:"failed"`
+	Skipped    int `json:"skipped"`
+	InProgress int `json:"inProgress"`
+}
+
+// Failures - returns the items which still have drives not ok after
+// heal. Items still in progress are not failures.
+func (h HealTaskStatus) Failures() []HealResultItem {
+	var items []HealResultItem
+	for i := range h.Items {
+		if h.Items[i].outcome() == healItemFailed {
+			items = append(items, h.Items[i])
+		}
+	}
+	return items
+}
+
+// SummaryCounts - returns the number of items healed, failed, skipped
+// because nothing needed healing and still in progress.
+func (h HealTaskStatus) SummaryCounts() HealSummaryCounts {
+	var c HealSummaryCounts
+	for i := range h.Items {
+		switch h.Items[i].outcome() {
+		case healItemHealed:
+			c.Healed++
+		case healItemFailed:
+			c.Failed++
+		case healItemSkipped:
+			c.Skipped++
+		case healItemInProgress:
+			c.InProgress++
+		}
+	}
+	return c
+}
+
 // Heal - API endpoint to start heal and to fetch status
 // forceStart and forceStop are mutually exclusive, you can either
 // set one of them to 'true'. If both are set 'forceStart' will be
@@ -307,6 +374,240 @@ func (adm *AdminClient) Heal(ctx context.Context, bucket, prefix string,
 	return healStart, healTaskStatus, nil
 }
 
+// Heal sequence states reported in HealTaskStatus.Summary
+const (
+	HealTaskNotStarted = "not started"
+	HealTaskRunning    = "running"
+	HealTaskStopped    = "stopped"
+	HealTaskFinished   = "finished"
+)
+
+// ErrHealFailed is returned when a heal sequence stopped on an error.
+var ErrHealFailed = errors.New("heal sequence failed")
+
+// healPollInterval is the interval between two heal status requests.
+var healPollInterval = time.Second
+
+// followHeal - polls the status of the heal sequence of token until it is
+// finished or stopped, calling fn with every status received. The items of
+// all statuses are accumulated in the returned status.
+func (adm *AdminClient) followHeal(ctx context.Context, bucket, prefix, token string, fn func(HealTaskStatus)) (HealTaskStatus, error) {
+	var final HealTaskStatus
+	for {
+		_, status, err := adm.Heal(ctx, bucket, prefix, HealOpts{}, token, false, false)
+		if err != nil {
+			return final, err
+		}
+		items := append(final.Items, status.Items...)
+		final = status
+		final.Items = items
+		if fn != nil {
+			fn(status)
+		}
+		switch status.Summary {
+		case HealTaskFinished:
+			return final, nil
+		case HealTaskStopped:
+			if status.FailureDetail != "" {
+				return final, fmt.Errorf("%w: %s", ErrHealFailed, status.FailureDetail)
+			}
+			return final, nil
+		}
+		select {
+		case <-ctx.Done():
+			return final, ctx.Err()
+		case <-time.After(healPollInterval):
+		}
+	}
+}
+
+// healStopTimeout bounds the request stopping a heal sequence once the
+// context of WaitForHeal is canceled.
+const healStopTimeout = 10 * time.Second
+
+// WaitForHeal - follows the heal sequence started on bucket and prefix with
+// token until it is finished or stopped, progress is called with every
+// status received. The returned status accumulates the items of all
+// statuses. ErrHealFailed is returned if the sequence stopped on an error.
+// When ctx is canceled the heal sequence is stopped on the server.
+func (adm *AdminClient) WaitForHeal(ctx context.Context, bucket, prefix, token string, progress func(HealTaskStatus)) (HealTaskStatus, error) {
+	if token == "" {
+		return HealTaskStatus{}, ErrInvalidArgument("heal client token cannot be empty")
+	}
+	status, err := adm.followHeal(ctx, bucket, prefix, token, progress)
+	if err != nil && ctx.Err() != nil {
+		stopCtx, cancel := context.WithTimeout(context.Background(), healStopTimeout)
+		defer cancel()
+		if _, _, serr := adm.Heal(stopCtx, bucket, prefix, HealOpts{}, "", false, true); serr != nil {
+			return status, fmt.Errorf("%w (unable to stop the heal sequence: %v)", err, serr)
+		}
+	}
+	return status, err
+}
+
+// HealErasureSet - starts a heal sequence restricted to the erasure set
+// set of pool pool, both zero based. The indices are validated against the
+// cluster topology before starting the heal. The scope is sent on a
+// dedicated API, servers which do not provide it answer with
+// ErrNotSupported, a cluster wide heal is never started instead. The
+// sequence can be followed with WaitForHeal on an empty bucket and prefix.
+func (adm *AdminClient) HealErasureSet(ctx context.Context, pool, set int, opts HealOpts) (HealStartSuccess, error) {
+	info, err := adm.ServerInfo(ctx)
+	if err != nil {
+		return HealStartSuccess{}, err
+	}
+	if err = validateErasureSet(info, pool, set); err != nil {
+		return HealStartSuccess{}, err
+	}
+	opts.Recursive = true
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return HealStartSuccess{}, err
+	}
+
+	queryVals := make(url.Values)
+	queryVals.Set("pool", strconv.Itoa(pool))
+	queryVals.Set("set", strconv.Itoa(set))
+
+	resp, err := adm.executeMethod(ctx,
+		http.MethodPost, requestData{
+			relPath:     adminAPIPrefix + "/heal-set",
+			content:     body,
+			queryValues: queryVals,
+		})
+	defer closeResponse(resp)
+	if err != nil {
+		return HealStartSuccess{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err = httpRespToErrorResponse(resp)
+		switch resp.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			// Indices were validated, the API itself is unknown.
+			return HealStartSuccess{}, fmt.Errorf("%w: erasure set heal: %v", ErrNotSupported, err)
+		}
+		return HealStartSuccess{}, err
+	}
+
+	var start HealStartSuccess
+	if err = json.NewDecoder(resp.Body).Decode(&start); err != nil {
+		return HealStartSuccess{}, err
+	}
+	return start, nil
+}
+
+func validateErasureSet(info InfoMessage, pool, set int) error {
+	sets := info.Backend.TotalSets
+	if pool < 0 || pool >= len(sets) {
+		return ErrInvalidArgument(fmt.Sprintf("pool index %d out of range, the cluster has %d pools", pool, len(sets)))
+	}
+	if set < 0 || set >= sets[pool] {
+		return ErrInvalidArgument(fmt.Sprintf("set index %d out of range, pool %d has %d sets", set, pool, sets[pool]))
+	}
+	return nil
+}
+
+// HealIssue - the kind of problem found on an item by HealDryRun
+type HealIssue string
+
+// Heal issue kinds
+const (
+	HealIssueMissing  HealIssue = "missing"  // parts missing on some drives
+	HealIssueBitrot   HealIssue = "bitrot"   // parts failing their bitrot checksum
+	HealIssueMetadata HealIssue = "metadata" // metadata inconsistent across drives
+)
+
+// HealScanItem - an item found needing heal by HealDryRun
+type HealScanItem struct {
+	HealResultItem
+	Issues []HealIssue `json:"issues"`
+	// EstimatedBytes is the amount of data to rewrite to heal the item.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+}
+
+// HealScanReport - result of HealDryRun
+type HealScanReport struct {
+	Scanned        int               `json:"scanned"`
+	Items          []HealScanItem    `json:"items,omitempty"`
+	Issues         map[HealIssue]int `json:"issues,omitempty"`
+	EstimatedBytes int64             `json:"estimatedBytes"`
+}
+
+// healIssues - returns the issues of an item and the number of drives
+// to heal, based on the drive states before heal. Offline drives cannot
+// be healed and are ignored.
+func healIssues(item HealResultItem) (issues []HealIssue, drives int) {
+	var missing, corrupt bool
+	for _, d := range item.Before.Drives {
+		switch d.State {
+		case DriveStateMissing:
+			missing = true
+			drives++
+		case DriveStateCorrupt:
+			corrupt = true
+			drives++
+		}
+	}
+	switch {
+	case item.Type == HealItemMetadata || item.Type == HealItemBucketMetadata:
+		if drives > 0 {
+			issues = append(issues, HealIssueMetadata)
+		}
+	default:
+		if missing {
+			issues = append(issues, HealIssueMissing)
+		}
+		if corrupt {
+			issues = append(issues, HealIssueBitrot)
+		}
+	}
+	return issues, drives
+}
+
+// HealDryRun - scans bucket and prefix like Heal and reports the items
+// needing heal, without healing them. The options which write data,
+// Remove, Recreate and UpdateParity, are rejected and DryRun is always set.
+// Blocks until the scan completes.
+func (adm *AdminClient) HealDryRun(ctx context.Context, bucket, prefix string, opts HealOpts) (HealScanReport, error) {
+	if opts.Remove || opts.Recreate || opts.UpdateParity {
+		return HealScanReport{}, ErrInvalidArgument("remove, recreate and update parity are not allowed in a heal dry run")
+	}
+	opts.DryRun = true
+
+	start, _, err := adm.Heal(ctx, bucket, prefix, opts, "", false, false)
+	if err != nil {
+		return HealScanReport{}, err
+	}
+	status, err := adm.WaitForHeal(ctx, bucket, prefix, start.ClientToken, nil)
+	if err != nil {
+		return HealScanReport{}, err
+	}
+
+	report := HealScanReport{Scanned: len(status.Items), Issues: make(map[HealIssue]int)}
+	for _, item := range status.Items {
+		issues, drives := healIssues(item)
+		if len(issues) == 0 {
+			continue
+		}
+		si := HealScanItem{HealResultItem: item, Issues: issues}
+		if item.Type == HealItemObject {
+			dataBlocks := item.DataBlocks
+			if dataBlocks <= 0 {
+				dataBlocks = 1
+			}
+			// Every drive to heal receives one shard of the object.
+			si.EstimatedBytes = item.ObjectSize / int64(dataBlocks) * int64(drives)
+		}
+		for _, issue := range issues {
+			report.Issues[issue]++
+		}
+		report.EstimatedBytes += si.EstimatedBytes
+		report.Items = append(report.Items, si)
+	}
+	return report, nil
+}
+
 // MRFStatus exposes MRF metrics of a server
 type MRFStatus struct {
 	BytesHealed uint64 `json:"bytes_healed"`
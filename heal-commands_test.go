@@ -20,7 +20,14 @@
 package madmin
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
 	"testing"
+	"time"
 )
 
 // Tests heal drives missing and offline counts.
@@ -73,3 +80,203 @@ func TestHealDriveCounts(t *testing.T) {
 		t.Errorf("Expected '4', got %d after missing disks", i)
 	}
 }
+
+func TestHealTaskStatusFailures(t *testing.T) {
+	item := func(object string, before, after []string) HealResultItem {
+		it := HealResultItem{Type: HealItemObject, Bucket: "bucket", Object: object}
+		for _, s := range before {
+			it.Before.Drives = append(it.Before.Drives, HealDriveInfo{State: s})
+		}
+		for _, s := range after {
+			it.After.Drives = append(it.After.Drives, HealDriveInfo{State: s})
+		}
+		return it
+	}
+	ok, missing, offline := DriveStateOk, DriveStateMissing, DriveStateOffline
+	status := HealTaskStatus{Items: []HealResultItem{
+		item("healed", []string{ok, missing}, []string{ok, ok}),
+		item("failed", []string{ok, missing}, []string{ok, missing}),
+		item("offline", []string{offline, ok}, []string{offline, ok}),
+		item("clean", []string{ok, ok}, []string{ok, ok}),
+		item("running", []string{ok, missing}, nil),
+	}}
+
+	failures := status.Failures()
+	if len(failures) != 2 || failures[0].Object != "failed" || failures[1].Object != "offline" {
+		t.Errorf("Unexpected failures %+v", failures)
+	}
+	want := HealSummaryCounts{Healed: 1, Failed: 2, Skipped: 1, InProgress: 1}
+	if got := status.SummaryCounts(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestHealDryRun(t *testing.T) {
+	defer func(d time.Duration) { healPollInterval = d }(healPollInterval)
+	healPollInterval = time.Millisecond
+
+	item := func(object, state string) HealResultItem {
+		it := HealResultItem{Type: HealItemObject, Bucket: "bucket", Object: object, DataBlocks: 2, ObjectSize: 1 << 20}
+		it.Before.Drives = []HealDriveInfo{{State: DriveStateOk}, {State: DriveStateOk}, {State: state}}
+		it.After.Drives = it.Before.Drives
+		return it
+	}
+
+	var polls int
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/heal/bucket/prefix" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		enc := json.NewEncoder(w)
+		if r.URL.Query().Get("clientToken") == "" {
+			var opts HealOpts
+			if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+				t.Error(err)
+			}
+			if !opts.DryRun || !opts.Recursive {
+				t.Errorf("Expected a recursive dry run, got %+v", opts)
+			}
+			enc.Encode(HealStartSuccess{ClientToken: "token"})
+			return
+		}
+		polls++
+		if polls == 1 {
+			enc.Encode(HealTaskStatus{Summary: HealTaskRunning, Items: []HealResultItem{item("a", DriveStateMissing)}})
+			return
+		}
+		enc.Encode(HealTaskStatus{Summary: HealTaskFinished, Items: []HealResultItem{
+			item("b", DriveStateCorrupt),
+			item("c", DriveStateOk),
+		}})
+	})
+
+	report, err := adm.HealDryRun(context.Background(), "bucket", "prefix", HealOpts{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 3 || len(report.Items) != 2 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	if report.Issues[HealIssueMissing] != 1 || report.Issues[HealIssueBitrot] != 1 || report.Issues[HealIssueMetadata] != 0 {
+		t.Errorf("Unexpected issues %v", report.Issues)
+	}
+	if report.EstimatedBytes != 1<<20 {
+		t.Errorf("Expected 1MiB to heal, got %d", report.EstimatedBytes)
+	}
+
+	if _, err = adm.HealDryRun(context.Background(), "bucket", "prefix", HealOpts{Remove: true}); err == nil {
+		t.Error("Expected remove to be rejected")
+	}
+}
+
+func TestWaitForHeal(t *testing.T) {
+	defer func(d time.Duration) { healPollInterval = d }(healPollInterval)
+	healPollInterval = time.Millisecond
+
+	var mu sync.Mutex
+	var polls, stops int
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		if q.Get("forceStop") == "true" {
+			stops++
+			json.NewEncoder(w).Encode(HealStopSuccess{})
+			return
+		}
+		if q.Get("clientToken") != "token" {
+			t.Errorf("Unexpected query %v", q)
+		}
+		polls++
+		status := HealTaskStatus{Summary: HealTaskRunning, Items: []HealResultItem{{ResultIndex: int64(polls)}}}
+		if polls == 3 {
+			status.Summary = HealTaskFinished
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+
+	var progress []string
+	status, err := adm.WaitForHeal(context.Background(), "bucket", "", "token", func(s HealTaskStatus) {
+		progress = append(progress, s.Summary)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Summary != HealTaskFinished || len(status.Items) != 3 {
+		t.Errorf("Unexpected final status %+v", status)
+	}
+	if len(progress) != 3 || progress[0] != HealTaskRunning || progress[2] != HealTaskFinished {
+		t.Errorf("Unexpected progress %v", progress)
+	}
+
+	// Canceling stops the heal sequence.
+	mu.Lock()
+	polls = -100
+	mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = adm.WaitForHeal(ctx, "bucket", "", "token", func(HealTaskStatus) { cancel() })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if stops != 1 {
+		t.Errorf("Expected the heal sequence to be stopped, got %d stops", stops)
+	}
+}
+
+func TestHealErasureSet(t *testing.T) {
+	var (
+		healed      *HealOpts
+		scope       url.Values
+		unsupported bool
+	)
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/info":
+			json.NewEncoder(w).Encode(InfoMessage{Backend: ErasureBackend{TotalSets: []int{2, 4}}})
+		case libraryAdminURLPrefix + adminAPIPrefix + "/heal-set":
+			if unsupported {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			healed, scope = &HealOpts{}, r.URL.Query()
+			if err := json.NewDecoder(r.Body).Decode(healed); err != nil {
+				t.Error(err)
+			}
+			json.NewEncoder(w).Encode(HealStartSuccess{ClientToken: "token"})
+		default:
+			// A cluster wide heal must never be started.
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	for _, idx := range [][2]int{{-1, 0}, {2, 0}, {0, 2}, {1, 4}, {1, -1}} {
+		if _, err := adm.HealErasureSet(context.Background(), idx[0], idx[1], HealOpts{}); err == nil {
+			t.Errorf("Expected pool %d set %d to be out of range", idx[0], idx[1])
+		}
+	}
+	if healed != nil {
+		t.Fatal("Expected no heal for out of range indices")
+	}
+
+	start, err := adm.HealErasureSet(context.Background(), 1, 3, HealOpts{ScanMode: HealDeepScan})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start.ClientToken != "token" {
+		t.Errorf("Unexpected start %+v", start)
+	}
+	if healed == nil || !healed.Recursive || healed.ScanMode != HealDeepScan {
+		t.Errorf("Unexpected heal options %+v", healed)
+	}
+	if scope.Get("pool") != "1" || scope.Get("set") != "3" {
+		t.Errorf("Unexpected heal scope %v", scope)
+	}
+
+	unsupported = true
+	if _, err = adm.HealErasureSet(context.Background(), 1, 3, HealOpts{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// drivePerfCSVHeader - columns written by WriteDrivePerfCSV, latencies are
+// in seconds and throughputs in bytes per second.
+var drivePerfCSVHeader = []string{
+	"node", "path", "mode",
+	"latency_avg", "latency_p50", "latency_p90", "latency_p99",
+	"throughput_avg", "throughput_p50", "throughput_p90", "throughput_p99",
+	"error",
+}
+
+// WriteDrivePerfCSV - writes the drive perf results of all nodes as CSV, one
+// row per drive and mode ("serial" or "parallel"). Drives that reported an
+// error have blank numeric columns, a node level error is written as a row
+// with an empty path.
+func WriteDrivePerfCSV(w io.Writer, info PerfInfo) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(drivePerfCSVHeader); err != nil {
+		return err
+	}
+	for _, node := range info.Drives {
+		if node.Error != "" {
+			if err := cw.Write(drivePerfCSVRow(node.Addr, DrivePerfInfo{Error: node.Error}, "")); err != nil {
+				return err
+			}
+		}
+		for _, d := range node.SerialPerf {
+			if err := cw.Write(drivePerfCSVRow(node.Addr, d, "serial")); err != nil {
+				return err
+			}
+		}
+		for _, d := range node.ParallelPerf {
+			if err := cw.Write(drivePerfCSVRow(node.Addr, d, "parallel")); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func drivePerfCSVRow(node string, d DrivePerfInfo, mode string) []string {
+	row := make([]string, len(drivePerfCSVHeader))
+	row[0], row[1], row[2] = node, d.Path, mode
+	row[len(row)-1] = d.Error
+	if d.Error != "" {
+		return row
+	}
+	l, t := d.Latency, d.Throughput
+	for i, v := range []float64{l.Avg, l.Percentile50, l.Percentile90, l.Percentile99} {
+		row[3+i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	for i, v := range []uint64{t.Avg, t.Percentile50, t.Percentile90, t.Percentile99} {
+		row[7+i] = strconv.FormatUint(v, 10)
+	}
+	return row
+}
@@ -0,0 +1,52 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDrivePerfCSV(t *testing.T) {
+	info := PerfInfo{
+		Drives: []DrivePerfInfos{{
+			NodeCommon: NodeCommon{Addr: "node1"},
+			SerialPerf: []DrivePerfInfo{
+				{
+					Path:       "/d1",
+					Latency:    Latency{Avg: 0.5, Percentile50: 0.25, Percentile90: 1, Percentile99: 2},
+					Throughput: Throughput{Avg: 100, Percentile50: 90, Percentile90: 120, Percentile99: 150},
+				},
+				{Path: "/d2", Error: "drive offline"},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDrivePerfCSV(&buf, info); err != nil {
+		t.Fatal(err)
+	}
+	want := "node,path,mode,latency_avg,latency_p50,latency_p90,latency_p99,throughput_avg,throughput_p50,throughput_p90,throughput_p99,error\n" +
+		"node1,/d1,serial,0.5,0.25,1,2,100,90,120,150,\n" +
+		"node1,/d2,serial,,,,,,,,,drive offline\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Unexpected CSV output:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,175 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"math"
+	"sort"
+)
+
+// HealthInfoDiff - differences between two health info captures
+type HealthInfoDiff struct {
+	NodesAdded     []string             `json:"nodesAdded,omitempty"`
+	NodesRemoved   []string             `json:"nodesRemoved,omitempty"`
+	DriveErrors    []DriveErrorChange   `json:"driveErrors,omitempty"`
+	DriveLatencies []DriveLatencyChange `json:"driveLatencies,omitempty"`
+}
+
+// DriveErrorChange - a drive whose error status changed between captures
+type DriveErrorChange struct {
+	Addr     string `json:"addr"`
+	Path     string `json:"path"`
+	Parallel bool   `json:"parallel,omitempty"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+}
+
+// DriveLatencyChange - a drive latency percentile that changed between captures
+type DriveLatencyChange struct {
+	Addr       string  `json:"addr"`
+	Path       string  `json:"path"`
+	Parallel   bool    `json:"parallel,omitempty"`
+	Percentile float64 `json:"percentile"`
+	Before     float64 `json:"before"`
+	After      float64 `json:"after"`
+}
+
+type driveKey struct {
+	addr     string
+	path     string
+	parallel bool
+}
+
+// DiffHealthInfoV2 - compares two health info captures. Nodes are matched by
+// address and drives by node address and path. Latency percentiles are
+// reported only when they moved by more than threshold seconds.
+func DiffHealthInfoV2(before, after HealthInfoV2, threshold float64) HealthInfoDiff {
+	var diff HealthInfoDiff
+
+	nodesBefore, nodesAfter := healthInfoV2Nodes(before), healthInfoV2Nodes(after)
+	for addr := range nodesAfter {
+		if _, ok := nodesBefore[addr]; !ok {
+			diff.NodesAdded = append(diff.NodesAdded, addr)
+		}
+	}
+	for addr := range nodesBefore {
+		if _, ok := nodesAfter[addr]; !ok {
+			diff.NodesRemoved = append(diff.NodesRemoved, addr)
+		}
+	}
+	sort.Strings(diff.NodesAdded)
+	sort.Strings(diff.NodesRemoved)
+
+	drivesBefore, drivesAfter := healthInfoV2Drives(before), healthInfoV2Drives(after)
+	for _, k := range sortedDriveKeys(drivesAfter) {
+		a := drivesAfter[k]
+		b, ok := drivesBefore[k]
+		if !ok {
+			continue
+		}
+		if (a.Error == "") != (b.Error == "") {
+			diff.DriveErrors = append(diff.DriveErrors, DriveErrorChange{
+				Addr:     k.addr,
+				Path:     k.path,
+				Parallel: k.parallel,
+				Before:   b.Error,
+				After:    a.Error,
+			})
+		}
+		for _, p := range []float64{50, 90, 99} {
+			lb, _ := b.Latency.Percentile(p)
+			la, _ := a.Latency.Percentile(p)
+			if math.Abs(la-lb) > threshold {
+				diff.DriveLatencies = append(diff.DriveLatencies, DriveLatencyChange{
+					Addr:       k.addr,
+					Path:       k.path,
+					Parallel:   k.parallel,
+					Percentile: p,
+					Before:     lb,
+					After:      la,
+				})
+			}
+		}
+	}
+	return diff
+}
+
+// healthInfoV2Nodes - returns the addresses of all nodes present in the capture
+func healthInfoV2Nodes(info HealthInfoV2) map[string]struct{} {
+	nodes := make(map[string]struct{})
+	add := func(addr string) {
+		if addr != "" {
+			nodes[addr] = struct{}{}
+		}
+	}
+	for _, n := range info.Sys.CPUInfo {
+		add(n.Addr)
+	}
+	for _, n := range info.Sys.Partitions {
+		add(n.Addr)
+	}
+	for _, n := range info.Sys.OSInfo {
+		add(n.Addr)
+	}
+	for _, n := range info.Sys.MemInfo {
+		add(n.Addr)
+	}
+	for _, n := range info.Sys.ProcInfo {
+		add(n.Addr)
+	}
+	for _, n := range info.Perf.Drives {
+		add(n.Addr)
+	}
+	for _, n := range info.Perf.Net {
+		add(n.Addr)
+	}
+	return nodes
+}
+
+// healthInfoV2Drives - returns all drive perf results of the capture
+func healthInfoV2Drives(info HealthInfoV2) map[driveKey]DrivePerfInfo {
+	drives := make(map[driveKey]DrivePerfInfo)
+	for _, n := range info.Perf.Drives {
+		for _, d := range n.SerialPerf {
+			drives[driveKey{addr: n.Addr, path: d.Path}] = d
+		}
+		for _, d := range n.ParallelPerf {
+			drives[driveKey{addr: n.Addr, path: d.Path, parallel: true}] = d
+		}
+	}
+	return drives
+}
+
+func sortedDriveKeys(drives map[driveKey]DrivePerfInfo) []driveKey {
+	keys := make([]driveKey, 0, len(drives))
+	for k := range drives {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].addr != keys[j].addr {
+			return keys[i].addr < keys[j].addr
+		}
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return !keys[i].parallel && keys[j].parallel
+	})
+	return keys
+}
@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+func init() {
+	// Concrete types found in the interface{} fields of the
+	// health info, as produced by decoding it from JSON.
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(ErasureBackend{})
+	gob.Register(FSBackend{})
+}
+
+// healthInfoV2Gob has the fields of HealthInfoV2 without its gob methods.
+type healthInfoV2Gob HealthInfoV2
+
+// GobEncode - encodes the health info using encoding/gob
+func (info HealthInfoV2) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(healthInfoV2Gob(info)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode - decodes health info encoded with GobEncode
+func (info *HealthInfoV2) GobDecode(data []byte) error {
+	var v healthInfoV2Gob
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+		return err
+	}
+	*info = HealthInfoV2(v)
+	return nil
+}
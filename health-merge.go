@@ -0,0 +1,172 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"reflect"
+	"strings"
+)
+
+// HealthInfoV2Accumulator - reduces a stream of HealthInfoV2 fragments
+// into a single HealthInfoV2, keeping track of the errors reported by
+// every fragment.
+type HealthInfoV2Accumulator struct {
+	info HealthInfoV2
+	errs []string
+}
+
+// Add - merges the fragment into the accumulated health info.
+func (a *HealthInfoV2Accumulator) Add(frag HealthInfoV2) {
+	if frag.Error != "" {
+		a.errs = append(a.errs, frag.Error)
+	}
+	MergeHealthInfoV2(&a.info, &frag)
+}
+
+// Result - returns the accumulated health info.
+func (a *HealthInfoV2Accumulator) Result() HealthInfoV2 {
+	return a.info
+}
+
+// Errors - returns the errors of all the fragments added so far,
+// in the order they were received.
+func (a *HealthInfoV2Accumulator) Errors() []string {
+	return a.errs
+}
+
+// MergeHealthInfoV2 - merges the health info fragment src into dst. Node
+// specific entries are deduplicated by node address, an entry in src
+// replaces the entry of the same node in dst. The latest non-empty
+// version and timestamp win, errors of both are combined.
+func MergeHealthInfoV2(dst, src *HealthInfoV2) {
+	if dst == nil || src == nil {
+		return
+	}
+
+	if src.Version != "" {
+		dst.Version = src.Version
+	}
+	if src.TimeStamp.After(dst.TimeStamp) {
+		dst.TimeStamp = src.TimeStamp
+	}
+	dst.Error = joinErrors(dst.Error, src.Error)
+
+	mergeSysInfo(&dst.Sys, &src.Sys)
+	mergePerfInfo(&dst.Perf, &src.Perf)
+
+	if !reflect.ValueOf(src.Minio).IsZero() {
+		dst.Minio = src.Minio
+	}
+}
+
+// joinErrors - combines two error strings with "; ", skipping
+// empty and duplicate values.
+func joinErrors(a, b string) string {
+	switch {
+	case b == "" || a == b:
+		return a
+	case a == "":
+		return b
+	}
+	for _, e := range strings.Split(a, "; ") {
+		if e == b {
+			return a
+		}
+	}
+	return a + "; " + b
+}
+
+// nodeIndex - returns the position of every key in a slice of n elements.
+func nodeIndex(n int, key func(i int) string) map[string]int {
+	idx := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		idx[key(i)] = i
+	}
+	return idx
+}
+
+// nodeAddr - returns the Addr field of a node specific entry.
+func nodeAddr(v reflect.Value) string {
+	return v.FieldByName("Addr").String()
+}
+
+// mergeNodes - merges the entries of the slice src into the slice dst
+// points to, deduplicating them by key. An entry of src replaces the entry
+// of dst with the same key, other entries are appended.
+func mergeNodes(dst, src interface{}, key func(v reflect.Value) string) {
+	d, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src)
+	idx := nodeIndex(d.Len(), func(i int) string { return key(d.Index(i)) })
+	for i := 0; i < sv.Len(); i++ {
+		v := sv.Index(i)
+		k := key(v)
+		if j, ok := idx[k]; ok {
+			d.Index(j).Set(v)
+			continue
+		}
+		idx[k] = d.Len()
+		d.Set(reflect.Append(d, v))
+	}
+}
+
+func mergePerfInfo(dst, src *PerfInfo) {
+	idx := nodeIndex(len(dst.Drives), func(i int) string { return dst.Drives[i].Addr })
+	for _, n := range src.Drives {
+		i, ok := idx[n.Addr]
+		if !ok {
+			idx[n.Addr] = len(dst.Drives)
+			dst.Drives = append(dst.Drives, n)
+			continue
+		}
+		// Serial and parallel results may arrive in separate fragments.
+		d := &dst.Drives[i]
+		if len(n.SerialPerf) > 0 {
+			d.SerialPerf = n.SerialPerf
+		}
+		if len(n.ParallelPerf) > 0 {
+			d.ParallelPerf = n.ParallelPerf
+		}
+		d.Error = joinErrors(d.Error, n.Error)
+	}
+
+	mergeNodes(&dst.Net, src.Net, nodeAddr)
+
+	if src.NetParallel.Addr != "" || len(src.NetParallel.RemotePeers) > 0 || src.NetParallel.Error != "" {
+		dst.NetParallel = src.NetParallel
+	}
+}
+
+func mergeSysInfo(dst, src *SysInfo) {
+	mergeNodes(&dst.CPUInfo, src.CPUInfo, nodeAddr)
+	mergeNodes(&dst.Partitions, src.Partitions, nodeAddr)
+	mergeNodes(&dst.OSInfo, src.OSInfo, nodeAddr)
+	mergeNodes(&dst.MemInfo, src.MemInfo, nodeAddr)
+	mergeNodes(&dst.ProcInfo, src.ProcInfo, nodeAddr)
+	// A node reports one NetInfo per network interface.
+	mergeNodes(&dst.NetInfo, src.NetInfo, func(v reflect.Value) string {
+		return nodeAddr(v) + "/" + v.FieldByName("Interface").String()
+	})
+	mergeNodes(&dst.SysErrs, src.SysErrs, nodeAddr)
+	mergeNodes(&dst.SysServices, src.SysServices, nodeAddr)
+	mergeNodes(&dst.SysConfig, src.SysConfig, nodeAddr)
+
+	if !reflect.ValueOf(src.KubernetesInfo).IsZero() {
+		dst.KubernetesInfo = src.KubernetesInfo
+	}
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthInfoV2Accumulator(t *testing.T) {
+	now := time.Now().UTC()
+	drives := func(addr string, paths ...string) DrivePerfInfos {
+		d := DrivePerfInfos{NodeCommon: NodeCommon{Addr: addr}}
+		for _, p := range paths {
+			d.SerialPerf = append(d.SerialPerf, DrivePerfInfo{Path: p})
+		}
+		return d
+	}
+
+	frags := []HealthInfoV2{
+		{
+			Version:   HealthInfoVersion2,
+			TimeStamp: now,
+			Sys: SysInfo{
+				CPUInfo: []CPUs{{NodeCommon: NodeCommon{Addr: "node1"}}},
+			},
+			Perf: PerfInfo{
+				Drives: []DrivePerfInfos{drives("node1", "/d1", "/d2")},
+			},
+		},
+		{
+			TimeStamp: now.Add(time.Second),
+			Error:     "node2 timed out",
+			Perf: PerfInfo{
+				Drives: []DrivePerfInfos{drives("node2", "/d1", "/d2")},
+				Net:    []NetPerfInfo{{NodeCommon: NodeCommon{Addr: "node1"}}},
+			},
+		},
+		{
+			// Same node as the first fragment, must not be counted twice.
+			TimeStamp: now.Add(2 * time.Second),
+			Error:     "node3 unreachable",
+			Sys: SysInfo{
+				CPUInfo: []CPUs{{NodeCommon: NodeCommon{Addr: "node1"}}, {NodeCommon: NodeCommon{Addr: "node2"}}},
+			},
+			Perf: PerfInfo{
+				Drives: []DrivePerfInfos{drives("node1", "/d1", "/d2")},
+			},
+		},
+	}
+
+	var acc HealthInfoV2Accumulator
+	for _, f := range frags {
+		acc.Add(f)
+	}
+	info := acc.Result()
+
+	var numDrives int
+	for _, d := range info.Perf.Drives {
+		numDrives += len(d.SerialPerf) + len(d.ParallelPerf)
+	}
+	if numDrives != 4 {
+		t.Errorf("Expected 4 drives, got %d", numDrives)
+	}
+	if len(info.Perf.Drives) != 2 {
+		t.Errorf("Expected 2 drive nodes, got %d", len(info.Perf.Drives))
+	}
+	if len(info.Sys.CPUInfo) != 2 {
+		t.Errorf("Expected 2 cpu nodes, got %d", len(info.Sys.CPUInfo))
+	}
+	if info.Version != HealthInfoVersion2 {
+		t.Errorf("Expected version %q, got %q", HealthInfoVersion2, info.Version)
+	}
+	if !info.TimeStamp.Equal(now.Add(2 * time.Second)) {
+		t.Errorf("Expected latest timestamp, got %v", info.TimeStamp)
+	}
+	if errs := acc.Errors(); len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %v", errs)
+	}
+	if info.Error != "node2 timed out; node3 unreachable" {
+		t.Errorf("Unexpected combined error %q", info.Error)
+	}
+}
@@ -20,7 +20,16 @@
 package madmin
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
 	"time"
 )
 
@@ -59,6 +68,39 @@ func (info HealthInfoV2) JSON() string {
 	return string(data)
 }
 
+// healthInfoLine - envelope of a single subsystem written by WriteNDJSON
+type healthInfoLine struct {
+	Subsystem string      `json:"subsystem"`
+	TimeStamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// WriteNDJSON - writes the Sys, Perf and Minio sections as one JSON object
+// per line, each wrapped in an envelope naming the subsystem. The writer
+// is flushed after every line if it supports flushing.
+func (info HealthInfoV2) WriteNDJSON(w io.Writer) error {
+	lines := []healthInfoLine{
+		{Subsystem: "sys", TimeStamp: info.TimeStamp, Data: info.Sys},
+		{Subsystem: "perf", TimeStamp: info.TimeStamp, Data: info.Perf},
+		{Subsystem: "minio", TimeStamp: info.TimeStamp, Data: info.Minio},
+	}
+	enc := json.NewEncoder(w)
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			if err := f.Flush(); err != nil {
+				return err
+			}
+		case http.Flusher:
+			f.Flush()
+		}
+	}
+	return nil
+}
+
 // GetError - returns error from the cluster health info v2
 func (info HealthInfoV2) GetError() string {
 	return info.Error
@@ -77,6 +119,60 @@ func (info HealthInfoV2) GetTimestamp() time.Time {
 	return info.TimeStamp
 }
 
+// nowFunc returns the current time, replaceable in tests.
+var nowFunc = time.Now
+
+// Age - returns how long ago the health info was captured, a capture
+// without timestamp is considered infinitely old.
+func (info HealthInfoV2) Age() time.Duration {
+	if info.TimeStamp.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return nowFunc().Sub(info.TimeStamp)
+}
+
+// IsStale - returns true if the health info is older than max
+func (info HealthInfoV2) IsStale(max time.Duration) bool {
+	return info.Age() > max
+}
+
+// Fingerprint - returns a short identifier of the cluster the health info
+// was captured from, derived from the deployment ID, the number of nodes
+// and the erasure set layout of every pool. Hostnames, timestamps and perf
+// results are not part of it, so captures of the same deployment produce
+// the same fingerprint.
+func (info HealthInfoV2) Fingerprint() string {
+	type poolLayout struct{ sets, drives int }
+	pools := make(map[int]*poolLayout)
+	for _, srv := range info.Minio.Info.Servers {
+		for _, d := range srv.Drives {
+			p, ok := pools[d.PoolIndex]
+			if !ok {
+				p = &poolLayout{}
+				pools[d.PoolIndex] = p
+			}
+			if d.SetIndex+1 > p.sets {
+				p.sets = d.SetIndex + 1
+			}
+			if d.DiskIndex+1 > p.drives {
+				p.drives = d.DiskIndex + 1
+			}
+		}
+	}
+	indices := make([]int, 0, len(pools))
+	for i := range pools {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n", info.Minio.Info.DeploymentID, len(info.Minio.Info.Servers))
+	for _, i := range indices {
+		fmt.Fprintf(h, "%d:%d:%d\n", i, pools[i].sets, pools[i].drives)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
 // Latency contains write operation latency in seconds of a disk drive.
 type Latency struct {
 	Avg          float64 `json:"avg"`
@@ -97,6 +193,84 @@ type Throughput struct {
 	Percentile99 uint64 `json:"percentile_99"`
 }
 
+// Percentile - returns the latency recorded for the given percentile,
+// only the 50th, 90th and 99th percentiles are available.
+func (l Latency) Percentile(p float64) (float64, bool) {
+	switch p {
+	case 50:
+		return l.Percentile50, true
+	case 90:
+		return l.Percentile90, true
+	case 99:
+		return l.Percentile99, true
+	}
+	return 0, false
+}
+
+// String - returns a single line summary of the latency, suitable for logging.
+func (l Latency) String() string {
+	return fmt.Sprintf("avg=%.6fs min=%.6fs max=%.6fs p50=%.6fs p90=%.6fs p99=%.6fs",
+		l.Avg, l.Min, l.Max, l.Percentile50, l.Percentile90, l.Percentile99)
+}
+
+// Percentile - returns the throughput recorded for the given percentile,
+// only the 50th, 90th and 99th percentiles are available.
+func (t Throughput) Percentile(p float64) (uint64, bool) {
+	switch p {
+	case 50:
+		return t.Percentile50, true
+	case 90:
+		return t.Percentile90, true
+	case 99:
+		return t.Percentile99, true
+	}
+	return 0, false
+}
+
+// ThroughputHuman - throughput values formatted for display
+type ThroughputHuman struct {
+	Avg          string `json:"avg"`
+	Max          string `json:"max"`
+	Min          string `json:"min"`
+	Percentile50 string `json:"percentile_50"`
+	Percentile90 string `json:"percentile_90"`
+	Percentile99 string `json:"percentile_99"`
+}
+
+// HumanReadable - returns all throughput values formatted like "1.2 GiB/s"
+func (t Throughput) HumanReadable() ThroughputHuman {
+	return ThroughputHuman{
+		Avg:          formatBytesPerSec(t.Avg),
+		Max:          formatBytesPerSec(t.Max),
+		Min:          formatBytesPerSec(t.Min),
+		Percentile50: formatBytesPerSec(t.Percentile50),
+		Percentile90: formatBytesPerSec(t.Percentile90),
+		Percentile99: formatBytesPerSec(t.Percentile99),
+	}
+}
+
+// AvgString - returns the average throughput formatted like "1.2 GiB/s"
+func (t Throughput) AvgString() string {
+	return formatBytesPerSec(t.Avg)
+}
+
+// formatBytesPerSec - formats a rate using IEC units rounded to one decimal
+func formatBytesPerSec(v uint64) string {
+	const unit = 1024
+	if v < unit {
+		return fmt.Sprintf("%d B/s", v)
+	}
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	f := float64(v) / unit
+	i := 0
+	// Move to the next unit if rounding would display 1024.0
+	for f >= unit-0.05 && i < len(units)-1 {
+		f /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s/s", f, units[i])
+}
+
 // DrivePerfInfo contains disk drive's performance information.
 type DrivePerfInfo struct {
 	Error string `json:"error,omitempty"`
@@ -106,6 +280,72 @@ type DrivePerfInfo struct {
 	Throughput Throughput `json:"throughput,omitempty"`
 }
 
+// Validate - returns an error if the drive result is malformed, i.e. it has
+// no path or it carries neither measurements nor an error, which indicates
+// that collection failed silently.
+func (d DrivePerfInfo) Validate() error {
+	if d.Path == "" {
+		return errors.New("drive perf result without path")
+	}
+	if d.Error == "" && d.Latency == (Latency{}) && d.Throughput == (Throughput{}) {
+		return fmt.Errorf("drive %s: no measurements and no error reported", d.Path)
+	}
+	return nil
+}
+
+// Validate - returns an error if the peer result is malformed, see DrivePerfInfo.Validate
+func (p PeerNetPerfInfo) Validate() error {
+	if p.Addr == "" {
+		return errors.New("net perf result without peer address")
+	}
+	if p.Error == "" && p.Latency == (Latency{}) && p.Throughput == (Throughput{}) {
+		return fmt.Errorf("peer %s: no measurements and no error reported", p.Addr)
+	}
+	return nil
+}
+
+// NodeDrivePerf - the perf result of a drive along with the address of
+// the node owning it.
+type NodeDrivePerf struct {
+	Addr string `json:"addr"`
+	DrivePerfInfo
+}
+
+// SortDrivePerfByLatency - flattens the serial and parallel drive results of
+// all nodes and sorts them by descending 99th percentile latency. Drives that
+// reported an error sort first. Every result carries its node address.
+func SortDrivePerfByLatency(nodes []DrivePerfInfos) []NodeDrivePerf {
+	var drives []NodeDrivePerf
+	for _, node := range nodes {
+		for _, perf := range [][]DrivePerfInfo{node.SerialPerf, node.ParallelPerf} {
+			for _, d := range perf {
+				drives = append(drives, NodeDrivePerf{Addr: node.Addr, DrivePerfInfo: d})
+			}
+		}
+	}
+	sort.SliceStable(drives, func(i, j int) bool {
+		ei, ej := drives[i].Error != "", drives[j].Error != ""
+		if ei != ej {
+			return ei
+		}
+		return drives[i].Latency.Percentile99 > drives[j].Latency.Percentile99
+	})
+	return drives
+}
+
+// TopSlowDrives - returns the n slowest drives of the cluster,
+// see SortDrivePerfByLatency for the ordering.
+func TopSlowDrives(info PerfInfo, n int) []NodeDrivePerf {
+	drives := SortDrivePerfByLatency(info.Drives)
+	if n < 0 {
+		n = 0
+	}
+	if n < len(drives) {
+		drives = drives[:n]
+	}
+	return drives
+}
+
 // DrivePerfInfos contains all disk drive's performance information of a node.
 type DrivePerfInfos struct {
 	NodeCommon
@@ -129,6 +369,66 @@ type NetPerfInfo struct {
 	RemotePeers []PeerNetPerfInfo `json:"remote_peers,omitempty"`
 }
 
+// AsymmetricLink - a pair of nodes whose network throughput differs
+// depending on the direction, or which was measured in one direction only.
+type AsymmetricLink struct {
+	A          string `json:"a"`
+	B          string `json:"b"`
+	AToB       uint64 `json:"aToB"` // average throughput from A to B
+	BToA       uint64 `json:"bToA"` // average throughput from B to A
+	Incomplete bool   `json:"incomplete,omitempty"`
+}
+
+// DetectAsymmetricLinks - pairs the measurements of both directions between
+// every two nodes and returns the links whose faster direction is more than
+// ratio times the slower one. Links measured in a single direction are
+// returned as incomplete.
+func DetectAsymmetricLinks(nets []NetPerfInfo, ratio float64) []AsymmetricLink {
+	type link struct{ from, to string }
+	measured := make(map[link]uint64)
+	for _, n := range nets {
+		for _, peer := range n.RemotePeers {
+			if peer.Error != "" || peer.Addr == n.Addr {
+				continue
+			}
+			measured[link{n.Addr, peer.Addr}] = peer.Throughput.Avg
+		}
+	}
+
+	links := make([]link, 0, len(measured))
+	for l := range measured {
+		links = append(links, l)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].from != links[j].from {
+			return links[i].from < links[j].from
+		}
+		return links[i].to < links[j].to
+	})
+
+	var result []AsymmetricLink
+	for _, l := range links {
+		rev, ok := measured[link{l.to, l.from}]
+		if ok && l.from > l.to {
+			continue // already handled from the other side.
+		}
+		al := AsymmetricLink{A: l.from, B: l.to, AToB: measured[l], BToA: rev}
+		if !ok {
+			al.Incomplete = true
+			result = append(result, al)
+			continue
+		}
+		lo, hi := al.AToB, al.BToA
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if float64(hi) > float64(lo)*ratio {
+			result = append(result, al)
+		}
+	}
+	return result
+}
+
 // PerfInfo - Includes Drive and Net perf info for the entire MinIO cluster
 type PerfInfo struct {
 	Drives      []DrivePerfInfos `json:"drives,omitempty"`
@@ -136,6 +436,97 @@ type PerfInfo struct {
 	NetParallel NetPerfInfo      `json:"net_parallel,omitempty"`
 }
 
+// NodePerfView - the perf results of a single node, parts
+// that were not reported for the node are nil.
+type NodePerfView struct {
+	Drives      *DrivePerfInfos
+	Net         *NetPerfInfo
+	NetParallel *PeerNetPerfInfo
+}
+
+// ByNode - returns the drive, net and parallel net results grouped by node
+// address. Every node present in any of them has an entry.
+func (p PerfInfo) ByNode() map[string]NodePerfView {
+	nodes := make(map[string]NodePerfView)
+	for i := range p.Drives {
+		v := nodes[p.Drives[i].Addr]
+		v.Drives = &p.Drives[i]
+		nodes[p.Drives[i].Addr] = v
+	}
+	for i := range p.Net {
+		v := nodes[p.Net[i].Addr]
+		v.Net = &p.Net[i]
+		nodes[p.Net[i].Addr] = v
+	}
+	for i := range p.NetParallel.RemotePeers {
+		peer := &p.NetParallel.RemotePeers[i]
+		v := nodes[peer.Addr]
+		v.NetParallel = peer
+		nodes[peer.Addr] = v
+	}
+	return nodes
+}
+
+// Validate - validates all drive and net results, errors are
+// prefixed with the address of the node reporting the result.
+func (p PerfInfo) Validate() []error {
+	var errs []error
+	for _, node := range p.Drives {
+		for _, perf := range [][]DrivePerfInfo{node.SerialPerf, node.ParallelPerf} {
+			for _, d := range perf {
+				if err := d.Validate(); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", node.Addr, err))
+				}
+			}
+		}
+	}
+	validateNet := func(n NetPerfInfo) {
+		for _, peer := range n.RemotePeers {
+			if err := peer.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", n.Addr, err))
+			}
+		}
+	}
+	for _, n := range p.Net {
+		validateNet(n)
+	}
+	validateNet(p.NetParallel)
+	return errs
+}
+
+// CollectPerfErrors - returns the errors reported in the perf info keyed by
+// node address. Errors reported by individual drives are attributed to the
+// node owning them, multiple errors of the same node are joined with "; ".
+func CollectPerfErrors(p PerfInfo) map[string]string {
+	errs := make(map[string]string)
+	add := func(addr, err string) {
+		if err != "" {
+			errs[addr] = joinErrors(errs[addr], err)
+		}
+	}
+	addNet := func(n NetPerfInfo) {
+		add(n.Addr, n.Error)
+		for _, peer := range n.RemotePeers {
+			add(peer.Addr, peer.Error)
+		}
+	}
+
+	for _, node := range p.Drives {
+		add(node.Addr, node.Error)
+		for _, d := range node.SerialPerf {
+			add(node.Addr, d.Error)
+		}
+		for _, d := range node.ParallelPerf {
+			add(node.Addr, d.Error)
+		}
+	}
+	for _, n := range p.Net {
+		addNet(n)
+	}
+	addNet(p.NetParallel)
+	return errs
+}
+
 func (info HealthInfoV0) String() string {
 	data, err := json.Marshal(info)
 	if err != nil {
@@ -153,6 +544,60 @@ func (info HealthInfoV0) JSON() string {
 	return string(data)
 }
 
+// MarshalCompact - returns the health info as compact JSON with all object
+// keys sorted, identical captures always produce identical output.
+func (info HealthInfoV0) MarshalCompact() ([]byte, error) {
+	v, err := info.sortedKeys()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// MarshalPretty - same as MarshalCompact but indented for readability.
+func (info HealthInfoV0) MarshalPretty() ([]byte, error) {
+	v, err := info.sortedKeys()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(v, "", "    ")
+}
+
+// sortedKeys - converts the health info into generic JSON values, objects
+// become maps which encoding/json always marshals with sorted keys while
+// arrays keep the order they were received in.
+func (info HealthInfoV0) sortedKeys() (interface{}, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err = dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetError - returns error from the cluster health info v0
+func (info HealthInfoV0) GetError() string {
+	return info.Error
+}
+
+// GetStatus - returns status of the cluster health info v0
+func (info HealthInfoV0) GetStatus() string {
+	if info.Error != "" {
+		return "error"
+	}
+	return "success"
+}
+
+// GetTimestamp - returns timestamp from the cluster health info v0
+func (info HealthInfoV0) GetTimestamp() time.Time {
+	return info.TimeStamp
+}
+
 // SysHealthInfo - Includes hardware and system information of the MinIO cluster
 type SysHealthInfo struct {
 	CPUInfo    []ServerCPUInfo    `json:"cpus,omitempty"`
@@ -163,6 +608,48 @@ type SysHealthInfo struct {
 	Error      string             `json:"error,omitempty"`
 }
 
+// SubsystemError - an error reported by a node for a health subsystem
+type SubsystemError struct {
+	Subsystem string `json:"subsystem"`
+	Addr      string `json:"addr,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Errors - returns all errors reported in the system health info, sorted
+// by subsystem and node address. The top level error is reported with
+// the "sys" subsystem and no address.
+func (info SysHealthInfo) Errors() []SubsystemError {
+	var errs []SubsystemError
+	add := func(subsys, addr, msg string) {
+		if msg != "" {
+			errs = append(errs, SubsystemError{Subsystem: subsys, Addr: addr, Message: msg})
+		}
+	}
+	add("sys", "", info.Error)
+	for _, n := range info.CPUInfo {
+		add("cpu", n.Addr, n.Error)
+	}
+	for _, n := range info.DiskHwInfo {
+		add("diskhw", n.Addr, n.Error)
+	}
+	for _, n := range info.OsInfo {
+		add("os", n.Addr, n.Error)
+	}
+	for _, n := range info.MemInfo {
+		add("mem", n.Addr, n.Error)
+	}
+	for _, n := range info.ProcInfo {
+		add("proc", n.Addr, n.Error)
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Subsystem != errs[j].Subsystem {
+			return errs[i].Subsystem < errs[j].Subsystem
+		}
+		return errs[i].Addr < errs[j].Addr
+	})
+	return errs
+}
+
 // ServerProcInfo - Includes host process lvl information
 type ServerProcInfo struct {
 	Addr      string       `json:"addr"`
@@ -170,6 +657,53 @@ type ServerProcInfo struct {
 	Error     string       `json:"error,omitempty"`
 }
 
+// TotalCPUPercent - returns the CPU usage of all running processes of the host
+func (info ServerProcInfo) TotalCPUPercent() float64 {
+	var total float64
+	for _, p := range info.Processes {
+		if p.IsRunning {
+			total += p.CPUPercent
+		}
+	}
+	return total
+}
+
+// TotalMemPercent - returns the memory usage of all running processes of the host
+func (info ServerProcInfo) TotalMemPercent() float32 {
+	var total float32
+	for _, p := range info.Processes {
+		if p.IsRunning {
+			total += p.MemPercent
+		}
+	}
+	return total
+}
+
+// TopProcesses - returns the n processes of the host using the most "cpu"
+// or "mem", nil is returned for any other value of by.
+func (info ServerProcInfo) TopProcesses(n int, by string) []SysProcess {
+	var less func(a, b SysProcess) bool
+	switch by {
+	case "cpu":
+		less = func(a, b SysProcess) bool { return a.CPUPercent > b.CPUPercent }
+	case "mem":
+		less = func(a, b SysProcess) bool { return a.MemPercent > b.MemPercent }
+	default:
+		return nil
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	procs := make([]SysProcess, len(info.Processes))
+	copy(procs, info.Processes)
+	sort.SliceStable(procs, func(i, j int) bool { return less(procs[i], procs[j]) })
+	if n < len(procs) {
+		procs = procs[:n]
+	}
+	return procs
+}
+
 // SysProcess - Includes process lvl information about a single process
 type SysProcess struct {
 	Pid             int32   `json:"pid"`
@@ -201,6 +735,56 @@ func (sp SysProcess) GetOwner() string {
 	return sp.Username
 }
 
+// ProcessNode - a process along with its child processes
+type ProcessNode struct {
+	SysProcess
+	Children []*ProcessNode `json:"children,omitempty"`
+
+	parent *ProcessNode
+}
+
+// BuildProcessTree - arranges a flat list of processes into trees using
+// their parent pid. Processes whose parent is not in the list become roots,
+// as does any process whose parent link would close a cycle.
+func BuildProcessTree(procs []SysProcess) []*ProcessNode {
+	nodes := make(map[int32]*ProcessNode, len(procs))
+	ordered := make([]*ProcessNode, 0, len(procs))
+	for _, p := range procs {
+		if _, ok := nodes[p.Pid]; ok {
+			continue // duplicate pid, keep the first one.
+		}
+		n := &ProcessNode{SysProcess: p}
+		nodes[p.Pid] = n
+		ordered = append(ordered, n)
+	}
+
+	var roots []*ProcessNode
+	for _, n := range ordered {
+		ppid := n.Ppid
+		if ppid == 0 {
+			ppid = n.Parent
+		}
+		parent, ok := nodes[ppid]
+		if !ok || createsCycle(n, parent) {
+			roots = append(roots, n)
+			continue
+		}
+		n.parent = parent
+		parent.Children = append(parent.Children, n)
+	}
+	return roots
+}
+
+// createsCycle - returns true if n is parent itself or one of its ancestors.
+func createsCycle(n, parent *ProcessNode) bool {
+	for p := parent; p != nil; p = p.parent {
+		if p == n {
+			return true
+		}
+	}
+	return false
+}
+
 // ServerMemInfo - Includes host virtual and swap mem information
 type ServerMemInfo struct {
 	Addr  string `json:"addr"`
@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Gauges written by PerfInfo.WritePrometheus.
+//
+// Drive gauges are labeled with cluster, node, path and mode ("serial" or
+// "parallel"), net gauges with cluster, node, peer and mode. The _up gauges
+// are 0 for results that reported an error and carry no measurements, a
+// node level drive error is reported with an empty path.
+var perfPromMetrics = []struct {
+	name, help string
+}{
+	{"minio_drive_up", "Whether the drive perf test succeeded"},
+	{"minio_drive_latency_avg_seconds", "Average drive latency"},
+	{"minio_drive_latency_p50_seconds", "50th percentile drive latency"},
+	{"minio_drive_latency_p90_seconds", "90th percentile drive latency"},
+	{"minio_drive_latency_p99_seconds", "99th percentile drive latency"},
+	{"minio_drive_throughput_bytes", "Average drive throughput in bytes per second"},
+	{"minio_net_up", "Whether the net perf test succeeded"},
+	{"minio_net_latency_avg_seconds", "Average network latency"},
+	{"minio_net_latency_p50_seconds", "50th percentile network latency"},
+	{"minio_net_latency_p90_seconds", "90th percentile network latency"},
+	{"minio_net_latency_p99_seconds", "99th percentile network latency"},
+	{"minio_net_throughput_bytes", "Average network throughput in bytes per second"},
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promSamples - collects samples grouped by metric name
+type promSamples map[string][]string
+
+func (s promSamples) add(name string, labels [][2]string, v float64) {
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(l[0])
+		sb.WriteString(`="`)
+		sb.WriteString(promLabelEscaper.Replace(l[1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteString("} ")
+	sb.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	s[name] = append(s[name], sb.String())
+}
+
+func (s promSamples) addUp(name string, labels [][2]string, err string) {
+	up := 1.0
+	if err != "" {
+		up = 0
+	}
+	s.add(name, labels, up)
+}
+
+func (s promSamples) addPerf(prefix string, labels [][2]string, l Latency, t Throughput) {
+	s.add(prefix+"_latency_avg_seconds", labels, l.Avg)
+	s.add(prefix+"_latency_p50_seconds", labels, l.Percentile50)
+	s.add(prefix+"_latency_p90_seconds", labels, l.Percentile90)
+	s.add(prefix+"_latency_p99_seconds", labels, l.Percentile99)
+	s.add(prefix+"_throughput_bytes", labels, float64(t.Avg))
+}
+
+// WritePrometheus - writes the drive and net perf results as gauges in the
+// Prometheus text exposition format, see perfPromMetrics for the metrics.
+func (p PerfInfo) WritePrometheus(w io.Writer, clusterLabel string) error {
+	samples := make(promSamples)
+	for _, node := range p.Drives {
+		if node.Error != "" {
+			samples.addUp("minio_drive_up", [][2]string{
+				{"cluster", clusterLabel}, {"node", node.Addr}, {"path", ""}, {"mode", ""},
+			}, node.Error)
+		}
+		for mode, perf := range map[string][]DrivePerfInfo{"serial": node.SerialPerf, "parallel": node.ParallelPerf} {
+			for _, d := range perf {
+				labels := [][2]string{{"cluster", clusterLabel}, {"node", node.Addr}, {"path", d.Path}, {"mode", mode}}
+				samples.addUp("minio_drive_up", labels, d.Error)
+				if d.Error == "" {
+					samples.addPerf("minio_drive", labels, d.Latency, d.Throughput)
+				}
+			}
+		}
+	}
+
+	addNet := func(n NetPerfInfo, mode string) {
+		for _, peer := range n.RemotePeers {
+			labels := [][2]string{{"cluster", clusterLabel}, {"node", n.Addr}, {"peer", peer.Addr}, {"mode", mode}}
+			samples.addUp("minio_net_up", labels, peer.Error)
+			if peer.Error == "" {
+				samples.addPerf("minio_net", labels, peer.Latency, peer.Throughput)
+			}
+		}
+	}
+	for _, n := range p.Net {
+		addNet(n, "serial")
+	}
+	addNet(p.NetParallel, "parallel")
+
+	bw := bufio.NewWriter(w)
+	for _, m := range perfPromMetrics {
+		lines := samples[m.name]
+		if len(lines) == 0 {
+			continue
+		}
+		// Keep the output stable regardless of map iteration order.
+		sort.Strings(lines)
+		bw.WriteString("# HELP " + m.name + " " + m.help + "\n")
+		bw.WriteString("# TYPE " + m.name + " gauge\n")
+		for _, l := range lines {
+			bw.WriteString(l + "\n")
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+// RedactedValue replaces the value of every redacted field.
+const RedactedValue = "[redacted]"
+
+// RedactOptions - selects the fields removed by Redact
+type RedactOptions struct {
+	RedactCmdline   bool // process command lines
+	RedactUsernames bool // process owners
+	RedactPaths     bool // process working directories and executables
+}
+
+// redact - replaces a non-empty value with RedactedValue
+func redact(s *string, enabled bool) {
+	if enabled && *s != "" {
+		*s = RedactedValue
+	}
+}
+
+// Redact - replaces the sensitive fields selected by opts in place
+func (p *SysProcess) Redact(opts RedactOptions) {
+	redact(&p.CmdLine, opts.RedactCmdline)
+	redact(&p.Username, opts.RedactUsernames)
+	redact(&p.Cwd, opts.RedactPaths)
+	redact(&p.Exe, opts.RedactPaths)
+}
+
+// Redact - replaces the sensitive fields of all processes in place
+func (info *ServerProcInfo) Redact(opts RedactOptions) {
+	for i := range info.Processes {
+		info.Processes[i].Redact(opts)
+	}
+}
+
+// Redact - replaces the sensitive fields selected by opts in place
+func (p *ProcInfo) Redact(opts RedactOptions) {
+	redact(&p.CmdLine, opts.RedactCmdline)
+	redact(&p.Username, opts.RedactUsernames)
+	redact(&p.CWD, opts.RedactPaths)
+	redact(&p.ExecPath, opts.RedactPaths)
+}
+
+// Redact - replaces the sensitive fields of the health info in place,
+// so that it can be shared without leaking paths or account names.
+func (info *HealthInfoV2) Redact(opts RedactOptions) {
+	for i := range info.Sys.ProcInfo {
+		info.Sys.ProcInfo[i].Redact(opts)
+	}
+}
+
+// Redact - replaces the sensitive fields of the health info in place,
+// so that it can be shared without leaking paths or account names.
+func (info *HealthInfoV0) Redact(opts RedactOptions) {
+	for i := range info.Sys.ProcInfo {
+		info.Sys.ProcInfo[i].Redact(opts)
+	}
+}
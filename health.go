@@ -23,6 +23,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -89,6 +91,11 @@ func (n *NodeCommon) SetError(err string) {
 	n.Error = err
 }
 
+// HasError - returns true if the node reported an error
+func (n *NodeCommon) HasError() bool {
+	return n.Error != ""
+}
+
 // SysErrors - contains a system error
 type SysErrors struct {
 	NodeCommon
@@ -443,6 +450,36 @@ func (info HealthInfo) GetTimestamp() time.Time {
 	return info.TimeStamp
 }
 
+// HealthInfoVersioned - common interface of all health info versions
+type HealthInfoVersioned interface {
+	GetError() string
+	GetStatus() string
+	GetTimestamp() time.Time
+	JSON() string
+}
+
+// ErrUnknownHealthVersion is returned when decoding health info of an unsupported version.
+var ErrUnknownHealthVersion = errors.New("unknown health info version")
+
+// DecodeHealthInfo - decodes the health info in data as the type matching version.
+func DecodeHealthInfo(version string, data []byte) (HealthInfoVersioned, error) {
+	var info HealthInfoVersioned
+	switch version {
+	case HealthInfoVersion0:
+		info = &HealthInfoV0{}
+	case HealthInfoVersion2:
+		info = &HealthInfoV2{}
+	case HealthInfoVersion3:
+		info = &HealthInfo{}
+	default:
+		return nil, ErrUnknownHealthVersion
+	}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
 // HealthDataType - Typed Health data types
 type HealthDataType string
 
@@ -463,6 +500,20 @@ const (
 	HealthDataTypeSysConfig   HealthDataType = "sysconfig"
 )
 
+// Perf health data types, only collected when explicitly requested.
+const (
+	HealthDataTypePerfDrive       HealthDataType = "perfdrive"
+	HealthDataTypePerfNet         HealthDataType = "perfnet"
+	HealthDataTypePerfNetParallel HealthDataType = "perfnetparallel"
+)
+
+// HealthDataTypesPerf - List of perf health datatypes
+var HealthDataTypesPerf = []HealthDataType{
+	HealthDataTypePerfDrive,
+	HealthDataTypePerfNet,
+	HealthDataTypePerfNetParallel,
+}
+
 // HealthDataTypesMap - Map of Health datatypes
 var HealthDataTypesMap = map[string]HealthDataType{
 	"minioinfo":   HealthDataTypeMinioInfo,
@@ -503,9 +554,85 @@ type HealthInfoVersionStruct struct {
 	Error   string `json:"error,omitempty"`
 }
 
-// ServerHealthInfo - Connect to a minio server and call Health Info Management API
-// to fetch server's information represented by HealthInfo structure
-func (adm *AdminClient) ServerHealthInfo(ctx context.Context, types []HealthDataType, deadline time.Duration, anonymize string) (*http.Response, string, error) {
+// DefaultHealthInfoDeadline - deadline used by HealthInfoOpts when none is set
+const DefaultHealthInfoDeadline = time.Hour
+
+// HealthInfoOpts - builds the options of a health info request. The zero
+// value collects everything, the same as passing HealthDataTypesList.
+type HealthInfoOpts struct {
+	types     []HealthDataType
+	deadline  time.Duration
+	anonymize string
+}
+
+// WithMinioInfo - collect the minio server information and config.
+func (o HealthInfoOpts) WithMinioInfo() HealthInfoOpts {
+	return o.With(HealthDataTypeMinioInfo, HealthDataTypeMinioConfig)
+}
+
+// WithSysInfo - collect the system information of every node, except
+// for the process list.
+func (o HealthInfoOpts) WithSysInfo() HealthInfoOpts {
+	return o.With(
+		HealthDataTypeSysCPU,
+		HealthDataTypeSysDriveHw,
+		HealthDataTypeSysDocker,
+		HealthDataTypeSysOsInfo,
+		HealthDataTypeSysLoad,
+		HealthDataTypeSysMem,
+		HealthDataTypeSysNet,
+		HealthDataTypeSysErrors,
+		HealthDataTypeSysServices,
+		HealthDataTypeSysConfig,
+	)
+}
+
+// WithProcesses - collect the process list of every node.
+func (o HealthInfoOpts) WithProcesses() HealthInfoOpts {
+	return o.With(HealthDataTypeSysProcess)
+}
+
+// WithPerf - run the drive and network perf tests.
+func (o HealthInfoOpts) WithPerf() HealthInfoOpts {
+	return o.With(HealthDataTypesPerf...)
+}
+
+// With - collect the given health data types.
+func (o HealthInfoOpts) With(types ...HealthDataType) HealthInfoOpts {
+	o.types = append(append([]HealthDataType(nil), o.types...), types...)
+	return o
+}
+
+// Deadline - sets the time the server is allowed to spend collecting.
+func (o HealthInfoOpts) Deadline(d time.Duration) HealthInfoOpts {
+	o.deadline = d
+	return o
+}
+
+// Anonymize - sets the anonymization mode of the collected data.
+func (o HealthInfoOpts) Anonymize(mode string) HealthInfoOpts {
+	o.anonymize = mode
+	return o
+}
+
+// Types - returns the health data types to collect.
+func (o HealthInfoOpts) Types() []HealthDataType {
+	if len(o.types) == 0 {
+		return HealthDataTypesList
+	}
+	return o.types
+}
+
+// Query - returns the query parameters of the health info request.
+func (o HealthInfoOpts) Query() url.Values {
+	deadline := o.deadline
+	if deadline <= 0 {
+		deadline = DefaultHealthInfoDeadline
+	}
+	return healthInfoQuery(o.Types(), deadline, o.anonymize)
+}
+
+func healthInfoQuery(types []HealthDataType, deadline time.Duration, anonymize string) url.Values {
 	v := url.Values{}
 	v.Set("deadline", deadline.Truncate(1*time.Second).String())
 	v.Set("anonymize", anonymize)
@@ -515,7 +642,24 @@ func (adm *AdminClient) ServerHealthInfo(ctx context.Context, types []HealthData
 	for _, d := range types {
 		v.Set(string(d), "true")
 	}
+	return v
+}
 
+// ServerHealthInfo - Connect to a minio server and call Health Info Management API
+// to fetch server's information represented by HealthInfo structure
+func (adm *AdminClient) ServerHealthInfo(ctx context.Context, types []HealthDataType, deadline time.Duration, anonymize string) (*http.Response, string, error) {
+	resp, _, _, version, err := adm.serverHealthInfo(ctx, healthInfoQuery(types, deadline, anonymize))
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, version, nil
+}
+
+// serverHealthInfo - performs the health info request and decodes the first
+// streamed frame to find out the health info version. The decoder and the
+// raw first frame are returned as well so that callers reading the rest of
+// the stream do not lose any data buffered by the decoder.
+func (adm *AdminClient) serverHealthInfo(ctx context.Context, v url.Values) (*http.Response, *json.Decoder, json.RawMessage, string, error) {
 	resp, err := adm.executeMethod(
 		ctx, "GET", requestData{
 			relPath:     adminAPIPrefix + "/healthinfo",
@@ -524,32 +668,177 @@ func (adm *AdminClient) ServerHealthInfo(ctx context.Context, types []HealthData
 	)
 	if err != nil {
 		closeResponse(resp)
-		return nil, "", err
+		return nil, nil, nil, "", err
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		closeResponse(resp)
-		return nil, "", httpRespToErrorResponse(resp)
+		return nil, nil, nil, "", httpRespToErrorResponse(resp)
 	}
 
 	decoder := json.NewDecoder(resp.Body)
+	var first json.RawMessage
+	if err = decoder.Decode(&first); err != nil {
+		closeResponse(resp)
+		return nil, nil, nil, "", err
+	}
+
 	var version HealthInfoVersionStruct
-	if err = decoder.Decode(&version); err != nil {
+	if err = json.Unmarshal(first, &version); err != nil {
 		closeResponse(resp)
-		return nil, "", err
+		return nil, nil, nil, "", err
 	}
 
 	if version.Error != "" {
 		closeResponse(resp)
-		return nil, "", errors.New(version.Error)
+		return nil, nil, nil, "", errors.New(version.Error)
 	}
 
 	switch version.Version {
 	case "", HealthInfoVersion2, HealthInfoVersion:
 	default:
 		closeResponse(resp)
-		return nil, "", errors.New("Upgrade Minio Client to support health info version " + version.Version)
+		return nil, nil, nil, "", errors.New("Upgrade Minio Client to support health info version " + version.Version)
 	}
 
-	return resp, version.Version, nil
+	// Readers of the remaining frames must go through the decoder, make
+	// sure the response body reflects that as well.
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(decoder.Buffered(), resp.Body), resp.Body}
+
+	return resp, decoder, first, version.Version, nil
+}
+
+// ServerHealthInfoWithContext - Connect to a minio server and read the
+// streamed health info until the server is done, returning the most recent
+// complete HealthInfo. Reading is aborted as soon as ctx is canceled, in which
+// case the returned error wraps ctx.Err().
+func (adm *AdminClient) ServerHealthInfoWithContext(ctx context.Context, types []HealthDataType, deadline time.Duration, anonymize string) (HealthInfo, error) {
+	var info HealthInfo
+	err := adm.readHealthInfo(ctx, healthInfoQuery(types, deadline, anonymize), []string{HealthInfoVersion}, func(frame json.RawMessage) error {
+		var next HealthInfo
+		if err := json.Unmarshal(frame, &next); err != nil {
+			return err
+		}
+		info = next
+		return nil
+	})
+	if err != nil {
+		return HealthInfo{}, err
+	}
+	return info, nil
+}
+
+// ServerHealthInfoWithOpts - reads the streamed health info requested by
+// opts. The frames are decoded as HealthInfoV2, a superset of the current
+// HealthInfo, so that perf results requested with WithPerf are kept. Both
+// health info version 2 and the current version are accepted, the version
+// reported by the server is returned in the Version field.
+func (adm *AdminClient) ServerHealthInfoWithOpts(ctx context.Context, opts HealthInfoOpts) (HealthInfoV2, error) {
+	var info HealthInfoV2
+	err := adm.readHealthInfo(ctx, opts.Query(), []string{HealthInfoVersion2, HealthInfoVersion}, func(frame json.RawMessage) error {
+		var next HealthInfoV2
+		if err := json.Unmarshal(frame, &next); err != nil {
+			return err
+		}
+		info = next
+		return nil
+	})
+	if err != nil {
+		return HealthInfoV2{}, err
+	}
+	return info, nil
+}
+
+// ServerHealthInfoV2WithContext - same as ServerHealthInfoWithContext but for
+// servers reporting health info version 2.
+func (adm *AdminClient) ServerHealthInfoV2WithContext(ctx context.Context, types []HealthDataType, deadline time.Duration, anonymize string) (HealthInfoV2, error) {
+	var info HealthInfoV2
+	err := adm.readHealthInfo(ctx, healthInfoQuery(types, deadline, anonymize), []string{HealthInfoVersion2}, func(frame json.RawMessage) error {
+		var next HealthInfoV2
+		if err := json.Unmarshal(frame, &next); err != nil {
+			return err
+		}
+		info = next
+		return nil
+	})
+	if err != nil {
+		return HealthInfoV2{}, err
+	}
+	return info, nil
+}
+
+// ServerPerfInfo - Connect to a minio server and fetch only the drive and
+// network perf results of the health info, skipping the collection of the
+// system and minio information. Servers which do not support selective
+// collection send the full health info, the perf section is extracted from it.
+func (adm *AdminClient) ServerPerfInfo(ctx context.Context, deadline time.Duration) (PerfInfo, error) {
+	var perf PerfInfo
+	err := adm.readHealthInfo(ctx, healthInfoQuery(HealthDataTypesPerf, deadline, ""), nil, func(frame json.RawMessage) error {
+		var next struct {
+			Perf PerfInfo `json:"perf"`
+		}
+		if err := json.Unmarshal(frame, &next); err != nil {
+			return err
+		}
+		perf = next.Perf
+		return nil
+	})
+	if err != nil {
+		return PerfInfo{}, err
+	}
+	return perf, nil
+}
+
+// readHealthInfo - reads all streamed health info frames, passing each one
+// to fn. Any version is accepted if versions is empty. The context is checked between frames, and a read failing due to
+// cancellation reports the context error instead of the transport error.
+func (adm *AdminClient) readHealthInfo(ctx context.Context, v url.Values, versions []string, fn func(frame json.RawMessage) error) error {
+	resp, decoder, frame, version, err := adm.serverHealthInfo(ctx, v)
+	if err != nil {
+		if ctx.Err() != nil {
+			return healthInfoCtxErr(ctx)
+		}
+		return err
+	}
+	defer closeResponse(resp)
+
+	if len(versions) > 0 {
+		supported := false
+		for _, v := range versions {
+			supported = supported || v == version
+		}
+		if !supported {
+			return fmt.Errorf("unexpected health info version %q, expected one of %q", version, versions)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return healthInfoCtxErr(ctx)
+		default:
+		}
+		if err = fn(frame); err != nil {
+			return err
+		}
+		frame = nil
+		if err = decoder.Decode(&frame); err != nil {
+			if ctx.Err() != nil {
+				return healthInfoCtxErr(ctx)
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// healthInfoCtxErr wraps the context error, callers can
+// use errors.Is() to tell a timeout from a transport failure.
+func healthInfoCtxErr(ctx context.Context) error {
+	return fmt.Errorf("health info read aborted: %w", ctx.Err())
 }
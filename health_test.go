@@ -0,0 +1,787 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectPerfErrors(t *testing.T) {
+	perf := PerfInfo{
+		Drives: []DrivePerfInfos{
+			{NodeCommon: NodeCommon{Addr: "node1"}, SerialPerf: []DrivePerfInfo{{Path: "/d1"}}},
+			{
+				NodeCommon: NodeCommon{Addr: "node2", Error: "drive offline"},
+				SerialPerf: []DrivePerfInfo{{Path: "/d1", Error: "i/o error"}},
+			},
+			{NodeCommon: NodeCommon{Addr: "node3"}, SerialPerf: []DrivePerfInfo{{Path: "/d1"}}},
+			{
+				NodeCommon:   NodeCommon{Addr: "node4"},
+				ParallelPerf: []DrivePerfInfo{{Path: "/d1", Error: "i/o error"}},
+			},
+		},
+		Net: []NetPerfInfo{
+			{NodeCommon: NodeCommon{Addr: "node1"}},
+			{NodeCommon: NodeCommon{Addr: "node2"}},
+		},
+	}
+
+	errs := CollectPerfErrors(perf)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 nodes with errors, got %v", errs)
+	}
+	if errs["node2"] != "drive offline; i/o error" {
+		t.Errorf("Unexpected error for node2: %q", errs["node2"])
+	}
+	if errs["node4"] != "i/o error" {
+		t.Errorf("Unexpected error for node4: %q", errs["node4"])
+	}
+	for _, d := range perf.Drives {
+		if d.HasError() != (d.Addr == "node2") {
+			t.Errorf("Unexpected HasError() for %s", d.Addr)
+		}
+	}
+}
+
+func TestHealthInfoV0MarshalStable(t *testing.T) {
+	capture := func() HealthInfoV0 {
+		return HealthInfoV0{
+			TimeStamp: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+			Sys: SysHealthInfo{
+				CPUInfo: []ServerCPUInfo{{Addr: "node2"}, {Addr: "node1", Error: "no cpuinfo"}},
+				ProcInfo: []ServerProcInfo{{
+					Addr:      "node1",
+					Processes: []SysProcess{{Pid: 2, Name: "minio"}, {Pid: 1, Name: "init"}},
+				}},
+			},
+		}
+	}
+
+	a, err := capture().MarshalPretty()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := capture().MarshalPretty()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Fatalf("Expected identical output, got\n%s\n%s", a, b)
+	}
+	if !bytes.HasPrefix(a, []byte("{\n    \"sys\"")) {
+		t.Errorf("Unexpected indentation:\n%s", a)
+	}
+
+	c, err := capture().MarshalCompact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"sys":{"cpus":[{"addr":"node2"},{"addr":"node1","error":"no cpuinfo"}],` +
+		`"procinfos":[{"addr":"node1","processes":[{"name":"minio","pid":2},{"name":"init","pid":1}]}]},` +
+		`"timestamp":"2022-01-02T03:04:05Z"}`
+	if string(c) != want {
+		t.Errorf("Expected %s, got %s", want, c)
+	}
+}
+
+func TestTopSlowDrives(t *testing.T) {
+	perf := PerfInfo{
+		Drives: []DrivePerfInfos{
+			{
+				NodeCommon: NodeCommon{Addr: "node1"},
+				SerialPerf: []DrivePerfInfo{
+					{Path: "/d1", Latency: Latency{Percentile99: 0.2}},
+					{Path: "/d2", Latency: Latency{Percentile99: 0.9}},
+				},
+			},
+			{
+				NodeCommon:   NodeCommon{Addr: "node2"},
+				ParallelPerf: []DrivePerfInfo{{Path: "/d1", Latency: Latency{Percentile99: 0.5}}, {Path: "/d2", Error: "faulty"}},
+			},
+		},
+	}
+
+	top := TopSlowDrives(perf, 3)
+	if len(top) != 3 {
+		t.Fatalf("Expected 3 drives, got %d", len(top))
+	}
+	want := []string{"node2/d2", "node1/d2", "node2/d1"}
+	for i, d := range top {
+		if got := d.Addr + d.Path; got != want[i] {
+			t.Errorf("Expected %s at position %d, got %s", want[i], i, got)
+		}
+	}
+	if n := len(TopSlowDrives(perf, 10)); n != 4 {
+		t.Errorf("Expected 4 drives, got %d", n)
+	}
+}
+
+func TestBuildProcessTree(t *testing.T) {
+	procs := []SysProcess{
+		{Pid: 5, Ppid: 4},
+		{Pid: 1},
+		{Pid: 2, Ppid: 1},
+		{Pid: 3, Ppid: 2},
+		{Pid: 4, Ppid: 3},
+		{Pid: 100, Ppid: 42}, // orphan
+		{Pid: 200, Ppid: 201},
+		{Pid: 201, Ppid: 200}, // cycle
+	}
+
+	roots := BuildProcessTree(procs)
+	if len(roots) != 3 {
+		t.Fatalf("Expected 3 roots, got %d", len(roots))
+	}
+	if roots[0].Pid != 1 || roots[1].Pid != 100 || roots[2].Pid != 201 {
+		t.Errorf("Unexpected roots %d %d %d", roots[0].Pid, roots[1].Pid, roots[2].Pid)
+	}
+
+	depth := 0
+	for n := roots[0]; len(n.Children) > 0; n = n.Children[0] {
+		if len(n.Children) != 1 {
+			t.Fatalf("Expected one child for pid %d, got %d", n.Pid, len(n.Children))
+		}
+		depth++
+	}
+	if depth != 4 {
+		t.Errorf("Expected chain of depth 4, got %d", depth)
+	}
+	if len(roots[2].Children) != 1 || roots[2].Children[0].Pid != 200 {
+		t.Errorf("Expected pid 200 under pid 201")
+	}
+}
+
+func TestDiffHealthInfoV2(t *testing.T) {
+	before := HealthInfoV2{
+		Perf: PerfInfo{Drives: []DrivePerfInfos{
+			{NodeCommon: NodeCommon{Addr: "node1"}, SerialPerf: []DrivePerfInfo{
+				{Path: "/d1", Error: "faulty disk"},
+				{Path: "/d2", Latency: Latency{Percentile99: 0.100}},
+			}},
+			{NodeCommon: NodeCommon{Addr: "node2"}},
+		}},
+	}
+	after := HealthInfoV2{
+		Perf: PerfInfo{Drives: []DrivePerfInfos{
+			{NodeCommon: NodeCommon{Addr: "node1"}, SerialPerf: []DrivePerfInfo{
+				{Path: "/d1", Latency: Latency{Percentile99: 0.010}},
+				{Path: "/d2", Latency: Latency{Percentile99: 0.101}},
+			}},
+			{NodeCommon: NodeCommon{Addr: "node3"}},
+		}},
+	}
+
+	diff := DiffHealthInfoV2(before, after, 0.005)
+	if len(diff.NodesAdded) != 1 || diff.NodesAdded[0] != "node3" {
+		t.Errorf("Expected node3 to be added, got %v", diff.NodesAdded)
+	}
+	if len(diff.NodesRemoved) != 1 || diff.NodesRemoved[0] != "node2" {
+		t.Errorf("Expected node2 to be removed, got %v", diff.NodesRemoved)
+	}
+	if len(diff.DriveErrors) != 1 {
+		t.Fatalf("Expected 1 drive error change, got %v", diff.DriveErrors)
+	}
+	if c := diff.DriveErrors[0]; c.Path != "/d1" || c.Before != "faulty disk" || c.After != "" {
+		t.Errorf("Unexpected drive error change %+v", c)
+	}
+	// Only /d1 p99 moved beyond the threshold.
+	if len(diff.DriveLatencies) != 1 || diff.DriveLatencies[0].Path != "/d1" {
+		t.Errorf("Unexpected latency changes %+v", diff.DriveLatencies)
+	}
+}
+
+func TestHealthInfoRedact(t *testing.T) {
+	v2 := HealthInfoV2{Sys: SysInfo{ProcInfo: []ProcInfo{{PID: 10, Username: "minio-user", CWD: "/srv/minio"}}}}
+	v2.Redact(RedactOptions{RedactUsernames: true})
+	if p := v2.Sys.ProcInfo[0]; p.Username != RedactedValue || p.PID != 10 || p.CWD != "/srv/minio" {
+		t.Errorf("Unexpected redacted process %+v", p)
+	}
+
+	v0 := HealthInfoV0{Sys: SysHealthInfo{ProcInfo: []ServerProcInfo{{
+		Addr:      "node1",
+		Processes: []SysProcess{{Pid: 1, Username: "root"}, {Pid: 2, Username: "minio-user", Exe: "/usr/bin/minio"}},
+	}}}}
+	v0.Redact(RedactOptions{RedactUsernames: true, RedactPaths: true})
+	for i, p := range v0.Sys.ProcInfo[0].Processes {
+		if p.Username != RedactedValue {
+			t.Errorf("Expected username to be redacted, got %q", p.Username)
+		}
+		if p.Pid != int32(i+1) {
+			t.Errorf("Expected pid %d, got %d", i+1, p.Pid)
+		}
+	}
+	if exe := v0.Sys.ProcInfo[0].Processes[1].Exe; exe != RedactedValue {
+		t.Errorf("Expected exe to be redacted, got %q", exe)
+	}
+}
+
+func TestHealthInfoV2IsStale(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+	nowFunc = func() time.Time { return now }
+
+	info := HealthInfoV2{TimeStamp: now.Add(-time.Minute)}
+	if age := info.Age(); age != time.Minute {
+		t.Errorf("Expected age of 1m, got %v", age)
+	}
+	if info.IsStale(2 * time.Minute) {
+		t.Error("Expected fresh health info")
+	}
+	if !info.IsStale(30 * time.Second) {
+		t.Error("Expected stale health info")
+	}
+	if !(HealthInfoV2{}).IsStale(24 * time.Hour) {
+		t.Error("Expected health info without timestamp to be stale")
+	}
+}
+
+func TestSysHealthInfoErrors(t *testing.T) {
+	info := SysHealthInfo{
+		MemInfo:  []ServerMemInfo{{Addr: "node2", Error: "meminfo unavailable"}, {Addr: "node1"}},
+		ProcInfo: []ServerProcInfo{{Addr: "node3", Error: "permission denied"}, {Addr: "node1"}},
+		CPUInfo:  []ServerCPUInfo{{Addr: "node1"}},
+	}
+
+	errs := info.Errors()
+	want := []SubsystemError{
+		{Subsystem: "mem", Addr: "node2", Message: "meminfo unavailable"},
+		{Subsystem: "proc", Addr: "node3", Message: "permission denied"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, errs)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want[i], errs[i])
+		}
+	}
+}
+
+func TestThroughputHumanReadable(t *testing.T) {
+	testCases := []struct {
+		v    uint64
+		want string
+	}{
+		{0, "0 B/s"},
+		{1023, "1023 B/s"},
+		{1024, "1.0 KiB/s"},
+		{1288490189, "1.2 GiB/s"},
+		{1024*1024 - 1, "1.0 MiB/s"},
+	}
+	for _, tc := range testCases {
+		if got := (Throughput{Avg: tc.v}).AvgString(); got != tc.want {
+			t.Errorf("Expected %q for %d, got %q", tc.want, tc.v, got)
+		}
+	}
+	if h := (Throughput{}).HumanReadable(); h.Percentile99 != "0 B/s" {
+		t.Errorf("Expected zero throughput, got %q", h.Percentile99)
+	}
+}
+
+func TestPerfInfoValidate(t *testing.T) {
+	perf := PerfInfo{
+		Drives: []DrivePerfInfos{{
+			NodeCommon: NodeCommon{Addr: "node1"},
+			SerialPerf: []DrivePerfInfo{
+				{Path: "/d1", Latency: Latency{Avg: 0.01}},
+				{Path: "/d2"},                     // ambiguous, nothing measured
+				{Path: "/d3", Error: "i/o error"}, // failed, but reported
+				{Throughput: Throughput{Avg: 1}},  // missing path
+			},
+		}},
+		Net: []NetPerfInfo{{
+			NodeCommon:  NodeCommon{Addr: "node1"},
+			RemotePeers: []PeerNetPerfInfo{{NodeCommon: NodeCommon{Addr: "node2"}, Throughput: Throughput{Avg: 1}}},
+		}},
+	}
+
+	if err := (DrivePerfInfo{Path: "/d2"}).Validate(); err == nil {
+		t.Error("Expected an error for a drive without measurements or error")
+	}
+	if errs := perf.Validate(); len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %v", errs)
+	}
+}
+
+func TestHealthInfoV2Gob(t *testing.T) {
+	isKubernetes := true
+	info := HealthInfoV2{
+		Version:   HealthInfoVersion2,
+		TimeStamp: time.Date(2024, 2, 3, 4, 5, 6, 789, time.UTC),
+		Sys: SysInfo{
+			CPUInfo:   []CPUs{{NodeCommon: NodeCommon{Addr: "node1"}, CPUs: []CPU{{ModelName: "cpu", Cores: 8}}}},
+			SysConfig: []SysConfig{{NodeCommon: NodeCommon{Addr: "node1"}, Config: map[string]interface{}{"rlimit-max": float64(1024)}}},
+		},
+		Perf: PerfInfo{
+			Drives: []DrivePerfInfos{{
+				NodeCommon: NodeCommon{Addr: "node1"},
+				SerialPerf: []DrivePerfInfo{{Path: "/d1", Latency: Latency{Avg: 0.5}}},
+			}},
+		},
+		Minio: MinioHealthInfo{
+			Config: MinioConfig{Config: map[string]interface{}{"region": []interface{}{"us-east-1"}}},
+			Info: MinioInfo{
+				Mode:         "online",
+				Backend:      ErasureBackend{Type: "Erasure", OnlineDisks: 4},
+				IsKubernetes: &isKubernetes,
+			},
+		},
+	}
+
+	data, err := info.GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got HealthInfoV2
+	if err = got.GobDecode(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(info, got) {
+		t.Errorf("Expected\n%+v\ngot\n%+v", info, got)
+	}
+	if !got.TimeStamp.Equal(info.TimeStamp) {
+		t.Errorf("Expected timestamp %v, got %v", info.TimeStamp, got.TimeStamp)
+	}
+}
+
+func TestPerfInfoWritePrometheus(t *testing.T) {
+	perf := PerfInfo{
+		Drives: []DrivePerfInfos{{
+			NodeCommon: NodeCommon{Addr: "node1"},
+			SerialPerf: []DrivePerfInfo{
+				{Path: "/d1", Latency: Latency{Percentile99: 0.25}, Throughput: Throughput{Avg: 1024}},
+				{Path: "/d2", Error: "faulty"},
+			},
+		}},
+		Net: []NetPerfInfo{{
+			NodeCommon:  NodeCommon{Addr: "node1"},
+			RemotePeers: []PeerNetPerfInfo{{NodeCommon: NodeCommon{Addr: "node2"}, Throughput: Throughput{Avg: 2048}}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := perf.WritePrometheus(&buf, "c1"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE minio_drive_latency_p99_seconds gauge\n",
+		`minio_drive_latency_p99_seconds{cluster="c1",node="node1",path="/d1",mode="serial"} 0.25` + "\n",
+		`minio_drive_throughput_bytes{cluster="c1",node="node1",path="/d1",mode="serial"} 1024` + "\n",
+		`minio_drive_up{cluster="c1",node="node1",path="/d2",mode="serial"} 0` + "\n",
+		`minio_net_throughput_bytes{cluster="c1",node="node1",peer="node2",mode="serial"} 2048` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `path="/d2",mode="serial"} 0.`) || strings.Contains(out, `minio_drive_latency_avg_seconds{cluster="c1",node="node1",path="/d2"`) {
+		t.Errorf("Expected no measurements for failed drive, got\n%s", out)
+	}
+}
+
+func TestHealthInfoOptsQuery(t *testing.T) {
+	v := HealthInfoOpts{}.WithPerf().Deadline(90 * time.Second).Query()
+	if got := v.Get("deadline"); got != "1m30s" {
+		t.Errorf("Expected deadline 1m30s, got %q", got)
+	}
+	for _, d := range HealthDataTypesPerf {
+		if got := v.Get(string(d)); got != "true" {
+			t.Errorf("Expected %s=true, got %q", d, got)
+		}
+	}
+	for _, d := range HealthDataTypesList {
+		if got := v.Get(string(d)); got != "false" {
+			t.Errorf("Expected %s=false, got %q", d, got)
+		}
+	}
+
+	v = HealthInfoOpts{}.Query()
+	for _, d := range HealthDataTypesList {
+		if got := v.Get(string(d)); got != "true" {
+			t.Errorf("Expected %s=true by default, got %q", d, got)
+		}
+	}
+	if got := v.Get("deadline"); got != DefaultHealthInfoDeadline.String() {
+		t.Errorf("Expected default deadline, got %q", got)
+	}
+}
+
+func TestHealthInfoV2Fingerprint(t *testing.T) {
+	capture := func(deploymentID string, endpoints []string, ts time.Time) HealthInfoV2 {
+		info := HealthInfoV2{TimeStamp: ts}
+		info.Minio.Info.DeploymentID = deploymentID
+		for i, ep := range endpoints {
+			srv := ServerInfo{Endpoint: ep, Uptime: ts.Unix()}
+			for j := 0; j < 2; j++ {
+				srv.Drives = append(srv.Drives, Disk{Endpoint: ep, DiskIndex: i*2 + j, State: "ok"})
+			}
+			info.Minio.Info.Servers = append(info.Minio.Info.Servers, srv)
+		}
+		info.Perf.Drives = []DrivePerfInfos{{NodeCommon: NodeCommon{Addr: endpoints[0]}}}
+		return info
+	}
+
+	now := time.Now()
+	a := capture("deployment-1", []string{"node1:9000", "node2:9000"}, now)
+	b := capture("deployment-1", []string{"node2:9000", "node1:9000"}, now.Add(time.Hour))
+	b.Perf = PerfInfo{}
+	b.Minio.Info.Servers[0].Drives[0].State = "offline"
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("Expected captures of the same cluster to match, got %s and %s", a.Fingerprint(), b.Fingerprint())
+	}
+	if len(a.Fingerprint()) != 16 {
+		t.Errorf("Expected a 16 character fingerprint, got %q", a.Fingerprint())
+	}
+	if strings.Contains(a.Fingerprint(), "node1") {
+		t.Errorf("Fingerprint must not contain hostnames, got %q", a.Fingerprint())
+	}
+
+	c := capture("deployment-2", []string{"node1:9000", "node2:9000"}, now)
+	if a.Fingerprint() == c.Fingerprint() {
+		t.Errorf("Expected different deployments to differ, got %s", a.Fingerprint())
+	}
+	d := capture("deployment-1", []string{"node1:9000", "node2:9000", "node3:9000"}, now)
+	if a.Fingerprint() == d.Fingerprint() {
+		t.Errorf("Expected different topologies to differ, got %s", a.Fingerprint())
+	}
+}
+
+// healthInfoHandler - serves the given frames as a health info stream.
+func healthInfoHandler(t *testing.T, check func(r *http.Request), frames ...interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/healthinfo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if check != nil {
+			check(r)
+		}
+		enc := json.NewEncoder(w)
+		for _, f := range frames {
+			if err := enc.Encode(f); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}
+}
+
+func TestServerHealthInfoWithOptsPerf(t *testing.T) {
+	frame := HealthInfoV2{
+		Version: HealthInfoVersion2,
+		Perf: PerfInfo{
+			Drives: []DrivePerfInfos{{
+				NodeCommon: NodeCommon{Addr: "node1"},
+				SerialPerf: []DrivePerfInfo{{Path: "/d1", Latency: Latency{Avg: 0.5}}},
+			}},
+		},
+	}
+	adm := newTestAdminClient(t, healthInfoHandler(t, func(r *http.Request) {
+		q := r.URL.Query()
+		if q.Get(string(HealthDataTypePerfDrive)) != "true" || q.Get(string(HealthDataTypeSysCPU)) != "false" {
+			t.Errorf("Unexpected query %v", q)
+		}
+	}, frame))
+
+	info, err := adm.ServerHealthInfoWithOpts(context.Background(), HealthInfoOpts{}.WithPerf())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(info.Perf, frame.Perf) {
+		t.Errorf("Expected perf %+v, got %+v", frame.Perf, info.Perf)
+	}
+	if info.Version != HealthInfoVersion2 {
+		t.Errorf("Expected version %q, got %q", HealthInfoVersion2, info.Version)
+	}
+}
+
+func TestServerHealthInfoWithContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewEncoder(w).Encode(HealthInfo{Version: HealthInfoVersion}); err != nil {
+			t.Error(err)
+			return
+		}
+		w.(http.Flusher).Flush()
+		// Cancel once the first frame is out and stall the stream.
+		cancel()
+		<-r.Context().Done()
+	})
+
+	_, err := adm.ServerHealthInfoWithContext(ctx, HealthDataTypesList, time.Minute, "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected error wrapping %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestServerHealthInfoVersion(t *testing.T) {
+	testCases := []struct {
+		name    string
+		frame   HealthInfoVersionStruct
+		wantErr string
+	}{
+		{name: "current", frame: HealthInfoVersionStruct{Version: HealthInfoVersion}},
+		{name: "v2", frame: HealthInfoVersionStruct{Version: HealthInfoVersion2}},
+		{name: "unsupported", frame: HealthInfoVersionStruct{Version: "99"}, wantErr: "Upgrade Minio Client to support health info version 99"},
+		{name: "server error", frame: HealthInfoVersionStruct{Version: HealthInfoVersion, Error: "collection failed"}, wantErr: "collection failed"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			adm := newTestAdminClient(t, healthInfoHandler(t, nil, tc.frame))
+			resp, version, err := adm.ServerHealthInfo(context.Background(), HealthDataTypesList, time.Minute, "")
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("Expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closeResponse(resp)
+			if version != tc.frame.Version {
+				t.Errorf("Expected version %q, got %q", tc.frame.Version, version)
+			}
+		})
+	}
+}
+
+func TestServerHealthInfoMultiFrame(t *testing.T) {
+	frames := []interface{}{
+		HealthInfo{Version: HealthInfoVersion, TimeStamp: time.Unix(1, 0).UTC()},
+		HealthInfo{Version: HealthInfoVersion, TimeStamp: time.Unix(2, 0).UTC()},
+		HealthInfo{Version: HealthInfoVersion, TimeStamp: time.Unix(3, 0).UTC()},
+	}
+	adm := newTestAdminClient(t, healthInfoHandler(t, nil, frames...))
+
+	// The frames are small enough to be buffered by the decoder reading the
+	// version, the returned body must still yield the remaining ones.
+	resp, _, err := adm.ServerHealthInfo(context.Background(), HealthDataTypesList, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeResponse(resp)
+	var got []time.Time
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var info HealthInfo
+		if err = decoder.Decode(&info); err != nil {
+			break
+		}
+		got = append(got, info.TimeStamp)
+	}
+	want := []time.Time{time.Unix(2, 0).UTC(), time.Unix(3, 0).UTC()}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected remaining frames %v, got %v", want, got)
+	}
+
+	info, err := adm.ServerHealthInfoWithContext(context.Background(), HealthDataTypesList, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.TimeStamp.Equal(time.Unix(3, 0)) {
+		t.Errorf("Expected last frame, got timestamp %v", info.TimeStamp)
+	}
+}
+
+type ndjsonWriter struct {
+	bytes.Buffer
+	flushes  int
+	writeErr error
+	flushErr error
+}
+
+func (w *ndjsonWriter) Write(p []byte) (int, error) {
+	if w.writeErr != nil {
+		return 0, w.writeErr
+	}
+	return w.Buffer.Write(p)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	w.flushes++
+	return w.flushErr
+}
+
+func TestHealthInfoV2WriteNDJSON(t *testing.T) {
+	writeErr := errors.New("write failed")
+	flushErr := errors.New("flush failed")
+	info := HealthInfoV2{
+		Version:   HealthInfoVersion2,
+		TimeStamp: time.Unix(10, 0).UTC(),
+		Perf:      PerfInfo{Net: []NetPerfInfo{{NodeCommon: NodeCommon{Addr: "node1"}}}},
+	}
+	testCases := []struct {
+		name        string
+		w           *ndjsonWriter
+		wantErr     error
+		wantFlushes int
+		wantLines   []string
+	}{
+		{name: "all lines", w: &ndjsonWriter{}, wantFlushes: 3, wantLines: []string{"sys", "perf", "minio"}},
+		{name: "write error", w: &ndjsonWriter{writeErr: writeErr}, wantErr: writeErr},
+		{name: "flush error", w: &ndjsonWriter{flushErr: flushErr}, wantErr: flushErr, wantFlushes: 1, wantLines: []string{"sys"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := info.WriteNDJSON(tc.w); err != tc.wantErr {
+				t.Fatalf("Expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.w.flushes != tc.wantFlushes {
+				t.Errorf("Expected %d flushes, got %d", tc.wantFlushes, tc.w.flushes)
+			}
+			var got []string
+			for _, l := range strings.Split(strings.TrimSpace(tc.w.String()), "\n") {
+				if l == "" {
+					continue
+				}
+				var line struct {
+					Subsystem string          `json:"subsystem"`
+					TimeStamp time.Time       `json:"timestamp"`
+					Data      json.RawMessage `json:"data"`
+				}
+				if err := json.Unmarshal([]byte(l), &line); err != nil {
+					t.Fatal(err)
+				}
+				if !line.TimeStamp.Equal(info.TimeStamp) {
+					t.Errorf("Expected timestamp %v, got %v", info.TimeStamp, line.TimeStamp)
+				}
+				if line.Subsystem == "perf" {
+					var perf PerfInfo
+					if err := json.Unmarshal(line.Data, &perf); err != nil || !reflect.DeepEqual(perf, info.Perf) {
+						t.Errorf("Expected perf %+v, got %+v (%v)", info.Perf, perf, err)
+					}
+				}
+				got = append(got, line.Subsystem)
+			}
+			if !reflect.DeepEqual(got, tc.wantLines) {
+				t.Errorf("Expected subsystems %v, got %v", tc.wantLines, got)
+			}
+		})
+	}
+}
+
+func TestDetectAsymmetricLinks(t *testing.T) {
+	peer := func(addr string, avg uint64) PeerNetPerfInfo {
+		return PeerNetPerfInfo{NodeCommon: NodeCommon{Addr: addr}, Throughput: Throughput{Avg: avg}}
+	}
+	node := func(addr string, peers ...PeerNetPerfInfo) NetPerfInfo {
+		return NetPerfInfo{NodeCommon: NodeCommon{Addr: addr}, RemotePeers: peers}
+	}
+	testCases := []struct {
+		name  string
+		nets  []NetPerfInfo
+		ratio float64
+		want  []AsymmetricLink
+	}{
+		{
+			name:  "symmetric",
+			nets:  []NetPerfInfo{node("a", peer("b", 100)), node("b", peer("a", 110))},
+			ratio: 2,
+		},
+		{
+			name:  "asymmetric",
+			nets:  []NetPerfInfo{node("a", peer("b", 100)), node("b", peer("a", 300))},
+			ratio: 2,
+			want:  []AsymmetricLink{{A: "a", B: "b", AToB: 100, BToA: 300}},
+		},
+		{
+			name:  "asymmetric reported from either side",
+			nets:  []NetPerfInfo{node("b", peer("a", 10)), node("a", peer("b", 300))},
+			ratio: 2,
+			want:  []AsymmetricLink{{A: "a", B: "b", AToB: 300, BToA: 10}},
+		},
+		{
+			name:  "incomplete",
+			nets:  []NetPerfInfo{node("a", peer("b", 100)), node("b")},
+			ratio: 2,
+			want:  []AsymmetricLink{{A: "a", B: "b", AToB: 100, Incomplete: true}},
+		},
+		{
+			name: "failed and self measurements ignored",
+			nets: []NetPerfInfo{
+				node("a", peer("a", 1), peer("b", 100)),
+				node("b", PeerNetPerfInfo{NodeCommon: NodeCommon{Addr: "a", Error: "timeout"}}),
+			},
+			ratio: 2,
+			want:  []AsymmetricLink{{A: "a", B: "b", AToB: 100, Incomplete: true}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DetectAsymmetricLinks(tc.nets, tc.ratio)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPerfInfoByNode(t *testing.T) {
+	perf := PerfInfo{
+		Drives: []DrivePerfInfos{{NodeCommon: NodeCommon{Addr: "node1"}}, {NodeCommon: NodeCommon{Addr: "node2"}}},
+		Net:    []NetPerfInfo{{NodeCommon: NodeCommon{Addr: "node1"}}, {NodeCommon: NodeCommon{Addr: "node3"}}},
+		NetParallel: NetPerfInfo{
+			NodeCommon:  NodeCommon{Addr: "node1"},
+			RemotePeers: []PeerNetPerfInfo{{NodeCommon: NodeCommon{Addr: "node2"}}},
+		},
+	}
+	testCases := []struct {
+		addr                         string
+		drives, net, netParallel, ok bool
+	}{
+		{addr: "node1", drives: true, net: true, ok: true},
+		{addr: "node2", drives: true, netParallel: true, ok: true},
+		{addr: "node3", net: true, ok: true},
+		{addr: "node4"},
+	}
+	nodes := perf.ByNode()
+	if len(nodes) != 3 {
+		t.Errorf("Expected 3 nodes, got %d", len(nodes))
+	}
+	for _, tc := range testCases {
+		t.Run(tc.addr, func(t *testing.T) {
+			v, ok := nodes[tc.addr]
+			if ok != tc.ok {
+				t.Fatalf("Expected present %v, got %v", tc.ok, ok)
+			}
+			if (v.Drives != nil) != tc.drives || (v.Drives != nil && v.Drives.Addr != tc.addr) {
+				t.Errorf("Unexpected drives %+v", v.Drives)
+			}
+			if (v.Net != nil) != tc.net || (v.Net != nil && v.Net.Addr != tc.addr) {
+				t.Errorf("Unexpected net %+v", v.Net)
+			}
+			if (v.NetParallel != nil) != tc.netParallel || (v.NetParallel != nil && v.NetParallel.Addr != tc.addr) {
+				t.Errorf("Unexpected parallel net %+v", v.NetParallel)
+			}
+		})
+	}
+}
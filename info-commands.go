@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -55,6 +56,8 @@ const (
 	ItemInitializing = ItemState("initializing")
 	// ItemOnline indicates that the item is online
 	ItemOnline = ItemState("online")
+	// ItemMaintenance indicates that the item was taken down for maintenance
+	ItemMaintenance = ItemState("maintenance")
 )
 
 // StorageInfo - represents total capacity of underlying storage.
@@ -211,6 +214,10 @@ type DataUsageInfo struct {
 	TotalCapacity     uint64 `json:"capacity"`
 	TotalFreeCapacity uint64 `json:"freeCapacity"`
 	TotalUsedCapacity uint64 `json:"usedCapacity"`
+
+	// Scanner is the progress of the scanner cycle, only set by servers
+	// reporting it.
+	Scanner *DataUsageScannerInfo `json:"scanner,omitempty"`
 }
 
 // DataUsageInfo - returns data usage of the current object API
@@ -293,6 +300,147 @@ func (info InfoMessage) StandardParity() int {
 	}
 }
 
+// OfflineNode - a server which is not online
+type OfflineNode struct {
+	Endpoint string    `json:"endpoint"`
+	State    ItemState `json:"state"`
+	// Maintenance is set for servers taken down on purpose.
+	Maintenance bool `json:"maintenance,omitempty"`
+	// LastSeen is zero when the server does not report it.
+	LastSeen time.Time `json:"lastSeen,omitempty"`
+	// Uptime is the last uptime reported for the server.
+	Uptime time.Duration `json:"uptime,omitempty"`
+}
+
+// OfflineNodes returns the servers which are neither online nor
+// initializing, sorted by endpoint.
+func (info InfoMessage) OfflineNodes() []OfflineNode {
+	var nodes []OfflineNode
+	for _, srv := range info.Servers {
+		state := ItemState(srv.State)
+		if state == ItemOnline || state == ItemInitializing {
+			continue
+		}
+		n := OfflineNode{
+			Endpoint:    srv.Endpoint,
+			State:       state,
+			Maintenance: state == ItemMaintenance,
+			Uptime:      time.Duration(srv.Uptime) * time.Second,
+		}
+		if srv.LastSeen != nil {
+			n.LastSeen = *srv.LastSeen
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Endpoint < nodes[j].Endpoint
+	})
+	return nodes
+}
+
+// Uptimes returns the uptime of every online server by endpoint.
+func (info InfoMessage) Uptimes() map[string]time.Duration {
+	uptimes := make(map[string]time.Duration, len(info.Servers))
+	for _, srv := range info.Servers {
+		if ItemState(srv.State) == ItemOnline {
+			uptimes[srv.Endpoint] = time.Duration(srv.Uptime) * time.Second
+		}
+	}
+	return uptimes
+}
+
+// RestartCounts returns the restart count of every server reporting it,
+// by endpoint.
+func (info InfoMessage) RestartCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, srv := range info.Servers {
+		if srv.RestartCount != nil {
+			counts[srv.Endpoint] = *srv.RestartCount
+		}
+	}
+	return counts
+}
+
+// RecentlyRestartedNodes returns the sorted endpoints of the online
+// servers whose uptime is below threshold.
+func (info InfoMessage) RecentlyRestartedNodes(threshold time.Duration) []string {
+	var nodes []string
+	for endpoint, uptime := range info.Uptimes() {
+		if uptime < threshold {
+			nodes = append(nodes, endpoint)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// PoolSummary - drives and capacity of a single pool
+type PoolSummary struct {
+	Index          int    `json:"index"`
+	Sets           int    `json:"sets"`
+	Drives         int    `json:"drives"`
+	OnlineDrives   int    `json:"onlineDrives"`
+	OfflineDrives  int    `json:"offlineDrives"`
+	RawCapacity    uint64 `json:"rawCapacity"`
+	RawUsage       uint64 `json:"rawUsage"`
+	UsableCapacity uint64 `json:"usableCapacity"`
+}
+
+// PoolSummaries returns the drive counts and capacity of every pool sorted
+// by pool index, computed from the drives of all servers. A deployment
+// without pools returns a single summary. The usable capacity excludes the
+// standard storage class parity.
+func (info InfoMessage) PoolSummaries() []PoolSummary {
+	pools := make(map[int]*PoolSummary)
+	pool := func(idx int) *PoolSummary {
+		if idx < 0 {
+			// Drives not assigned to a set yet.
+			idx = 0
+		}
+		p, ok := pools[idx]
+		if !ok {
+			p = &PoolSummary{Index: idx}
+			pools[idx] = p
+		}
+		return p
+	}
+	for idx := range info.Backend.TotalSets {
+		pool(idx)
+	}
+	for _, srv := range info.Servers {
+		for _, d := range srv.Disks {
+			p := pool(d.PoolIndex)
+			p.Drives++
+			if d.State == DriveStateOk {
+				p.OnlineDrives++
+			} else {
+				p.OfflineDrives++
+			}
+			p.RawCapacity += d.TotalSpace
+			p.RawUsage += d.UsedSpace
+		}
+	}
+	if len(pools) == 0 {
+		pool(0)
+	}
+
+	res := make([]PoolSummary, 0, len(pools))
+	for idx, p := range pools {
+		if idx < len(info.Backend.TotalSets) {
+			p.Sets = info.Backend.TotalSets[idx]
+		}
+		p.UsableCapacity = p.RawCapacity
+		if idx < len(info.Backend.DrivesPerSet) {
+			if n := info.Backend.DrivesPerSet[idx]; n > 0 && info.Backend.StandardSCParity < n {
+				p.UsableCapacity = p.RawCapacity / uint64(n) * uint64(n-info.Backend.StandardSCParity)
+			}
+		}
+		res = append(res, *p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Index < res[j].Index })
+	return res
+}
+
 // Services contains different services information
 type Services struct {
 	KMS           KMS                           `json:"kms,omitempty"` // deprecated july 2023
@@ -417,6 +565,12 @@ type ServerProperties struct {
 	RuntimeVersion string            `json:"runtime_version,omitempty"`
 	GCStats        *GCStats          `json:"gc_stats,omitempty"`
 	MinioEnvVars   map[string]string `json:"minio_env_vars,omitempty"`
+	// LastSeen is the last time the server was reachable, only set by
+	// servers that keep track of it for offline peers.
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+	// RestartCount is the number of times the server process restarted,
+	// only set by servers that keep track of it.
+	RestartCount *int `json:"restartCount,omitempty"`
 }
 
 // DiskMetrics has the information about XL Storage APIs
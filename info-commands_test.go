@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInfoMessagePoolSummaries(t *testing.T) {
+	drive := func(pool int, state string) Disk {
+		return Disk{PoolIndex: pool, State: state, TotalSpace: 100, UsedSpace: 10}
+	}
+	info := InfoMessage{
+		Backend: ErasureBackend{
+			Type:             ErasureType,
+			StandardSCParity: 1,
+			TotalSets:        []int{1, 1},
+			DrivesPerSet:     []int{4, 4},
+		},
+		Servers: []ServerProperties{
+			{Disks: []Disk{drive(0, DriveStateOk), drive(0, DriveStateOk), drive(1, DriveStateOk), drive(1, DriveStateOk)}},
+			{Disks: []Disk{drive(0, DriveStateOk), drive(0, DriveStateOk), drive(1, DriveStateOk), drive(1, DriveStateOffline)}},
+		},
+	}
+
+	pools := info.PoolSummaries()
+	if len(pools) != 2 {
+		t.Fatalf("Expected 2 pools, got %d", len(pools))
+	}
+	want := []PoolSummary{
+		{Index: 0, Sets: 1, Drives: 4, OnlineDrives: 4, RawCapacity: 400, RawUsage: 40, UsableCapacity: 300},
+		{Index: 1, Sets: 1, Drives: 4, OnlineDrives: 3, OfflineDrives: 1, RawCapacity: 400, RawUsage: 40, UsableCapacity: 300},
+	}
+	for i := range want {
+		if pools[i] != want[i] {
+			t.Errorf("Pool %d: expected %+v, got %+v", i, want[i], pools[i])
+		}
+	}
+
+	single := InfoMessage{Servers: []ServerProperties{{Disks: []Disk{drive(-1, DriveStateOk)}}}}
+	if pools := single.PoolSummaries(); len(pools) != 1 || pools[0].Index != 0 || pools[0].Drives != 1 {
+		t.Errorf("Expected a single pool, got %+v", pools)
+	}
+}
+
+func TestInfoMessageOfflineNodes(t *testing.T) {
+	lastSeen := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	info := InfoMessage{
+		Servers: []ServerProperties{
+			{Endpoint: "node3:9000", State: string(ItemOffline), LastSeen: &lastSeen},
+			{Endpoint: "node1:9000", State: string(ItemOnline), Uptime: 3600},
+			{Endpoint: "node2:9000", State: string(ItemMaintenance), Uptime: 60},
+			{Endpoint: "node4:9000", State: string(ItemInitializing)},
+		},
+	}
+
+	nodes := info.OfflineNodes()
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 offline nodes, got %+v", nodes)
+	}
+	if n := nodes[0]; n.Endpoint != "node2:9000" || !n.Maintenance || n.Uptime != time.Minute || !n.LastSeen.IsZero() {
+		t.Errorf("Unexpected maintenance node %+v", n)
+	}
+	if n := nodes[1]; n.Endpoint != "node3:9000" || n.Maintenance || n.State != ItemOffline || !n.LastSeen.Equal(lastSeen) {
+		t.Errorf("Unexpected offline node %+v", n)
+	}
+}
+
+func TestInfoMessageUptimes(t *testing.T) {
+	restarts := 3
+	info := InfoMessage{
+		Servers: []ServerProperties{
+			{Endpoint: "node1:9000", State: string(ItemOnline), Uptime: 86400},
+			{Endpoint: "node2:9000", State: string(ItemOnline), Uptime: 120, RestartCount: &restarts},
+			{Endpoint: "node3:9000", State: string(ItemOffline)},
+		},
+	}
+
+	uptimes := info.Uptimes()
+	if len(uptimes) != 2 || uptimes["node1:9000"] != 24*time.Hour || uptimes["node2:9000"] != 2*time.Minute {
+		t.Errorf("Unexpected uptimes %v", uptimes)
+	}
+	if counts := info.RestartCounts(); len(counts) != 1 || counts["node2:9000"] != 3 {
+		t.Errorf("Unexpected restart counts %v", counts)
+	}
+	nodes := info.RecentlyRestartedNodes(10 * time.Minute)
+	if len(nodes) != 1 || nodes[0] != "node2:9000" {
+		t.Errorf("Expected node2 to be recently restarted, got %v", nodes)
+	}
+	if nodes = info.RecentlyRestartedNodes(time.Minute); len(nodes) != 0 {
+		t.Errorf("Expected no recently restarted nodes, got %v", nodes)
+	}
+}
@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"math"
+)
+
+// HealthStatus is the category of a HealthScore.
+type HealthStatus string
+
+// Health score categories.
+const (
+	HealthStatusHealthy  HealthStatus = "healthy"
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusCritical HealthStatus = "critical"
+)
+
+// Weights of the HealthScore components, they add up to 100. The drive
+// and node components are scaled by the ratio of online drives and nodes,
+// the healing component is granted only when no drive is healing.
+const (
+	HealthScoreDriveWeight   = 50
+	HealthScoreNodeWeight    = 40
+	HealthScoreHealingWeight = 10
+)
+
+// HealthScoreCriticalBelow - scores below this value are critical, scores
+// from this value up to 99 are degraded and only 100 is healthy. A cluster
+// with an erasure set below read quorum scores at most
+// HealthScoreCriticalBelow-1.
+const HealthScoreCriticalBelow = 50
+
+// HealthScore - overall health of a cluster computed from InfoMessage.
+type HealthScore struct {
+	Score               int          `json:"score"`
+	Status              HealthStatus `json:"status"`
+	OnlineDriveRatio    float64      `json:"onlineDriveRatio"`
+	OnlineNodeRatio     float64      `json:"onlineNodeRatio"`
+	Healing             bool         `json:"healing"`
+	SetsBelowReadQuorum int          `json:"setsBelowReadQuorum"`
+}
+
+type erasureSetKey struct {
+	pool, set int
+}
+
+// HealthScore - computes the 0 to 100 health score of the cluster from the
+// ratio of online drives and nodes and whether drives are healing, see
+// HealthScoreDriveWeight, HealthScoreNodeWeight and HealthScoreHealingWeight.
+// An erasure set is below read quorum when fewer of its drives than its
+// data drives of the standard storage class are online.
+//
+// Offline servers do not report their drives, the drive total and the
+// erasure sets are therefore taken from Backend whenever it provides them.
+func (info InfoMessage) HealthScore() HealthScore {
+	var (
+		hs                   HealthScore
+		drives, onlineDrives int
+		nodes, onlineNodes   int
+		setDrives, setOnline = make(map[erasureSetKey]int), make(map[erasureSetKey]int)
+	)
+	for _, srv := range info.Servers {
+		nodes++
+		if srv.State == string(ItemOnline) {
+			onlineNodes++
+		}
+		for _, d := range srv.Disks {
+			drives++
+			k := erasureSetKey{pool: d.PoolIndex, set: d.SetIndex}
+			setDrives[k]++
+			if d.State == DriveStateOk {
+				onlineDrives++
+				setOnline[k]++
+			}
+			if d.Healing {
+				hs.Healing = true
+			}
+		}
+	}
+	var topologyDrives int
+	for pool, sets := range info.Backend.TotalSets {
+		if pool < len(info.Backend.DrivesPerSet) {
+			topologyDrives += sets * info.Backend.DrivesPerSet[pool]
+		}
+	}
+	switch {
+	case info.Backend.OnlineDisks+info.Backend.OfflineDisks > 0:
+		drives, onlineDrives = info.Backend.OnlineDisks+info.Backend.OfflineDisks, info.Backend.OnlineDisks
+	case topologyDrives > drives:
+		drives = topologyDrives
+	}
+	for _, sets := range info.Pools {
+		for _, set := range sets {
+			if set.HealDisks > 0 {
+				hs.Healing = true
+			}
+		}
+	}
+
+	hs.OnlineDriveRatio, hs.OnlineNodeRatio = 1, 1
+	if drives > 0 {
+		hs.OnlineDriveRatio = math.Min(float64(onlineDrives)/float64(drives), 1)
+	}
+	if nodes > 0 {
+		hs.OnlineNodeRatio = float64(onlineNodes) / float64(nodes)
+	}
+
+	if topologyDrives > 0 {
+		// Sets whose drives all sit on offline servers are never
+		// reported, go through every set of the topology.
+		for pool, sets := range info.Backend.TotalSets {
+			if pool >= len(info.Backend.DrivesPerSet) {
+				continue
+			}
+			n := info.Backend.DrivesPerSet[pool]
+			for set := 0; set < sets; set++ {
+				if setOnline[erasureSetKey{pool: pool, set: set}] < n-info.Backend.StandardSCParity {
+					hs.SetsBelowReadQuorum++
+				}
+			}
+		}
+	} else {
+		for k, n := range setDrives {
+			if k.pool < 0 || k.set < 0 {
+				continue
+			}
+			if setOnline[k] < n-info.Backend.StandardSCParity {
+				hs.SetsBelowReadQuorum++
+			}
+		}
+	}
+
+	score := HealthScoreDriveWeight*hs.OnlineDriveRatio + HealthScoreNodeWeight*hs.OnlineNodeRatio
+	if !hs.Healing {
+		score += HealthScoreHealingWeight
+	}
+	hs.Score = int(math.Floor(score + 1e-9))
+	if hs.SetsBelowReadQuorum > 0 && hs.Score >= HealthScoreCriticalBelow {
+		hs.Score = HealthScoreCriticalBelow - 1
+	}
+
+	switch {
+	case hs.Score < HealthScoreCriticalBelow:
+		hs.Status = HealthStatusCritical
+	case hs.Score < 100:
+		hs.Status = HealthStatusDegraded
+	default:
+		hs.Status = HealthStatusHealthy
+	}
+	return hs
+}
@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"testing"
+)
+
+func TestInfoMessageHealthScore(t *testing.T) {
+	cluster := func(states ...string) InfoMessage {
+		info := InfoMessage{
+			Backend: ErasureBackend{
+				Type:             ErasureType,
+				StandardSCParity: 2,
+				TotalSets:        []int{1},
+				DrivesPerSet:     []int{4},
+			},
+		}
+		for i, state := range states {
+			info.Servers = append(info.Servers, ServerProperties{
+				State: string(ItemOnline),
+				Disks: []Disk{{State: state, PoolIndex: 0, SetIndex: 0, DiskIndex: i}},
+			})
+		}
+		return info
+	}
+
+	healthy := cluster(DriveStateOk, DriveStateOk, DriveStateOk, DriveStateOk).HealthScore()
+	if healthy.Score != 100 || healthy.Status != HealthStatusHealthy || healthy.SetsBelowReadQuorum != 0 {
+		t.Errorf("Expected a healthy cluster, got %+v", healthy)
+	}
+
+	info := cluster(DriveStateOk, DriveStateOk, DriveStateOk, DriveStateOffline)
+	info.Servers[3].State = string(ItemOffline)
+	info.Servers[0].Disks[0].Healing = true
+	degraded := info.HealthScore()
+	// 50*3/4 + 40*3/4 + 0 for healing
+	if degraded.Score != 67 || degraded.Status != HealthStatusDegraded || !degraded.Healing {
+		t.Errorf("Expected a degraded cluster, got %+v", degraded)
+	}
+
+	// Two online drives out of four with a parity of two still allow
+	// reads, one does not.
+	if hs := cluster(DriveStateOk, DriveStateOk, DriveStateOffline, DriveStateOffline).HealthScore(); hs.SetsBelowReadQuorum != 0 {
+		t.Errorf("Expected the set to have read quorum, got %+v", hs)
+	}
+	critical := cluster(DriveStateOk, DriveStateOffline, DriveStateOffline, DriveStateOffline).HealthScore()
+	if critical.Status != HealthStatusCritical || critical.SetsBelowReadQuorum != 1 || critical.Score >= HealthScoreCriticalBelow {
+		t.Errorf("Expected a critical cluster, got %+v", critical)
+	}
+}
+
+func TestInfoMessageHealthScoreOfflineNodes(t *testing.T) {
+	// Two sets of two drives spread over four nodes, one drive per node.
+	// The node holding drive 1 of set 1 is offline and reports no drives.
+	info := InfoMessage{
+		Backend: ErasureBackend{
+			Type:             ErasureType,
+			OnlineDisks:      3,
+			OfflineDisks:     1,
+			StandardSCParity: 1,
+			TotalSets:        []int{2},
+			DrivesPerSet:     []int{2},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		info.Servers = append(info.Servers, ServerProperties{
+			State: string(ItemOnline),
+			Disks: []Disk{{State: DriveStateOk, PoolIndex: 0, SetIndex: i / 2, DiskIndex: i % 2}},
+		})
+	}
+	info.Servers = append(info.Servers, ServerProperties{State: string(ItemOffline)})
+
+	testCases := []struct {
+		name                string
+		modify              func(info *InfoMessage)
+		onlineDriveRatio    float64
+		setsBelowReadQuorum int
+	}{
+		{"one offline node", func(*InfoMessage) {}, 0.75, 0},
+		{"set on offline nodes", func(info *InfoMessage) {
+			// Both drives of set 1 are on offline nodes.
+			info.Servers[2] = ServerProperties{State: string(ItemOffline)}
+			info.Backend.OnlineDisks, info.Backend.OfflineDisks = 2, 2
+		}, 0.5, 1},
+	}
+	for _, tc := range testCases {
+		info := info
+		info.Servers = append([]ServerProperties(nil), info.Servers...)
+		tc.modify(&info)
+		hs := info.HealthScore()
+		if hs.OnlineDriveRatio != tc.onlineDriveRatio || hs.SetsBelowReadQuorum != tc.setsBelowReadQuorum {
+			t.Errorf("%s: unexpected score %+v", tc.name, hs)
+		}
+	}
+}
@@ -20,14 +20,22 @@
 package madmin
 
 import (
+	"archive/zip"
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+
+	"github.com/secure-io/sio-go"
 )
 
 // InspectOptions provides options to Inspect.
@@ -108,3 +116,165 @@ type closeWrapper struct {
 	io.Reader
 	io.Closer
 }
+
+// InspectPart - a file of the inspected data along with its verification
+// status. Parts failing the verification are returned as well.
+type InspectPart struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	CRC32    uint32 `json:"crc32"` // checksum recorded in the archive
+	Verified bool   `json:"verified"`
+	Err      string `json:"err,omitempty"`
+	Data     []byte `json:"-"`
+}
+
+// InspectResult - inspected data verified by InspectWithVerify
+type InspectResult struct {
+	Parts []InspectPart `json:"parts"`
+
+	// Key is the data key returned by the server, it is nil when the
+	// data is encrypted with a public key.
+	Key []byte `json:"-"`
+	// Raw is the encrypted data as returned by the server.
+	Raw []byte `json:"-"`
+}
+
+// ErrInspectDecryptFailed is returned when the inspect data cannot be
+// decrypted with the given key.
+var ErrInspectDecryptFailed = errors.New("unable to decrypt inspect data")
+
+// Decrypt - decrypts the raw inspect data with key, the result is the
+// plain zip archive of the inspected files. The key is either the 32 bytes
+// data key or its hex form as printed by the client tools, prefixed with
+// its CRC32. ErrInspectDecryptFailed is returned if key does not match.
+func (r InspectResult) Decrypt(key []byte) (io.ReadCloser, error) {
+	key, err := parseInspectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	// Decrypt everything upfront, so a wrong key is reported
+	// before any data is returned.
+	data, err := ioutil.ReadAll(decryptInspectData(key, bytes.NewReader(r.Raw)))
+	if err != nil {
+		if errors.Is(err, sio.NotAuthentic) {
+			return nil, ErrInspectDecryptFailed
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInspectDecryptFailed, err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// parseInspectKey - returns the data key of its raw or hex form.
+func parseInspectKey(key []byte) ([]byte, error) {
+	switch len(key) {
+	case 32:
+		return key, nil
+	case 8 + 64:
+		id, err := hex.DecodeString(string(key[:8]))
+		if err != nil {
+			return nil, ErrInvalidArgument("invalid inspect key id")
+		}
+		k, err := hex.DecodeString(string(key[8:]))
+		if err != nil {
+			return nil, ErrInvalidArgument("invalid inspect key")
+		}
+		if binary.LittleEndian.Uint32(id) != crc32.ChecksumIEEE(k) {
+			return nil, ErrInvalidArgument("inspect key does not match its id")
+		}
+		return k, nil
+	}
+	return nil, ErrInvalidArgument("inspect key must be 32 bytes or 72 hex characters")
+}
+
+// Corrupted - returns the parts which failed the verification.
+func (r InspectResult) Corrupted() []InspectPart {
+	var parts []InspectPart
+	for _, p := range r.Parts {
+		if !p.Verified {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// InspectWithVerify - downloads the raw files like Inspect and checks
+// every file of the archive against the CRC32 recorded for it. The whole
+// data is held in memory. Data encrypted with a public key cannot be
+// verified.
+func (adm *AdminClient) InspectWithVerify(ctx context.Context, opts InspectOptions) (InspectResult, error) {
+	if opts.PublicKey != nil {
+		return InspectResult{}, errors.New("inspect data encrypted with a public key cannot be verified")
+	}
+	key, rc, err := adm.Inspect(ctx, opts)
+	if err != nil {
+		return InspectResult{}, err
+	}
+	defer rc.Close()
+	if key == nil {
+		return InspectResult{}, errors.New("no inspect data key returned")
+	}
+
+	res := InspectResult{Key: key}
+	if res.Raw, err = ioutil.ReadAll(rc); err != nil {
+		return InspectResult{}, err
+	}
+	plain, err := res.Decrypt(key)
+	if err != nil {
+		return InspectResult{}, err
+	}
+	defer plain.Close()
+	data, err := ioutil.ReadAll(plain)
+	if err != nil {
+		return InspectResult{}, err
+	}
+	if res.Parts, err = verifyInspectParts(data); err != nil {
+		return InspectResult{}, err
+	}
+	return res, nil
+}
+
+// decryptInspectData - the server encrypts the inspect data with a
+// random key used only once and a zero nonce.
+func decryptInspectData(key []byte, r io.Reader) io.Reader {
+	stream, err := sio.AES_256_GCM.Stream(key)
+	if err != nil {
+		return errReader{err: err}
+	}
+	nonce := make([]byte, stream.NonceSize())
+	return stream.DecryptReader(r, nonce, nil)
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func verifyInspectParts(data []byte) ([]InspectPart, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]InspectPart, 0, len(zr.File))
+	for _, f := range zr.File {
+		part := InspectPart{
+			Name:  f.Name,
+			Size:  int64(f.UncompressedSize64),
+			CRC32: f.CRC32,
+		}
+		rc, err := f.Open()
+		if err == nil {
+			// The checksum is verified once all data is read,
+			// the data read so far is kept on error.
+			part.Data, err = ioutil.ReadAll(rc)
+			rc.Close()
+		}
+		if err != nil {
+			part.Err = err.Error()
+		} else {
+			part.Verified = true
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
@@ -0,0 +1,141 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/secure-io/sio-go"
+)
+
+// encryptInspectData - encrypts data the way the server does.
+func encryptInspectData(t *testing.T, key, data []byte) []byte {
+	stream, err := sio.AES_256_GCM.Stream(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := stream.EncryptWriter(&buf, make([]byte, stream.NonceSize()), nil)
+	if _, err = w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspectWithVerify(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for _, f := range []struct{ name, data string }{
+		{"node1/disk1/bucket/object/xl.meta", "metadata-of-disk-one"},
+		{"node1/disk2/bucket/object/xl.meta", "metadata-of-disk-two"},
+	} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(f.data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Corrupt the stored data of the second file.
+	data := archive.Bytes()
+	i := bytes.Index(data, []byte("disk-two"))
+	data[i] = 'D'
+
+	key := make([]byte, 32)
+	rand.Read(key)
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte{1})
+		w.Write(key)
+		w.Write(encryptInspectData(t, key, data))
+	})
+
+	res, err := adm.InspectWithVerify(context.Background(), InspectOptions{Volume: "bucket", File: "object/xl.meta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Parts) != 2 {
+		t.Fatalf("Expected 2 parts, got %d", len(res.Parts))
+	}
+	if p := res.Parts[0]; !p.Verified || p.Err != "" || string(p.Data) != "metadata-of-disk-one" {
+		t.Errorf("Unexpected first part %+v", p)
+	}
+	corrupted := res.Corrupted()
+	if len(corrupted) != 1 || corrupted[0].Name != "node1/disk2/bucket/object/xl.meta" || corrupted[0].Err == "" {
+		t.Fatalf("Expected the second part to be corrupted, got %+v", corrupted)
+	}
+	if string(corrupted[0].Data) != "metadata-of-Disk-two" {
+		t.Errorf("Expected the corrupted data to be returned, got %q", corrupted[0].Data)
+	}
+}
+
+func TestInspectResultDecrypt(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	plain := bytes.Repeat([]byte("inspect data "), 10000)
+	res := InspectResult{Raw: encryptInspectData(t, key, plain)}
+
+	check := func(key []byte) {
+		t.Helper()
+		rc, err := res.Decrypt(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rc.Close()
+		got, err := ioutil.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Error("Decrypted data does not match")
+		}
+	}
+	check(key)
+
+	// Hex form of the key, prefixed with its checksum.
+	id := make([]byte, 4)
+	binary.LittleEndian.PutUint32(id, crc32.ChecksumIEEE(key))
+	check([]byte(hex.EncodeToString(id) + hex.EncodeToString(key)))
+
+	wrong := make([]byte, 32)
+	copy(wrong, key)
+	wrong[0] ^= 0xff
+	if _, err := res.Decrypt(wrong); !errors.Is(err, ErrInspectDecryptFailed) {
+		t.Errorf("Expected ErrInspectDecryptFailed, got %v", err)
+	}
+	badID := []byte("00000000" + hex.EncodeToString(key))
+	if _, err := res.Decrypt(badID); err == nil || errors.Is(err, ErrInspectDecryptFailed) {
+		t.Errorf("Expected an invalid key error, got %v", err)
+	}
+}
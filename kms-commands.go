@@ -20,13 +20,20 @@
 package madmin
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
 )
 
+// ErrKMSListUnsupported is returned when the KMS connected to the
+// MinIO server does not support listing keys.
+var ErrKMSListUnsupported = errors.New("kms: listing keys is not supported")
+
 // KMSStatus contains various informations about
 // the KMS connected to a MinIO server - like
 // the KMS endpoints and the default key ID.
@@ -58,6 +65,20 @@ type KMSKeyInfo struct {
 	CreatedAt string `json:"createdAt"`
 	CreatedBy string `json:"createdBy"`
 	Name      string `json:"name"`
+
+	// IsDefault is set by ListKeys, it is true for the
+	// key used when no explicit key is specified.
+	IsDefault bool `json:"isDefault,omitempty"`
+}
+
+// CreationTime returns the parsed CreatedAt timestamp, or the
+// zero time if the KMS didn't report a valid timestamp.
+func (k KMSKeyInfo) CreationTime() time.Time {
+	t, err := time.Parse(time.RFC3339Nano, k.CreatedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
 }
 
 // KMSPolicyInfo contains policy metadata
@@ -231,6 +252,30 @@ func (adm *AdminClient) CreateKey(ctx context.Context, keyID string) error {
 	return nil
 }
 
+// KMSCreateKeyWithPolicy creates a new master key with the given keyName and
+// sets a KMS policy of the same name from policy, which must follow the
+// KMSPolicy schema. If setting the policy fails the key is deleted again, so
+// no key is left behind without its policy.
+func (adm *AdminClient) KMSCreateKeyWithPolicy(ctx context.Context, keyName string, policy []byte) error {
+	var p KMSPolicy
+	dec := json.NewDecoder(bytes.NewReader(policy))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return ErrInvalidArgument(fmt.Sprintf("invalid KMS policy: %v", err))
+	}
+
+	if err := adm.CreateKey(ctx, keyName); err != nil {
+		return err
+	}
+	if err := adm.SetKMSPolicy(ctx, keyName, policy); err != nil {
+		if rerr := adm.DeleteKey(ctx, keyName); rerr != nil {
+			return fmt.Errorf("unable to set policy of key %s: %w (deleting the key failed: %v)", keyName, err, rerr)
+		}
+		return err
+	}
+	return nil
+}
+
 // DeleteKey tries to delete a key with the given keyID
 // at the KMS connected to a MinIO server.
 func (adm *AdminClient) DeleteKey(ctx context.Context, keyID string) error {
@@ -261,7 +306,10 @@ func (adm *AdminClient) ImportKey(ctx context.Context, keyID string, content []b
 	return nil
 }
 
-// ListKeys tries to get all key names that match the specified pattern
+// ListKeys tries to get all key names that match the specified pattern,
+// e.g. "app-*" lists the keys starting with "app-". The default key,
+// the KMSStatus.DefaultKeyID, is flagged with IsDefault. Returns
+// ErrKMSListUnsupported if the KMS backend can't list keys.
 func (adm *AdminClient) ListKeys(ctx context.Context, pattern string) ([]KMSKeyInfo, error) {
 	// GET /minio/kms/v1/key/list?pattern=<pattern>
 	resp, err := adm.doKMSRequest(ctx, "/key/list", http.MethodGet, nil, map[string]string{"pattern": pattern})
@@ -270,12 +318,24 @@ func (adm *AdminClient) ListKeys(ctx context.Context, pattern string) ([]KMSKeyI
 	}
 	defer closeResponse(resp)
 	if resp.StatusCode != http.StatusOK {
-		return nil, httpRespToErrorResponse(resp)
+		errResp := ToErrorResponse(httpRespToErrorResponse(resp))
+		if isNotSupportedResponse(resp, errResp) {
+			return nil, ErrKMSListUnsupported
+		}
+		return nil, errResp
 	}
 	var results []KMSKeyInfo
 	if err = json.NewDecoder(resp.Body).Decode(&results); err != nil {
 		return nil, err
 	}
+
+	status, err := adm.KMSStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].IsDefault = results[i].Name == status.DefaultKeyID
+	}
 	return results, nil
 }
 
@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListKeys(t *testing.T) {
+	keys := []KMSKeyInfo{
+		{Name: "app-key-1", CreatedAt: "2024-01-02T03:04:05Z"},
+		{Name: "app-key-2", CreatedAt: "2024-02-03T04:05:06Z"},
+	}
+	unsupported := false
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case libraryKMSURLPrefix + kmsAPIPrefix + "/status":
+			json.NewEncoder(w).Encode(KMSStatus{DefaultKeyID: "app-key-2"})
+		case libraryKMSURLPrefix + kmsAPIPrefix + "/key/list":
+			if unsupported {
+				w.WriteHeader(http.StatusNotImplemented)
+				return
+			}
+			if p := r.URL.Query().Get("pattern"); p != "app-*" {
+				t.Errorf("Expected pattern app-*, got %s", p)
+			}
+			json.NewEncoder(w).Encode(keys)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ctx := context.Background()
+	result, err := adm.ListKeys(ctx, "app-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(result))
+	}
+	if result[0].IsDefault || !result[1].IsDefault {
+		t.Errorf("Expected app-key-2 to be the default key, got %+v", result)
+	}
+	if !result[0].CreationTime().Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Unexpected creation time %v", result[0].CreationTime())
+	}
+
+	unsupported = true
+	if _, err = adm.ListKeys(ctx, "app-*"); !errors.Is(err, ErrKMSListUnsupported) {
+		t.Errorf("Expected ErrKMSListUnsupported, got %v", err)
+	}
+}
+
+func TestKMSCreateKeyWithPolicy(t *testing.T) {
+	var calls []string
+	policyFails := false
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path[len(libraryKMSURLPrefix+kmsAPIPrefix):])
+		if policyFails && r.URL.Path == libraryKMSURLPrefix+kmsAPIPrefix+"/policy/set" {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Code: "InternalError", Message: "policy store unavailable"})
+			return
+		}
+	})
+
+	ctx := context.Background()
+	policy := []byte(`{"allow":["/v1/key/generate/app-key"],"deny":[]}`)
+	if err := adm.KMSCreateKeyWithPolicy(ctx, "app-key", policy); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 || calls[0] != "/key/create" || calls[1] != "/policy/set" {
+		t.Errorf("Unexpected calls %v", calls)
+	}
+
+	calls, policyFails = nil, true
+	err := adm.KMSCreateKeyWithPolicy(ctx, "app-key", policy)
+	if ToErrorResponse(err).Code != "InternalError" {
+		t.Errorf("Expected policy error, got %v", err)
+	}
+	if len(calls) != 3 || calls[2] != "/key/delete" {
+		t.Errorf("Expected key to be deleted after policy failure, got calls %v", calls)
+	}
+
+	calls = nil
+	if err = adm.KMSCreateKeyWithPolicy(ctx, "app-key", []byte(`{"permit":[]}`)); err == nil {
+		t.Error("Expected invalid policy to be rejected")
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no calls for an invalid policy, got %v", calls)
+	}
+}
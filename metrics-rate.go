@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MetricRate - per second rate of a counter of a node
+type MetricRate struct {
+	Node   string  `json:"node"`
+	Name   string  `json:"name"`
+	PerSec float64 `json:"perSec"`
+}
+
+type metricSample struct {
+	at    time.Time
+	value uint64
+}
+
+// MetricsRateCalculator - computes the rates of the counters of successive
+// RealtimeMetrics samples, by node and counter name. Counters are the
+// lifetime operation counts of the scanner, drives and OS, the drive IO
+// stats and the network stats, named like "disk.ops.<op>" or
+// "net.rx_bytes". An interval in which a counter decreased, because it
+// was reset by a restart, is skipped.
+type MetricsRateCalculator struct {
+	mu   sync.Mutex
+	prev map[string]map[string]metricSample
+}
+
+// NewMetricsRateCalculator - returns a calculator without any prior sample.
+func NewMetricsRateCalculator() *MetricsRateCalculator {
+	return &MetricsRateCalculator{prev: make(map[string]map[string]metricSample)}
+}
+
+// Add - adds the sample taken at time at and returns the rates of all
+// counters since their previous sample, sorted by node and name. Metrics
+// without a node are reported for node "".
+func (c *MetricsRateCalculator) Add(m RealtimeMetrics, at time.Time) []MetricRate {
+	nodes := m.ByNode()
+	if len(nodes) == 0 {
+		nodes = map[string]Metrics{"": m.Aggregated}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var rates []MetricRate
+	for node, metrics := range nodes {
+		prev := c.prev[node]
+		cur := make(map[string]metricSample)
+		for name, v := range metricCounters(metrics) {
+			cur[name] = metricSample{at: at, value: v}
+			p, ok := prev[name]
+			if !ok || v < p.value {
+				continue
+			}
+			elapsed := at.Sub(p.at).Seconds()
+			if elapsed <= 0 {
+				continue
+			}
+			rates = append(rates, MetricRate{
+				Node:   node,
+				Name:   name,
+				PerSec: float64(v-p.value) / elapsed,
+			})
+		}
+		c.prev[node] = cur
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Node != rates[j].Node {
+			return rates[i].Node < rates[j].Node
+		}
+		return rates[i].Name < rates[j].Name
+	})
+	return rates
+}
+
+// Observe - returns a function adding every sample it receives, at the time
+// it is received, and calling out with the resulting rates. It can be passed
+// to Metrics directly.
+func (c *MetricsRateCalculator) Observe(out func([]MetricRate)) func(RealtimeMetrics) {
+	return func(m RealtimeMetrics) {
+		if rates := c.Add(m, nowFunc()); len(rates) > 0 {
+			out(rates)
+		}
+	}
+}
+
+// metricCounters - returns the counters of m by name.
+func metricCounters(m Metrics) map[string]uint64 {
+	counters := make(map[string]uint64)
+	addOps := func(prefix string, ops map[string]uint64) {
+		for k, v := range ops {
+			counters[prefix+k] = v
+		}
+	}
+	if m.Scanner != nil {
+		addOps("scanner.ops.", m.Scanner.LifeTimeOps)
+		addOps("scanner.ilm.", m.Scanner.LifeTimeILM)
+	}
+	if m.Disk != nil {
+		addOps("disk.ops.", m.Disk.LifeTimeOps)
+		io := m.Disk.IOStats
+		counters["disk.read_ios"] = io.ReadIOs
+		counters["disk.read_sectors"] = io.ReadSectors
+		counters["disk.write_ios"] = io.WriteIOs
+		counters["disk.write_sectors"] = io.WriteSectors
+	}
+	if m.OS != nil {
+		addOps("os.ops.", m.OS.LifeTimeOps)
+	}
+	if m.Net != nil {
+		s := m.Net.NetStats
+		counters["net.rx_bytes"] = s.RxBytes
+		counters["net.rx_packets"] = s.RxPackets
+		counters["net.rx_errors"] = s.RxErrors
+		counters["net.tx_bytes"] = s.TxBytes
+		counters["net.tx_packets"] = s.TxPackets
+		counters["net.tx_errors"] = s.TxErrors
+	}
+	return counters
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsRateCalculator(t *testing.T) {
+	sample := func(node1Ops, node2Ops uint64) RealtimeMetrics {
+		disk := func(ops uint64) Metrics {
+			return Metrics{Disk: &DiskMetric{LifeTimeOps: map[string]uint64{"read": ops}}}
+		}
+		return RealtimeMetrics{
+			Hosts:  []string{"node1", "node2"},
+			ByHost: map[string]Metrics{"node1": disk(node1Ops), "node2": disk(node2Ops)},
+		}
+	}
+	rate := func(rates []MetricRate, node, name string) (float64, bool) {
+		for _, r := range rates {
+			if r.Node == node && r.Name == name {
+				return r.PerSec, true
+			}
+		}
+		return 0, false
+	}
+
+	c := NewMetricsRateCalculator()
+	start := time.Now()
+	if rates := c.Add(sample(100, 1000), start); len(rates) != 0 {
+		t.Errorf("Expected no rates for the first sample, got %v", rates)
+	}
+
+	rates := c.Add(sample(300, 1500), start.Add(2*time.Second))
+	if r, ok := rate(rates, "node1", "disk.ops.read"); !ok || r != 100 {
+		t.Errorf("Expected node1 rate 100, got %v, %v", r, ok)
+	}
+	if r, ok := rate(rates, "node2", "disk.ops.read"); !ok || r != 250 {
+		t.Errorf("Expected node2 rate 250, got %v, %v", r, ok)
+	}
+	if len(rates) == 0 || rates[0].Node != "node1" {
+		t.Errorf("Expected rates sorted by node, got %v", rates)
+	}
+
+	// node2 restarted, its counter was reset.
+	rates = c.Add(sample(400, 50), start.Add(3*time.Second))
+	if _, ok := rate(rates, "node2", "disk.ops.read"); ok {
+		t.Error("Expected the interval with a counter reset to be skipped")
+	}
+	if r, ok := rate(rates, "node1", "disk.ops.read"); !ok || r != 100 {
+		t.Errorf("Expected node1 rate 100, got %v, %v", r, ok)
+	}
+
+	rates = c.Add(sample(500, 150), start.Add(4*time.Second))
+	if r, ok := rate(rates, "node2", "disk.ops.read"); !ok || r != 100 {
+		t.Errorf("Expected node2 rate 100 after the reset, got %v, %v", r, ok)
+	}
+}
@@ -117,6 +117,10 @@ func (adm *AdminClient) Metrics(ctx context.Context, o MetricsOptions, out func(
 			}
 			return err
 		}
+		if o.Type != MetricsNone {
+			// Older servers may return all metric types.
+			m.only(o.Type)
+		}
 		out(m)
 		if m.Final {
 			break
@@ -125,6 +129,25 @@ func (adm *AdminClient) Metrics(ctx context.Context, o MetricsOptions, out func(
 	return nil
 }
 
+// GetMetrics returns a single sample of the metric types selected by
+// opts.Type, all types when it is 0. Other types are dropped if the server
+// returns them anyway. opts.N is ignored.
+func (adm *AdminClient) GetMetrics(ctx context.Context, opts MetricsOptions) (RealtimeMetrics, error) {
+	opts.N = 1
+	var (
+		res      RealtimeMetrics
+		received bool
+	)
+	err := adm.Metrics(ctx, opts, func(m RealtimeMetrics) {
+		if !received {
+			res, received = m, true
+			return
+		}
+		res.Merge(&m)
+	})
+	return res, err
+}
+
 // Contains returns whether m contains all of x.
 func (m MetricType) Contains(x MetricType) bool {
 	return m&x == x
@@ -191,6 +214,59 @@ func (r *Metrics) Merge(other *Metrics) {
 	r.Net.Merge(other.Net)
 }
 
+// only drops the metrics not of type t.
+func (r *Metrics) only(t MetricType) {
+	if !t.Contains(MetricsScanner) {
+		r.Scanner = nil
+	}
+	if !t.Contains(MetricsDisk) {
+		r.Disk = nil
+	}
+	if !t.Contains(MetricsOS) {
+		r.OS = nil
+	}
+	if !t.Contains(MetricsBatchJobs) {
+		r.BatchJobs = nil
+	}
+	if !t.Contains(MetricsSiteResync) {
+		r.SiteResync = nil
+	}
+	if !t.Contains(MetricNet) {
+		r.Net = nil
+	}
+	if !t.Contains(MetricsMem) {
+		r.Mem = nil
+	}
+	if !t.Contains(MetricsCPU) {
+		r.CPU = nil
+	}
+}
+
+// only drops the metrics not of type t.
+func (r *RealtimeMetrics) only(t MetricType) {
+	r.Aggregated.only(t)
+	for host, m := range r.ByHost {
+		m.only(t)
+		r.ByHost[host] = m
+	}
+	if !t.Contains(MetricsDisk) {
+		r.ByDisk = nil
+	}
+}
+
+// ByNode returns the metrics of every node, keyed by host. The aggregated
+// metrics are returned for a single host when metrics by host were not
+// requested.
+func (r RealtimeMetrics) ByNode() map[string]Metrics {
+	if len(r.ByHost) > 0 {
+		return r.ByHost
+	}
+	if len(r.Hosts) == 1 {
+		return map[string]Metrics{r.Hosts[0]: r.Aggregated}
+	}
+	return nil
+}
+
 // Merge will merge other into r.
 func (r *RealtimeMetrics) Merge(other *RealtimeMetrics) {
 	if other == nil {
@@ -99,12 +99,15 @@ func getPrometheusToken(accessKey, secretKey string) (string, error) {
 func privateNewMetricsClient(endpointURL *url.URL, opts *Options) (*MetricsClient, error) {
 	clnt := new(MetricsClient)
 	clnt.creds = opts.Creds
+	if clnt.creds == nil && opts.CredsProvider != nil {
+		clnt.creds = credentials.New(opts.CredsProvider)
+	}
 	clnt.secure = opts.Secure
 	clnt.endpointURL = endpointURL
 
 	tr := opts.Transport
 	if tr == nil {
-		tr = DefaultTransport(opts.Secure)
+		tr = newTransport(opts.Secure, opts.TransportOptions)
 	}
 
 	clnt.httpClient = &http.Client{
@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestGetMetrics(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("types") != strconv.Itoa(int(MetricsDisk)) || q.Get("n") != "1" {
+			t.Errorf("Unexpected query %v", q)
+		}
+		// An old server returning all metric types.
+		full := Metrics{
+			Disk:    &DiskMetric{NDisks: 2},
+			CPU:     &CPUMetrics{},
+			Scanner: &ScannerMetrics{},
+		}
+		json.NewEncoder(w).Encode(RealtimeMetrics{
+			Hosts:      []string{"node1", "node2"},
+			Aggregated: full,
+			ByHost:     map[string]Metrics{"node1": full, "node2": full},
+			Final:      true,
+		})
+	})
+
+	m, err := adm.GetMetrics(context.Background(), MetricsOptions{Type: MetricsDisk, ByHost: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := m.ByNode()
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(nodes))
+	}
+	for host, n := range nodes {
+		if n.Disk == nil || n.Disk.NDisks != 2 || n.CPU != nil || n.Scanner != nil {
+			t.Errorf("Unexpected metrics of %s: %+v", host, n)
+		}
+	}
+	if m.Aggregated.CPU != nil || m.Aggregated.Disk == nil {
+		t.Errorf("Unexpected aggregated metrics %+v", m.Aggregated)
+	}
+
+	single := RealtimeMetrics{Hosts: []string{"node1"}, Aggregated: Metrics{CPU: &CPUMetrics{}}}
+	if n := single.ByNode(); len(n) != 1 || n["node1"].CPU == nil {
+		t.Errorf("Unexpected single node metrics %+v", n)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 )
 
@@ -45,6 +46,74 @@ type DrivePerf struct {
 	Error string `json:"error,omitempty"`
 }
 
+// DriveResult - drive speed test result of a drive along with its server
+type DriveResult struct {
+	Endpoint string `json:"endpoint"`
+	DrivePerf
+}
+
+// slowerThan - errored drives are slower than all others, otherwise the
+// drive with the lower of read and write throughput is slower.
+func (d DriveResult) slowerThan(o DriveResult) bool {
+	if (d.Error != "") != (o.Error != "") {
+		return d.Error != ""
+	}
+	dMin, dMax := minMaxUint64(d.ReadThroughput, d.WriteThroughput)
+	oMin, oMax := minMaxUint64(o.ReadThroughput, o.WriteThroughput)
+	if dMin != oMin {
+		return dMin < oMin
+	}
+	if dMax != oMax {
+		return dMax < oMax
+	}
+	return d.Path < o.Path
+}
+
+func minMaxUint64(a, b uint64) (uint64, uint64) {
+	if a < b {
+		return a, b
+	}
+	return b, a
+}
+
+// SlowestDrives - returns up to n drives, slowest first. Drives that
+// errored, or all drives of a server that errored, are the slowest.
+func (r DriveSpeedTestResult) SlowestDrives(n int) []DriveResult {
+	if n <= 0 {
+		return nil
+	}
+	drives := make([]DriveResult, 0, len(r.DrivePerf)+1)
+	for _, d := range r.DrivePerf {
+		if d.Error == "" && r.Error != "" {
+			d.Error = r.Error
+		}
+		drives = append(drives, DriveResult{Endpoint: r.Endpoint, DrivePerf: d})
+	}
+	if len(drives) == 0 && r.Error != "" {
+		drives = append(drives, DriveResult{Endpoint: r.Endpoint, DrivePerf: DrivePerf{Error: r.Error}})
+	}
+	sort.Slice(drives, func(i, j int) bool {
+		return drives[i].slowerThan(drives[j])
+	})
+	if n < len(drives) {
+		drives = drives[:n]
+	}
+	return drives
+}
+
+// AggregateThroughput - returns the sum of the read and write throughput
+// of all drives that did not error.
+func (r DriveSpeedTestResult) AggregateThroughput() (read, write uint64) {
+	for _, d := range r.DrivePerf {
+		if d.Error != "" {
+			continue
+		}
+		read += d.ReadThroughput
+		write += d.WriteThroughput
+	}
+	return read, write
+}
+
 // DriveSpeedTestOpts provide configurable options for drive speedtest
 type DriveSpeedTestOpts struct {
 	Serial    bool   // Run speed tests one drive at a time
@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"testing"
+)
+
+func TestDriveSpeedTestResultSlowestDrives(t *testing.T) {
+	res := DriveSpeedTestResult{
+		Endpoint: "node1:9000",
+		DrivePerf: []DrivePerf{
+			{Path: "/d1", ReadThroughput: 500, WriteThroughput: 400},
+			{Path: "/d2", ReadThroughput: 900, WriteThroughput: 100},
+			{Path: "/d3", Error: "i/o error"},
+			{Path: "/d4", ReadThroughput: 600, WriteThroughput: 450},
+		},
+	}
+
+	slowest := res.SlowestDrives(3)
+	var paths []string
+	for _, d := range slowest {
+		if d.Endpoint != "node1:9000" {
+			t.Errorf("Unexpected endpoint %q", d.Endpoint)
+		}
+		paths = append(paths, d.Path)
+	}
+	if len(paths) != 3 || paths[0] != "/d3" || paths[1] != "/d2" || paths[2] != "/d1" {
+		t.Errorf("Unexpected slowest drives %v", paths)
+	}
+	if len(res.SlowestDrives(10)) != 4 || res.SlowestDrives(0) != nil {
+		t.Error("Unexpected number of slowest drives")
+	}
+
+	read, write := res.AggregateThroughput()
+	if read != 2000 || write != 950 {
+		t.Errorf("Unexpected aggregate throughput %d/%d", read, write)
+	}
+
+	failed := DriveSpeedTestResult{Endpoint: "node2:9000", Error: "timeout"}
+	if d := failed.SlowestDrives(1); len(d) != 1 || d[0].Error != "timeout" {
+		t.Errorf("Expected the errored server to be reported, got %+v", d)
+	}
+}
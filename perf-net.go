@@ -22,8 +22,10 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
 )
 
@@ -40,6 +42,53 @@ type NetperfResult struct {
 	NodeResults []NetperfNodeResult `json:"nodeResults"`
 }
 
+// NodeOrder - returns the sorted endpoints of all servers of the result,
+// this is the row and column order of NetPerfMatrix.
+func (r NetperfResult) NodeOrder() []string {
+	seen := make(map[string]struct{})
+	var nodes []string
+	for _, n := range r.NodeResults {
+		if _, ok := seen[n.Endpoint]; !ok && n.Endpoint != "" {
+			seen[n.Endpoint] = struct{}{}
+			nodes = append(nodes, n.Endpoint)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// NetPerfMatrix - returns the N x N bandwidth matrix of the result in
+// NodeOrder, the cell [i][j] being the throughput sent from node i to
+// node j. Servers only report their total throughput to all peers, so
+// ErrNotSupported is returned for results of more than one server. Per
+// peer throughput is reported by the network perf of ServerPerfInfo.
+func NetPerfMatrix(result NetperfResult) ([][]uint64, error) {
+	m, _, err := netPerfMatrix(result)
+	return m, err
+}
+
+// NetPerfMeasured - returns which cells of NetPerfMatrix hold a
+// measurement, ErrNotSupported is returned like for NetPerfMatrix.
+func NetPerfMeasured(result NetperfResult) ([][]bool, error) {
+	_, measured, err := netPerfMatrix(result)
+	return measured, err
+}
+
+func netPerfMatrix(result NetperfResult) ([][]uint64, [][]bool, error) {
+	nodes := result.NodeOrder()
+	if len(nodes) > 1 {
+		return nil, nil, fmt.Errorf("%w: netperf reports the total throughput of each server, not per peer", ErrNotSupported)
+	}
+	// No pairs to measure.
+	m := make([][]uint64, len(nodes))
+	measured := make([][]bool, len(nodes))
+	for i := range nodes {
+		m[i] = make([]uint64, len(nodes))
+		measured[i] = make([]bool, len(nodes))
+	}
+	return m, measured, nil
+}
+
 // Netperf - perform netperf on the MinIO servers
 func (adm *AdminClient) Netperf(ctx context.Context, duration time.Duration) (result NetperfResult, err error) {
 	queryVals := make(url.Values)
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNetPerfMatrix(t *testing.T) {
+	testCases := []struct {
+		name         string
+		res          NetperfResult
+		wantOrder    []string
+		wantMatrix   [][]uint64
+		wantMeasured [][]bool
+		wantErr      error
+	}{
+		{
+			name: "three nodes",
+			res: NetperfResult{NodeResults: []NetperfNodeResult{
+				{Endpoint: "node3", TX: 31, RX: 13},
+				{Endpoint: "node1", TX: 12, RX: 21},
+				{Endpoint: "node2", Error: "connection reset"},
+			}},
+			wantOrder: []string{"node1", "node2", "node3"},
+			wantErr:   ErrNotSupported,
+		},
+		{
+			name:         "single node",
+			res:          NetperfResult{NodeResults: []NetperfNodeResult{{Endpoint: "node1"}}},
+			wantOrder:    []string{"node1"},
+			wantMatrix:   [][]uint64{{0}},
+			wantMeasured: [][]bool{{false}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if order := tc.res.NodeOrder(); !reflect.DeepEqual(order, tc.wantOrder) {
+				t.Errorf("Unexpected node order %v", order)
+			}
+			m, err := NetPerfMatrix(tc.res)
+			if !errors.Is(err, tc.wantErr) || !reflect.DeepEqual(m, tc.wantMatrix) {
+				t.Errorf("Expected matrix %v, %v, got %v, %v", tc.wantMatrix, tc.wantErr, m, err)
+			}
+			measured, err := NetPerfMeasured(tc.res)
+			if !errors.Is(err, tc.wantErr) || !reflect.DeepEqual(measured, tc.wantMeasured) {
+				t.Errorf("Expected measured %v, %v, got %v, %v", tc.wantMeasured, tc.wantErr, measured, err)
+			}
+		})
+	}
+}
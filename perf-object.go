@@ -23,6 +23,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -57,6 +59,44 @@ type SpeedTestResult struct {
 	GETStats   SpeedTestStats
 }
 
+// NodeSpeedContribution - throughput of a single server during a speedtest
+type NodeSpeedContribution struct {
+	Endpoint            string `json:"endpoint"`
+	PUTThroughputPerSec uint64 `json:"putThroughputPerSec"`
+	PUTObjectsPerSec    uint64 `json:"putObjectsPerSec"`
+	GETThroughputPerSec uint64 `json:"getThroughputPerSec"`
+	GETObjectsPerSec    uint64 `json:"getObjectsPerSec"`
+	Err                 string `json:"err,omitempty"`
+}
+
+// PerNode - returns the contribution of every server to the speedtest
+// result, keyed by server endpoint. The throughput of a stage a server
+// failed in is zero and its error is recorded.
+func (r SpeedTestResult) PerNode() map[string]NodeSpeedContribution {
+	nodes := make(map[string]NodeSpeedContribution, len(r.PUTStats.Servers))
+	for _, s := range r.PUTStats.Servers {
+		n := nodes[s.Endpoint]
+		n.Endpoint = s.Endpoint
+		if s.Err != "" {
+			n.Err = joinErrors(n.Err, "PUT: "+s.Err)
+		} else {
+			n.PUTThroughputPerSec, n.PUTObjectsPerSec = s.ThroughputPerSec, s.ObjectsPerSec
+		}
+		nodes[s.Endpoint] = n
+	}
+	for _, s := range r.GETStats.Servers {
+		n := nodes[s.Endpoint]
+		n.Endpoint = s.Endpoint
+		if s.Err != "" {
+			n.Err = joinErrors(n.Err, "GET: "+s.Err)
+		} else {
+			n.GETThroughputPerSec, n.GETObjectsPerSec = s.ThroughputPerSec, s.ObjectsPerSec
+		}
+		nodes[s.Endpoint] = n
+	}
+	return nodes
+}
+
 // SpeedtestOpts provide configurable options for speedtest
 type SpeedtestOpts struct {
 	Size         int           // Object size used in speed test
@@ -135,3 +175,51 @@ func (adm *AdminClient) Speedtest(ctx context.Context, opts SpeedtestOpts) (chan
 	}()
 	return ch, nil
 }
+
+// SpeedtestSweep - runs the object speedtest once per object size, one size
+// at a time. Results are aligned with sizes, the final result of every run
+// is kept. When ctx is canceled the results of the completed runs are
+// returned along with the context error. Sizes must be positive and unique,
+// autotune is not supported.
+func (adm *AdminClient) SpeedtestSweep(ctx context.Context, sizes []int64, opts SpeedtestOpts) ([]SpeedTestResult, error) {
+	if len(sizes) == 0 {
+		return nil, errors.New("at least one object size is required")
+	}
+	if opts.Autotune {
+		return nil, errors.New("autotune cannot be used with a size sweep")
+	}
+	seen := make(map[int64]struct{}, len(sizes))
+	for _, size := range sizes {
+		if size <= 0 || size > math.MaxInt32 {
+			return nil, fmt.Errorf("invalid object size %d", size)
+		}
+		if _, ok := seen[size]; ok {
+			return nil, fmt.Errorf("duplicate object size %d", size)
+		}
+		seen[size] = struct{}{}
+	}
+
+	results := make([]SpeedTestResult, 0, len(sizes))
+	for _, size := range sizes {
+		opts.Size = int(size)
+		ch, err := adm.Speedtest(ctx, opts)
+		if err != nil {
+			return results, err
+		}
+		var (
+			last SpeedTestResult
+			ok   bool
+		)
+		for r := range ch {
+			last, ok = r, true
+		}
+		if err = ctx.Err(); err != nil {
+			return results, err
+		}
+		if !ok {
+			return results, fmt.Errorf("speedtest with object size %d returned no result", size)
+		}
+		results = append(results, last)
+	}
+	return results, nil
+}
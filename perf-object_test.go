@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSpeedTestResultPerNode(t *testing.T) {
+	res := SpeedTestResult{
+		PUTStats: SpeedTestStats{Servers: []SpeedTestStatServer{
+			{Endpoint: "node1:9000", ThroughputPerSec: 1000, ObjectsPerSec: 10},
+			{Endpoint: "node2:9000", ThroughputPerSec: 900, ObjectsPerSec: 9},
+			{Endpoint: "node3:9000", ThroughputPerSec: 100, ObjectsPerSec: 1},
+		}},
+		GETStats: SpeedTestStats{Servers: []SpeedTestStatServer{
+			{Endpoint: "node1:9000", ThroughputPerSec: 2000, ObjectsPerSec: 20},
+			{Endpoint: "node2:9000", ThroughputPerSec: 1800, ObjectsPerSec: 18},
+			{Endpoint: "node3:9000", ThroughputPerSec: 150, Err: "disk timeout"},
+		}},
+	}
+
+	nodes := res.PerNode()
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(nodes))
+	}
+	want := NodeSpeedContribution{
+		Endpoint:            "node2:9000",
+		PUTThroughputPerSec: 900,
+		PUTObjectsPerSec:    9,
+		GETThroughputPerSec: 1800,
+		GETObjectsPerSec:    18,
+	}
+	if got := nodes["node2:9000"]; got != want {
+		t.Errorf("Unexpected node2 contribution %+v", got)
+	}
+
+	slow := nodes["node3:9000"]
+	if slow.PUTThroughputPerSec != 100 || slow.GETThroughputPerSec != 0 || slow.GETObjectsPerSec != 0 {
+		t.Errorf("Unexpected node3 contribution %+v", slow)
+	}
+	if slow.Err != "GET: disk timeout" {
+		t.Errorf("Unexpected node3 error %q", slow.Err)
+	}
+	if nodes["node1:9000"].Err != "" {
+		t.Errorf("Unexpected node1 error %q", nodes["node1:9000"].Err)
+	}
+}
+
+func TestSpeedtestSweep(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		size, err := strconv.Atoi(r.URL.Query().Get("size"))
+		if err != nil {
+			t.Error(err)
+		}
+		enc := json.NewEncoder(w)
+		// Intermediate results are followed by the final one.
+		enc.Encode(SpeedTestResult{Size: size, Concurrent: 1})
+		enc.Encode(SpeedTestResult{Size: size, Concurrent: 2})
+	})
+
+	opts := SpeedtestOpts{Concurrency: 4, Duration: 2 * time.Second}
+	sizes := []int64{64 << 20, 1 << 10, 4 << 20}
+	results, err := adm.SpeedtestSweep(context.Background(), sizes, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(sizes) {
+		t.Fatalf("Expected %d results, got %d", len(sizes), len(results))
+	}
+	for i, r := range results {
+		if int64(r.Size) != sizes[i] || r.Concurrent != 2 {
+			t.Errorf("Unexpected result %d: %+v", i, r)
+		}
+	}
+
+	for _, sizes := range [][]int64{nil, {1, 0}, {-1}, {1 << 20, 1 << 20}} {
+		if _, err = adm.SpeedtestSweep(context.Background(), sizes, opts); err == nil {
+			t.Errorf("Expected error for sizes %v", sizes)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = adm.SpeedtestSweep(ctx, sizes, opts)
+	if !errors.Is(err, context.Canceled) || len(results) != 0 {
+		t.Errorf("Expected no results and context.Canceled, got %v, %v", results, err)
+	}
+}
@@ -22,9 +22,14 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -167,6 +172,36 @@ func (adm *AdminClient) RemoveCannedPolicy(ctx context.Context, policyName strin
 	return nil
 }
 
+// ErrPolicyInUse is returned by RemoveCannedPolicySafe when the policy is
+// still attached to users or groups.
+var ErrPolicyInUse = errors.New("policy is attached")
+
+// RemoveCannedPolicySafe - same as RemoveCannedPolicy but refuses to
+// remove a policy which is still attached to users or groups, returning
+// ErrPolicyInUse naming them. With force the policy is removed regardless.
+func (adm *AdminClient) RemoveCannedPolicySafe(ctx context.Context, policyName string, force bool) error {
+	if !force {
+		r, err := adm.PolicyEntities(ctx, []string{policyName})
+		if err != nil {
+			return err
+		}
+		for _, m := range r.PolicyMappings {
+			if m.Policy != policyName || len(m.Users)+len(m.Groups) == 0 {
+				continue
+			}
+			var attached []string
+			if len(m.Users) > 0 {
+				attached = append(attached, "users "+strings.Join(m.Users, ", "))
+			}
+			if len(m.Groups) > 0 {
+				attached = append(attached, "groups "+strings.Join(m.Groups, ", "))
+			}
+			return fmt.Errorf("%w: %s is attached to %s", ErrPolicyInUse, policyName, strings.Join(attached, " and "))
+		}
+	}
+	return adm.RemoveCannedPolicy(ctx, policyName)
+}
+
 // AddCannedPolicy - adds a policy for a canned.
 func (adm *AdminClient) AddCannedPolicy(ctx context.Context, policyName string, policy []byte) error {
 	if policy == nil {
@@ -197,6 +232,145 @@ func (adm *AdminClient) AddCannedPolicy(ctx context.Context, policyName string,
 	return nil
 }
 
+// ValidatePolicyDocument - performs basic client side checks of a policy
+// document: it must be valid JSON with a Version and a Statement array, and
+// every statement must have an Effect, an Action and a Resource. Statements
+// granting only admin or kms actions do not need a Resource.
+func ValidatePolicyDocument(data []byte) error {
+	var doc struct {
+		Version   string            `json:"Version"`
+		Statement []json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid policy document: %w", err)
+	}
+	if doc.Version == "" {
+		return errors.New("invalid policy document: missing Version")
+	}
+	if len(doc.Statement) == 0 {
+		return errors.New("invalid policy document: missing Statement")
+	}
+
+	for i, raw := range doc.Statement {
+		var st struct {
+			Effect      string          `json:"Effect"`
+			Action      json.RawMessage `json:"Action"`
+			NotAction   json.RawMessage `json:"NotAction"`
+			Resource    json.RawMessage `json:"Resource"`
+			NotResource json.RawMessage `json:"NotResource"`
+		}
+		if err := json.Unmarshal(raw, &st); err != nil {
+			return fmt.Errorf("invalid policy statement %d: %w", i, err)
+		}
+		if st.Effect != "Allow" && st.Effect != "Deny" {
+			return fmt.Errorf("invalid policy statement %d: Effect must be Allow or Deny", i)
+		}
+		actionsRaw := st.Action
+		if actionsRaw == nil {
+			actionsRaw = st.NotAction
+		}
+		actions, err := policyStringOrSlice(actionsRaw)
+		if err != nil || len(actions) == 0 {
+			return fmt.Errorf("invalid policy statement %d: missing Action", i)
+		}
+		if st.Resource != nil || st.NotResource != nil {
+			continue
+		}
+		for _, a := range actions {
+			if !strings.HasPrefix(a, "admin:") && !strings.HasPrefix(a, "kms:") {
+				return fmt.Errorf("invalid policy statement %d: missing Resource", i)
+			}
+		}
+	}
+	return nil
+}
+
+// policyStringOrSlice - decodes a policy element which is either a string or a list of strings.
+func policyStringOrSlice(raw json.RawMessage) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []string{s}, nil
+	}
+	var ss []string
+	err := json.Unmarshal(raw, &ss)
+	return ss, err
+}
+
+// AddCannedPolicyValidated - same as AddCannedPolicy but validates the
+// policy document with ValidatePolicyDocument before uploading it.
+func (adm *AdminClient) AddCannedPolicyValidated(ctx context.Context, policyName string, policy []byte) error {
+	if err := ValidatePolicyDocument(policy); err != nil {
+		return ErrInvalidArgument(err.Error())
+	}
+	return adm.AddCannedPolicy(ctx, policyName, policy)
+}
+
+// maxAddCannedPoliciesConcurrency is the number of policies uploaded in
+// parallel by AddCannedPolicies.
+const maxAddCannedPoliciesConcurrency = 8
+
+// AddCannedPoliciesError is returned by AddCannedPolicies when one or more
+// policies could not be added, it lists both the failed and the added
+// policies so that the caller can resume.
+type AddCannedPoliciesError struct {
+	Succeeded []string
+	Failed    map[string]error
+}
+
+func (e AddCannedPoliciesError) Error() string {
+	names := make([]string, 0, len(e.Failed))
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, name+": "+e.Failed[name].Error())
+	}
+	return fmt.Sprintf("failed to add %d of %d policies: %s",
+		len(e.Failed), len(e.Failed)+len(e.Succeeded), strings.Join(msgs, "; "))
+}
+
+// AddCannedPolicies - adds multiple canned policies, keyed by policy name,
+// uploading up to 8 of them at a time. If any upload fails an
+// AddCannedPoliciesError is returned.
+func (adm *AdminClient) AddCannedPolicies(ctx context.Context, policies map[string][]byte) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, maxAddCannedPoliciesConcurrency)
+		pErr = AddCannedPoliciesError{Failed: make(map[string]error)}
+	)
+	for name, policy := range policies {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string, policy []byte) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := adm.AddCannedPolicy(ctx, name, policy)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				pErr.Failed[name] = err
+			} else {
+				pErr.Succeeded = append(pErr.Succeeded, name)
+			}
+		}(name, policy)
+	}
+	wg.Wait()
+
+	if len(pErr.Failed) > 0 {
+		sort.Strings(pErr.Succeeded)
+		return pErr
+	}
+	return nil
+}
+
 // SetPolicy - sets the policy for a user or a group.
 func (adm *AdminClient) SetPolicy(ctx context.Context, policyName, entityName string, isGroup bool) error {
 	queryValues := url.Values{}
@@ -297,6 +471,65 @@ func (adm *AdminClient) DetachPolicy(ctx context.Context, r PolicyAssociationReq
 	return adm.attachOrDetachPolicyBuiltin(ctx, false, r)
 }
 
+// policyAssociationReq - returns the request associating policies with a user or a group.
+func policyAssociationReq(policies []string, entityName string, isGroup bool) PolicyAssociationReq {
+	r := PolicyAssociationReq{Policies: policies}
+	if isGroup {
+		r.Group = entityName
+	} else {
+		r.User = entityName
+	}
+	return r
+}
+
+// AttachPolicies - attaches policies to a user or group, keeping the
+// policies already attached to it.
+func (adm *AdminClient) AttachPolicies(ctx context.Context, policies []string, entityName string, isGroup bool) error {
+	_, err := adm.AttachPolicy(ctx, policyAssociationReq(policies, entityName, isGroup))
+	return err
+}
+
+// DetachPolicies - detaches policies from a user or group, policies which
+// are not attached to it are ignored.
+func (adm *AdminClient) DetachPolicies(ctx context.Context, policies []string, entityName string, isGroup bool) error {
+	q := PolicyEntitiesQuery{Users: []string{entityName}}
+	if isGroup {
+		q = PolicyEntitiesQuery{Groups: []string{entityName}}
+	}
+	entities, err := adm.GetPolicyEntities(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	attached := make(map[string]struct{})
+	for _, m := range entities.UserMappings {
+		if !isGroup && m.User == entityName {
+			for _, p := range m.Policies {
+				attached[p] = struct{}{}
+			}
+		}
+	}
+	for _, m := range entities.GroupMappings {
+		if isGroup && m.Group == entityName {
+			for _, p := range m.Policies {
+				attached[p] = struct{}{}
+			}
+		}
+	}
+
+	var detach []string
+	for _, p := range policies {
+		if _, ok := attached[p]; ok {
+			detach = append(detach, p)
+		}
+	}
+	if len(detach) == 0 {
+		return nil
+	}
+	_, err = adm.DetachPolicy(ctx, policyAssociationReq(detach, entityName, isGroup))
+	return err
+}
+
 // GetPolicyEntities - returns builtin policy entities.
 func (adm *AdminClient) GetPolicyEntities(ctx context.Context, q PolicyEntitiesQuery) (r PolicyEntitiesResult, err error) {
 	params := make(url.Values)
@@ -327,3 +560,41 @@ func (adm *AdminClient) GetPolicyEntities(ctx context.Context, q PolicyEntitiesQ
 	err = json.Unmarshal(content, &r)
 	return r, err
 }
+
+// PolicyEntities - returns the users and groups every policy is attached
+// to, in the order of policies. Policies which are not attached anywhere
+// are returned with empty lists. Only the builtin identity provider is
+// queried, see GetLDAPPolicyEntities for LDAP mappings.
+func (adm *AdminClient) PolicyEntities(ctx context.Context, policies []string) (PolicyEntitiesResult, error) {
+	if len(policies) == 0 {
+		return PolicyEntitiesResult{}, ErrInvalidArgument("at least one policy is required")
+	}
+	r, err := adm.GetPolicyEntities(ctx, PolicyEntitiesQuery{Policy: policies})
+	if err != nil {
+		return r, err
+	}
+
+	found := make(map[string]PolicyEntities, len(r.PolicyMappings))
+	for _, m := range r.PolicyMappings {
+		found[m.Policy] = m
+	}
+	mappings := make([]PolicyEntities, 0, len(policies))
+	seen := make(map[string]struct{}, len(policies))
+	for _, p := range policies {
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		m := found[p]
+		m.Policy = p
+		if m.Users == nil {
+			m.Users = []string{}
+		}
+		if m.Groups == nil {
+			m.Groups = []string{}
+		}
+		mappings = append(mappings, m)
+	}
+	r.PolicyMappings = mappings
+	return r, nil
+}
@@ -21,7 +21,15 @@ package madmin
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -67,3 +75,291 @@ func TestPolicyInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePolicyDocument(t *testing.T) {
+	testCases := []struct {
+		doc     string
+		wantErr string
+	}{
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["admin:*"]},{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`, ""},
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/*"}]}`, ""},
+		{`{"Version":"2012-10-17","Statement":[`, "invalid policy document"},
+		{`{"Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`, "missing Version"},
+		{`{"Version":"2012-10-17","Statement":[]}`, "missing Statement"},
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]},{"Effect":"Permit","Action":["s3:*"]}]}`, "statement 1: Effect"},
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Resource":["arn:aws:s3:::*"]}]}`, "statement 0: missing Action"},
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":["s3:*"]}]}`, "statement 0: missing Resource"},
+	}
+	for i, tc := range testCases {
+		err := ValidatePolicyDocument([]byte(tc.doc))
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("Test %d: unexpected error %v", i, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("Test %d: expected error containing %q, got %v", i, tc.wantErr, err)
+		}
+	}
+}
+
+func TestPolicyEntities(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/idp/builtin/policy-entities" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query()["policy"]; !reflect.DeepEqual(got, []string{"readwrite", "unused"}) {
+			t.Errorf("Unexpected policies %v", got)
+		}
+		data, _ := json.Marshal(PolicyEntitiesResult{
+			PolicyMappings: []PolicyEntities{{Policy: "readwrite", Users: []string{"alice"}, Groups: []string{"devs"}}},
+		})
+		enc, err := EncryptData(testSecretKey, data)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(enc)
+	})
+
+	r, err := adm.PolicyEntities(context.Background(), []string{"readwrite", "unused"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []PolicyEntities{
+		{Policy: "readwrite", Users: []string{"alice"}, Groups: []string{"devs"}},
+		{Policy: "unused", Users: []string{}, Groups: []string{}},
+	}
+	if !reflect.DeepEqual(r.PolicyMappings, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, r.PolicyMappings)
+	}
+}
+
+func TestRemoveCannedPolicySafe(t *testing.T) {
+	var removed []string
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		policy := r.URL.Query().Get("policy")
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/idp/builtin/policy-entities":
+			var res PolicyEntitiesResult
+			if policy == "readwrite" {
+				res.PolicyMappings = []PolicyEntities{{Policy: policy, Users: []string{"alice"}, Groups: []string{"devs", "ops"}}}
+			}
+			data, _ := json.Marshal(res)
+			enc, err := EncryptData(testSecretKey, data)
+			if err != nil {
+				t.Error(err)
+			}
+			w.Write(enc)
+		case libraryAdminURLPrefix + adminAPIPrefix + "/remove-canned-policy":
+			removed = append(removed, r.URL.Query().Get("name"))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	err := adm.RemoveCannedPolicySafe(context.Background(), "readwrite", false)
+	if !errors.Is(err, ErrPolicyInUse) {
+		t.Fatalf("Expected ErrPolicyInUse, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "users alice and groups devs, ops") {
+		t.Errorf("Expected the attached entities in %q", err)
+	}
+	if len(removed) != 0 {
+		t.Fatalf("Expected the policy not to be removed, removed %v", removed)
+	}
+
+	if err = adm.RemoveCannedPolicySafe(context.Background(), "unused", false); err != nil {
+		t.Fatal(err)
+	}
+	if err = adm.RemoveCannedPolicySafe(context.Background(), "readwrite", true); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(removed, ",") != "unused,readwrite" {
+		t.Errorf("Unexpected removed policies %v", removed)
+	}
+}
+
+func TestAddCannedPolicies(t *testing.T) {
+	testCases := []struct {
+		name          string
+		policies      []string
+		wantSucceeded []string
+		wantFailed    []string
+	}{
+		{name: "all added", policies: []string{"p1", "p2", "p3"}},
+		{name: "many", policies: []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8", "p9", "p10", "p11", "p12"}},
+		{
+			name:          "partial failure",
+			policies:      []string{"p1", "bad1", "p2", "bad2"},
+			wantSucceeded: []string{"p1", "p2"},
+			wantFailed:    []string{"bad1", "bad2"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mu                 sync.Mutex
+				added              []string
+				inflight, maxInfly int32
+			)
+			adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/add-canned-policy" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+					return
+				}
+				n := atomic.AddInt32(&inflight, 1)
+				defer atomic.AddInt32(&inflight, -1)
+				for {
+					m := atomic.LoadInt32(&maxInfly)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInfly, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				name := r.URL.Query().Get("name")
+				if strings.HasPrefix(name, "bad") {
+					w.WriteHeader(http.StatusBadRequest)
+					json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioMalformedJSON", Message: "malformed policy"})
+					return
+				}
+				if body, _ := ioutil.ReadAll(r.Body); string(body) != `{"name":"`+name+`"}` {
+					t.Errorf("Unexpected policy %s for %s", body, name)
+				}
+				mu.Lock()
+				added = append(added, name)
+				mu.Unlock()
+			})
+
+			policies := make(map[string][]byte, len(tc.policies))
+			for _, name := range tc.policies {
+				policies[name] = []byte(`{"name":"` + name + `"}`)
+			}
+			err := adm.AddCannedPolicies(context.Background(), policies)
+			if n := atomic.LoadInt32(&maxInfly); n > maxAddCannedPoliciesConcurrency {
+				t.Errorf("Expected at most %d concurrent uploads, got %d", maxAddCannedPoliciesConcurrency, n)
+			}
+			if len(tc.wantFailed) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(added) != len(tc.policies) {
+					t.Errorf("Expected %d policies added, got %v", len(tc.policies), added)
+				}
+				return
+			}
+
+			var pErr AddCannedPoliciesError
+			if !errors.As(err, &pErr) {
+				t.Fatalf("Expected AddCannedPoliciesError, got %v", err)
+			}
+			if !reflect.DeepEqual(pErr.Succeeded, tc.wantSucceeded) {
+				t.Errorf("Expected succeeded %v, got %v", tc.wantSucceeded, pErr.Succeeded)
+			}
+			for _, name := range tc.wantFailed {
+				if pErr.Failed[name] == nil || !strings.Contains(err.Error(), name+": ") {
+					t.Errorf("Expected %s to be reported as failed in %q", name, err)
+				}
+			}
+			if len(pErr.Failed) != len(tc.wantFailed) {
+				t.Errorf("Expected %d failures, got %v", len(tc.wantFailed), pErr.Failed)
+			}
+		})
+	}
+}
+
+func TestAttachDetachPolicies(t *testing.T) {
+	testCases := []struct {
+		name     string
+		detach   bool
+		policies []string
+		isGroup  bool
+		attached []string
+		want     *PolicyAssociationReq
+	}{
+		{
+			name:     "attach user",
+			policies: []string{"readonly", "diagnostics"},
+			want:     &PolicyAssociationReq{Policies: []string{"readonly", "diagnostics"}, User: "alice"},
+		},
+		{
+			name:     "attach group",
+			policies: []string{"readonly"},
+			isGroup:  true,
+			want:     &PolicyAssociationReq{Policies: []string{"readonly"}, Group: "alice"},
+		},
+		{
+			name:     "detach attached only",
+			detach:   true,
+			policies: []string{"readwrite", "diagnostics"},
+			attached: []string{"readonly", "readwrite"},
+			want:     &PolicyAssociationReq{Policies: []string{"readwrite"}, User: "alice"},
+		},
+		{
+			name:     "detach group",
+			detach:   true,
+			policies: []string{"readonly"},
+			isGroup:  true,
+			attached: []string{"readonly"},
+			want:     &PolicyAssociationReq{Policies: []string{"readonly"}, Group: "alice"},
+		},
+		{
+			name:     "detach not attached is a no-op",
+			detach:   true,
+			policies: []string{"diagnostics"},
+			attached: []string{"readonly"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *PolicyAssociationReq
+			adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case libraryAdminURLPrefix + adminAPIPrefix + "/idp/builtin/policy-entities":
+					var res PolicyEntitiesResult
+					q := r.URL.Query()
+					if tc.isGroup && q.Get("group") == "alice" {
+						res.GroupMappings = []GroupPolicyEntities{{Group: "alice", Policies: tc.attached}}
+					}
+					if !tc.isGroup && q.Get("user") == "alice" {
+						res.UserMappings = []UserPolicyEntities{{User: "alice", Policies: tc.attached}}
+					}
+					data, _ := json.Marshal(res)
+					enc, err := EncryptData(testSecretKey, data)
+					if err != nil {
+						t.Error(err)
+					}
+					w.Write(enc)
+				case libraryAdminURLPrefix + adminAPIPrefix + "/idp/builtin/policy/attach",
+					libraryAdminURLPrefix + adminAPIPrefix + "/idp/builtin/policy/detach":
+					if isDetach := strings.HasSuffix(r.URL.Path, "/detach"); isDetach != tc.detach {
+						t.Errorf("Unexpected request %s", r.URL.Path)
+					}
+					data, err := DecryptData(testSecretKey, r.Body)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					got = &PolicyAssociationReq{}
+					if err = json.Unmarshal(data, got); err != nil {
+						t.Error(err)
+					}
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+			})
+
+			var err error
+			if tc.detach {
+				err = adm.DetachPolicies(context.Background(), tc.policies, "alice", tc.isGroup)
+			} else {
+				err = adm.AttachPolicies(context.Background(), tc.policies, "alice", tc.isGroup)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Expected request %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
@@ -20,6 +20,8 @@
 package madmin
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -28,6 +30,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
+	"strings"
 	"time"
 )
 
@@ -142,3 +146,138 @@ func (adm *AdminClient) Profile(ctx context.Context, profiler ProfilerType, dura
 	}
 	return resp.Body, nil
 }
+
+// ProfileCluster runs the profiler types on all the nodes of the cluster for
+// duration and returns a single zip archive holding one directory per node:
+//
+//	<node>/<profiler type>.pprof
+//
+// Files of the server archive that don't belong to a node are kept at the
+// root of the archive. The profiling is done by the server within a single
+// request, which stops the profilers when the request is aborted, so the
+// profilers don't keep running when ctx is canceled mid-run.
+func (adm *AdminClient) ProfileCluster(ctx context.Context, types []ProfilerType, duration time.Duration) (io.ReadCloser, error) {
+	if len(types) == 0 {
+		return nil, ErrInvalidArgument("at least one profiler type is required")
+	}
+	if duration <= 0 {
+		return nil, ErrInvalidArgument("profiling duration must be positive")
+	}
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, string(t))
+	}
+
+	rc, err := adm.Profile(ctx, ProfilerType(strings.Join(names, ",")), duration)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		if err = copyProfileFile(zw, f, profileFileName(f.Name, types)); err != nil {
+			return nil, err
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(&buf), nil
+}
+
+// profileFileName maps the server profile file name, of the form
+// profile-<node>-<type>.<ext>, to <node>/<type>.<ext>. Other names
+// are returned unchanged.
+func profileFileName(name string, types []ProfilerType) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if !strings.HasPrefix(base, "profile-") {
+		return name
+	}
+	base = strings.TrimPrefix(base, "profile-")
+	for _, t := range types {
+		if node := strings.TrimSuffix(base, "-"+string(t)); node != base && node != "" {
+			return node + "/" + string(t) + ext
+		}
+	}
+	return name
+}
+
+func copyProfileFile(zw *zip.Writer, f *zip.File, name string) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   f.Method,
+		Modified: f.Modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// CPUProfile returns the CPU profile of node over duration, in the pprof
+// format consumable by `go tool pprof`. node is the node address as reported
+// in ServerProperties.Endpoint, an empty node returns the profile of any one
+// node. The server profiles all the nodes, only the profile of node is
+// returned.
+func (adm *AdminClient) CPUProfile(ctx context.Context, node string, duration time.Duration) (io.ReadCloser, error) {
+	if duration <= 0 {
+		return nil, ErrInvalidArgument("profiling duration must be positive")
+	}
+	rc, err := adm.Profile(ctx, ProfilerCPU, duration)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	types := []ProfilerType{ProfilerCPU}
+	for _, f := range zr.File {
+		name := profileFileName(f.Name, types)
+		if name == f.Name {
+			continue
+		}
+		if node != "" && path.Dir(name) != node {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		profile, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(profile)), nil
+	}
+	if node == "" {
+		return nil, errors.New("no CPU profile returned by the server")
+	}
+	return nil, fmt.Errorf("no CPU profile returned for node %s", node)
+}
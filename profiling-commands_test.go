@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProfileCluster(t *testing.T) {
+	serverFiles := map[string]string{
+		"profile-node-1:9000-cpu.pprof": "cpu1",
+		"profile-node-1:9000-mem.pprof": "mem1",
+		"profile-node-2:9000-cpu.pprof": "cpu2",
+		"profile-node-2:9000-mem.pprof": "mem2",
+		"cluster.info":                  "info",
+	}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("profilerType") != "cpu,mem" || q.Get("duration") != "10s" {
+			t.Errorf("Unexpected profile query %v", q)
+		}
+		zw := zip.NewWriter(w)
+		for name, content := range serverFiles {
+			fw, err := zw.Create(name)
+			if err != nil {
+				t.Error(err)
+			}
+			fw.Write([]byte(content))
+		}
+		zw.Close()
+	})
+
+	rc, err := adm.ProfileCluster(context.Background(), []ProfilerType{ProfilerCPU, ProfilerMEM}, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"node-1:9000/cpu.pprof": "cpu1",
+		"node-1:9000/mem.pprof": "mem1",
+		"node-2:9000/cpu.pprof": "cpu2",
+		"node-2:9000/mem.pprof": "mem2",
+		"cluster.info":          "info",
+	}
+	if len(zr.File) != len(expected) {
+		t.Fatalf("Expected %d files, got %d", len(expected), len(zr.File))
+	}
+	for _, f := range zr.File {
+		remaining := int64(maxConfigBundleSize)
+		err = readConfigBundleFile(f, &remaining, func(b []byte) error {
+			if want, ok := expected[f.Name]; !ok || want != string(b) {
+				t.Errorf("Unexpected file %s with content %q", f.Name, b)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = adm.ProfileCluster(context.Background(), []ProfilerType{ProfilerCPU}, 0); err == nil {
+		t.Error("Expected an error for a zero duration")
+	}
+}
+
+func TestCPUProfile(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("profilerType") != "cpu" {
+			t.Errorf("Unexpected profiler type %s", r.URL.Query().Get("profilerType"))
+		}
+		zw := zip.NewWriter(w)
+		for _, node := range []string{"node-1:9000", "node-2:9000"} {
+			fw, err := zw.Create("profile-" + node + "-cpu.pprof")
+			if err != nil {
+				t.Error(err)
+			}
+			fw.Write([]byte("cpu " + node))
+		}
+		zw.Close()
+	})
+
+	testCases := []struct {
+		node     string
+		expected string
+	}{
+		{node: "node-2:9000", expected: "cpu node-2:9000"},
+		{node: "", expected: "cpu node-1:9000"},
+	}
+	for i, tc := range testCases {
+		rc, err := adm.CPUProfile(context.Background(), tc.node, 30*time.Second)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tc.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, tc.expected, data)
+		}
+	}
+
+	if _, err := adm.CPUProfile(context.Background(), "node-3:9000", 30*time.Second); err == nil {
+		t.Error("Expected an error for an unknown node")
+	}
+	if _, err := adm.CPUProfile(context.Background(), "", -time.Second); err == nil {
+		t.Error("Expected an error for a negative duration")
+	}
+}
@@ -25,22 +25,30 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // QuotaType represents bucket quota type
 type QuotaType string
 
+// Supported quota types.
 const (
 	// HardQuota specifies a hard quota of usage for bucket
 	HardQuota QuotaType = "hard"
+	// FIFOQuota specifies a quota enforced by removing the oldest objects
+	// of the bucket, only honored by servers supporting it.
+	FIFOQuota QuotaType = "fifo"
 )
 
-// IsValid returns true if quota type is one of Hard
+// IsValid returns true if quota type is one of Hard or FIFO
 func (t QuotaType) IsValid() bool {
-	return t == HardQuota
+	return t == HardQuota || t == FIFOQuota
 }
 
-// BucketQuota holds bucket quota restrictions
+// BucketQuota holds bucket quota restrictions. The maximum bucket size is
+// read from Size, the deprecated Quota field is only used when Size is zero.
 type BucketQuota struct {
 	Quota    uint64    `json:"quota"`    // Deprecated Aug 2023
 	Size     uint64    `json:"size"`     // Indicates maximum size allowed per bucket
@@ -49,8 +57,32 @@ type BucketQuota struct {
 	Type     QuotaType `json:"quotatype,omitempty"`
 }
 
+// Validate returns an error if the quota is invalid.
+//
+// Size takes precedence over the deprecated Quota field, a quota is set when
+// either of them is non-zero and it then requires a valid Type. A quota with
+// neither of them set must not have a Type, except for the zero value
+// BucketQuota{} which removes the bucket quota.
+func (q BucketQuota) Validate() error {
+	limit := q.Size
+	if limit == 0 {
+		limit = q.Quota
+	}
+	if limit == 0 {
+		if q.Type != "" {
+			return ErrInvalidArgument("quota type " + string(q.Type) + " given without a quota size")
+		}
+		return nil
+	}
+	if !q.Type.IsValid() {
+		return ErrInvalidArgument("invalid quota type '" + string(q.Type) + "', must be one of hard or fifo")
+	}
+	return nil
+}
+
 // IsValid returns false if quota is invalid
 // empty quota when Quota == 0 is always true.
+// Use Validate for the stricter checks applied by SetBucketQuota.
 func (q BucketQuota) IsValid() bool {
 	if q.Quota > 0 {
 		return q.Type.IsValid()
@@ -92,9 +124,17 @@ func (adm *AdminClient) GetBucketQuota(ctx context.Context, bucket string) (q Bu
 	return q, nil
 }
 
-// SetBucketQuota - sets a bucket's quota, if quota is set to '0'
-// quota is disabled.
+// SetBucketQuota - sets a bucket's quota, if quota is nil or set to the
+// zero value BucketQuota{} the bucket quota is removed. The quota is
+// validated with BucketQuota.Validate before being sent, so a Type given
+// without a Size or Quota is rejected. Size takes precedence over Quota.
 func (adm *AdminClient) SetBucketQuota(ctx context.Context, bucket string, quota *BucketQuota) error {
+	if quota == nil {
+		quota = &BucketQuota{}
+	}
+	if err := quota.Validate(); err != nil {
+		return err
+	}
 	data, err := json.Marshal(quota)
 	if err != nil {
 		return err
@@ -123,3 +163,74 @@ func (adm *AdminClient) SetBucketQuota(ctx context.Context, bucket string, quota
 
 	return nil
 }
+
+// maxBucketQuotaConcurrency is the number of quotas fetched in parallel by GetAllBucketQuotas.
+const maxBucketQuotaConcurrency = 8
+
+// BucketQuotaErrors is returned by GetAllBucketQuotas with
+// the error of every bucket whose quota could not be fetched.
+type BucketQuotaErrors map[string]error
+
+func (e BucketQuotaErrors) Error() string {
+	buckets := make([]string, 0, len(e))
+	for bucket := range e {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+	msgs := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		msgs = append(msgs, bucket+": "+e[bucket].Error())
+	}
+	return "unable to get bucket quotas: " + strings.Join(msgs, "; ")
+}
+
+// isSet returns true if any limit of the quota is set
+func (q BucketQuota) isSet() bool {
+	return q.Quota > 0 || q.Size > 0 || q.Rate > 0 || q.Requests > 0
+}
+
+// GetAllBucketQuotas - returns the quota of every bucket that has one,
+// keyed by bucket name. Buckets are fetched concurrently, on failure the
+// quotas fetched successfully are returned along with BucketQuotaErrors.
+func (adm *AdminClient) GetAllBucketQuotas(ctx context.Context) (map[string]BucketQuota, error) {
+	acct, err := adm.AccountInfo(ctx, AccountOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		sem    = make(chan struct{}, maxBucketQuotaConcurrency)
+		quotas = make(map[string]BucketQuota)
+		errs   = make(BucketQuotaErrors)
+	)
+	for _, b := range acct.Buckets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(bucket string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			q, err := adm.GetBucketQuota(ctx, bucket)
+			if err != nil && ToErrorResponse(err).Code == "XMinioAdminBucketQuotaConfigNotFound" {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				errs[bucket] = err
+			case q.isSet():
+				quotas[bucket] = q
+			}
+		}(b.Name)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return quotas, errs
+	}
+	return quotas, nil
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGetAllBucketQuotas(t *testing.T) {
+	quotas := map[string]BucketQuota{
+		"bucket1": {Size: 1 << 30, Type: HardQuota},
+		"bucket3": {Size: 1 << 40, Type: HardQuota},
+	}
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/accountinfo":
+			json.NewEncoder(w).Encode(AccountInfo{Buckets: []BucketAccessInfo{
+				{Name: "bucket1"}, {Name: "bucket2"}, {Name: "bucket3"},
+			}})
+		case libraryAdminURLPrefix + adminAPIPrefix + "/get-bucket-quota":
+			q, ok := quotas[r.URL.Query().Get("bucket")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioAdminBucketQuotaConfigNotFound"})
+				return
+			}
+			json.NewEncoder(w).Encode(q)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	got, err := adm.GetAllBucketQuotas(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 quotas, got %v", got)
+	}
+	for bucket, q := range quotas {
+		if got[bucket] != q {
+			t.Errorf("Expected %v for %s, got %v", q, bucket, got[bucket])
+		}
+	}
+}
+
+func TestBucketQuotaValidate(t *testing.T) {
+	testCases := []struct {
+		q       BucketQuota
+		valid   bool
+		isValid bool
+	}{
+		{BucketQuota{}, true, true},
+		{BucketQuota{Size: 1 << 30, Type: HardQuota}, true, true},
+		{BucketQuota{Quota: 1 << 30, Type: FIFOQuota}, true, true},
+		{BucketQuota{Type: HardQuota}, false, true},
+		{BucketQuota{Size: 1 << 30}, false, true},
+		{BucketQuota{Size: 1 << 30, Type: "soft"}, false, true},
+		{BucketQuota{Quota: 1 << 30, Type: "soft"}, false, false},
+	}
+	for i, tc := range testCases {
+		if err := tc.q.Validate(); (err == nil) != tc.valid {
+			t.Errorf("Test %d: expected valid=%v, got %v", i, tc.valid, err)
+		}
+		if tc.q.IsValid() != tc.isValid {
+			t.Errorf("Test %d: expected IsValid()=%v", i, tc.isValid)
+		}
+	}
+}
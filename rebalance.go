@@ -53,6 +53,48 @@ type RebalanceStatus struct {
 	Pools     []RebalancePoolStatus `json:"pools"` // contains all pools, including inactive
 }
 
+// RebalanceOverall contains the rebalance progress of the cluster as a whole
+type RebalanceOverall struct {
+	NumObjects  uint64 `json:"objects"`
+	NumVersions uint64 `json:"versions"`
+	Bytes       uint64 `json:"bytes"`
+	// TotalBytes is the estimated number of bytes to rebalance, extrapolated
+	// from the bytes rebalanced so far along with the elapsed time and ETA of
+	// every pool.
+	TotalBytes      uint64  `json:"totalBytes"`
+	PercentComplete float64 `json:"percentComplete"`
+	PoolsInProgress int     `json:"poolsInProgress"`
+}
+
+// inProgress returns true if the rebalance of the pool is still running
+func (p RebalancePoolStatus) inProgress() bool {
+	return p.Status == "Started" || p.Status == "Active"
+}
+
+// Overall returns the rebalance progress summed over all pools. The percent
+// complete is weighted by the estimated bytes to rebalance of every pool,
+// pools which haven't rebalanced anything yet don't count.
+func (r RebalanceStatus) Overall() RebalanceOverall {
+	var o RebalanceOverall
+	for _, p := range r.Pools {
+		o.NumObjects += p.Progress.NumObjects
+		o.NumVersions += p.Progress.NumVersions
+		o.Bytes += p.Progress.Bytes
+		total := p.Progress.Bytes
+		if p.inProgress() {
+			o.PoolsInProgress++
+			if p.Progress.Elapsed > 0 {
+				total = uint64(float64(p.Progress.Bytes) * float64(p.Progress.Elapsed+p.Progress.ETA) / float64(p.Progress.Elapsed))
+			}
+		}
+		o.TotalBytes += total
+	}
+	if o.TotalBytes > 0 {
+		o.PercentComplete = float64(o.Bytes) / float64(o.TotalBytes) * 100
+	}
+	return o
+}
+
 // RebalanceStart starts a rebalance operation if one isn't in progress already
 func (adm *AdminClient) RebalanceStart(ctx context.Context) (id string, err error) {
 	// Execute POST on /minio/admin/v3/rebalance/start to start a rebalance operation.
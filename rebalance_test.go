@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRebalanceStatusOverall(t *testing.T) {
+	status := RebalanceStatus{
+		Pools: []RebalancePoolStatus{
+			// Small pool, 90% done: 90 of 100 bytes.
+			{ID: 0, Status: "Started", Progress: RebalPoolProgress{NumObjects: 9, Bytes: 90, Elapsed: 9 * time.Minute, ETA: time.Minute}},
+			// Large pool, 10% done: 1000 of 10000 bytes.
+			{ID: 1, Status: "Started", Progress: RebalPoolProgress{NumObjects: 100, Bytes: 1000, Elapsed: time.Minute, ETA: 9 * time.Minute}},
+			// Nothing to rebalance.
+			{ID: 2, Status: "Completed"},
+		},
+	}
+	o := status.Overall()
+	if o.NumObjects != 109 || o.Bytes != 1090 || o.TotalBytes != 10100 || o.PoolsInProgress != 2 {
+		t.Errorf("Unexpected overall progress %+v", o)
+	}
+	// Byte weighted, not the 50% average of the two pools.
+	if pct := o.PercentComplete; pct < 10.79 || pct > 10.80 {
+		t.Errorf("Expected ~10.79%% complete, got %v", pct)
+	}
+
+	if o = (RebalanceStatus{}).Overall(); o.PercentComplete != 0 {
+		t.Errorf("Expected no progress without pools, got %+v", o)
+	}
+}
@@ -22,6 +22,7 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -84,6 +85,10 @@ func ParseARN(s string) (*ARN, error) {
 	}, nil
 }
 
+// ErrRemoteTargetNotFound is returned when no remote target of a bucket
+// matches the requested ARN.
+var ErrRemoteTargetNotFound = errors.New("remote target not found")
+
 // BucketTarget represents the target bucket and site association.
 type BucketTarget struct {
 	SourceBucket        string        `json:"sourcebucket"`
@@ -211,6 +216,49 @@ func (adm *AdminClient) ListRemoteTargets(ctx context.Context, bucket, arnType s
 	return targets, nil
 }
 
+// BucketTargetWithStatus - a remote target along with its reachability as
+// last observed by the server health checks.
+type BucketTargetWithStatus struct {
+	BucketTarget
+	Reachable bool   `json:"reachable"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ListRemoteTargetsWithStatus - gets all the targets of this bucket along with
+// their reachability. Unreachable targets are listed as well, with LastError
+// describing since when the target is offline.
+func (adm *AdminClient) ListRemoteTargetsWithStatus(ctx context.Context, bucket string) ([]BucketTargetWithStatus, error) {
+	targets, err := adm.ListRemoteTargets(ctx, bucket, "")
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]BucketTargetWithStatus, 0, len(targets))
+	for _, t := range targets {
+		statuses = append(statuses, BucketTargetWithStatus{
+			BucketTarget: t,
+			Reachable:    t.Online,
+			LastError:    remoteTargetError(t),
+		})
+	}
+	return statuses, nil
+}
+
+// remoteTargetError - describes why the target is unreachable, returns
+// an empty string for online targets.
+func remoteTargetError(t BucketTarget) string {
+	if t.Online {
+		return ""
+	}
+	msg := fmt.Sprintf("remote target %s is offline", t.URL())
+	if !t.LastOnline.IsZero() {
+		msg += fmt.Sprintf(", last online at %s", t.LastOnline.UTC().Format(time.RFC3339))
+	}
+	if t.TotalDowntime > 0 {
+		msg += fmt.Sprintf(", total downtime %s", t.TotalDowntime)
+	}
+	return msg
+}
+
 // SetRemoteTarget sets up a remote target for this bucket
 func (adm *AdminClient) SetRemoteTarget(ctx context.Context, bucket string, target *BucketTarget) (string, error) {
 	data, err := json.Marshal(target)
@@ -362,6 +410,35 @@ func (adm *AdminClient) UpdateRemoteTarget(ctx context.Context, target *BucketTa
 	return arn, nil
 }
 
+// UpdateRemoteTargetBandwidth updates only the bandwidth limit of the remote
+// target identified by arn on this bucket. The existing target is looked up
+// first and re-sent without its secret key, so no credentials are transmitted
+// to merely throttle replication. Returns ErrRemoteTargetNotFound if the
+// bucket has no target with this arn.
+func (adm *AdminClient) UpdateRemoteTargetBandwidth(ctx context.Context, bucket, arn string, limitBytesPerSec int64) error {
+	if limitBytesPerSec < 0 {
+		return ErrInvalidArgument("bandwidth limit cannot be negative")
+	}
+	targets, err := adm.ListRemoteTargets(ctx, bucket, "")
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if t.Arn != arn {
+			continue
+		}
+		if t.Credentials == nil {
+			t.Credentials = &Credentials{}
+		}
+		target := t.Clone()
+		target.SourceBucket = bucket
+		target.BandwidthLimit = limitBytesPerSec
+		_, err = adm.UpdateRemoteTarget(ctx, &target, BandwidthLimitUpdateType)
+		return err
+	}
+	return fmt.Errorf("%w: %s on bucket %s", ErrRemoteTargetNotFound, arn, bucket)
+}
+
 // RemoveRemoteTarget removes a remote target associated with particular ARN for this bucket
 func (adm *AdminClient) RemoveRemoteTarget(ctx context.Context, bucket, arn string) error {
 	queryValues := url.Values{}
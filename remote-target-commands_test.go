@@ -20,8 +20,14 @@
 package madmin
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
 	"net/url"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func isOpsEqual(op1 []TargetUpdateType, op2 []TargetUpdateType) bool {
@@ -100,3 +106,106 @@ func TestGetTargetUpdateOps(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateRemoteTargetBandwidth(t *testing.T) {
+	const arn = "arn:minio:replication::c5be6b16-769d-432a-9ef1-4567081f3566:target"
+	existing := BucketTarget{
+		SourceBucket:        "source",
+		Endpoint:            "replica:9000",
+		Credentials:         &Credentials{AccessKey: "replica-access"},
+		TargetBucket:        "target",
+		Secure:              true,
+		Path:                "auto",
+		API:                 "s3v4",
+		Arn:                 arn,
+		Type:                ReplicationService,
+		Region:              "us-east-1",
+		BandwidthLimit:      100 << 20,
+		ReplicationSync:     true,
+		HealthCheckDuration: 30 * time.Second,
+		DeploymentID:        "deployment",
+	}
+
+	var updated *BucketTarget
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/list-remote-targets":
+			data, err := json.Marshal([]BucketTarget{existing})
+			if err != nil {
+				t.Error(err)
+			}
+			w.Write(data)
+		case libraryAdminURLPrefix + adminAPIPrefix + "/set-remote-target":
+			q := r.URL.Query()
+			if q.Get("update") != "true" || q.Get("bandwidth") != "true" || q.Get("creds") != "" {
+				t.Errorf("unexpected update query %v", q)
+			}
+			data, err := DecryptData(testSecretKey, r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			updated = &BucketTarget{}
+			if err = json.Unmarshal(data, updated); err != nil {
+				t.Error(err)
+			}
+			w.Write([]byte(`"` + arn + `"`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ctx := context.Background()
+	if err := adm.UpdateRemoteTargetBandwidth(ctx, "source", arn, 10<<20); err != nil {
+		t.Fatal(err)
+	}
+	if updated == nil {
+		t.Fatal("remote target was not updated")
+	}
+	if updated.BandwidthLimit != 10<<20 {
+		t.Errorf("expected bandwidth limit %d, got %d", 10<<20, updated.BandwidthLimit)
+	}
+	expected := existing
+	expected.BandwidthLimit = updated.BandwidthLimit
+	if !reflect.DeepEqual(*updated, expected) {
+		t.Errorf("expected other fields untouched, got %+v", *updated)
+	}
+
+	err := adm.UpdateRemoteTargetBandwidth(ctx, "source", "arn:minio:replication::unknown:target", 1)
+	if !errors.Is(err, ErrRemoteTargetNotFound) {
+		t.Errorf("expected ErrRemoteTargetNotFound, got %v", err)
+	}
+}
+
+func TestListRemoteTargetsWithStatus(t *testing.T) {
+	lastOnline := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	targets := []BucketTarget{
+		{Endpoint: "online:9000", Arn: "arn1", Online: true},
+		{Endpoint: "offline:9000", Arn: "arn2", LastOnline: lastOnline, TotalDowntime: time.Minute},
+	}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/list-remote-targets" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, err := json.Marshal(targets)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(data)
+	})
+
+	statuses, err := adm.ListRemoteTargetsWithStatus(context.Background(), "source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(statuses))
+	}
+	if !statuses[0].Reachable || statuses[0].LastError != "" {
+		t.Errorf("expected reachable target, got %+v", statuses[0])
+	}
+	expected := "remote target http://offline:9000 is offline, last online at 2024-01-02T03:04:05Z, total downtime 1m0s"
+	if statuses[1].Reachable || statuses[1].LastError != expected {
+		t.Errorf("expected unreachable target with error %q, got %+v", expected, statuses[1])
+	}
+}
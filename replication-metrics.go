@@ -0,0 +1,168 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// ErrReplicationNotConfigured is returned for buckets without a
+// replication configuration.
+var ErrReplicationNotConfigured = errors.New("bucket replication is not configured")
+
+// ReplicationTargetMetrics - replication counters of a bucket towards one target
+type ReplicationTargetMetrics struct {
+	Arn             string `json:"arn"`
+	PendingCount    uint64 `json:"pendingCount"`
+	PendingSize     uint64 `json:"pendingSize"`
+	FailedCount     uint64 `json:"failedCount"`
+	FailedSize      uint64 `json:"failedSize"`
+	ReplicatedCount uint64 `json:"replicatedCount"`
+	ReplicatedSize  uint64 `json:"replicatedSize"`
+	// BandwidthLimit and CurrentBandwidth are in bytes/sec.
+	BandwidthLimit   int64         `json:"bandwidthLimit,omitempty"`
+	CurrentBandwidth float64       `json:"currentBandwidth,omitempty"`
+	Failed           TimedErrStats `json:"failed"`
+}
+
+// ReplicationMetrics - replication counters of a bucket across targets,
+// targets are sorted by ARN.
+type ReplicationMetrics struct {
+	Bucket          string                     `json:"bucket"`
+	PendingCount    uint64                     `json:"pendingCount"`
+	PendingSize     uint64                     `json:"pendingSize"`
+	FailedCount     uint64                     `json:"failedCount"`
+	FailedSize      uint64                     `json:"failedSize"`
+	ReplicatedCount uint64                     `json:"replicatedCount"`
+	ReplicatedSize  uint64                     `json:"replicatedSize"`
+	Targets         []ReplicationTargetMetrics `json:"targets,omitempty"`
+}
+
+// bucketReplicationMetricsV2 - replication metrics as sent by the server
+type bucketReplicationMetricsV2 struct {
+	CurrentStats struct {
+		Stats map[string]struct {
+			ReplicatedCount  uint64        `json:"replicationCount"`
+			ReplicatedSize   uint64        `json:"completedReplicationSize"`
+			BandwidthLimit   int64         `json:"limitInBits"`
+			CurrentBandwidth float64       `json:"currentBandwidth"`
+			Failed           TimedErrStats `json:"failed"`
+			PendingSize      uint64        `json:"pendingReplicationSize"`
+			FailedSize       uint64        `json:"failedReplicationSize"`
+			PendingCount     uint64        `json:"pendingReplicationCount"`
+			FailedCount      uint64        `json:"failedReplicationCount"`
+		} `json:"Stats"`
+		ReplicatedSize  uint64        `json:"completedReplicationSize"`
+		ReplicatedCount int64         `json:"replicationCount"`
+		Errors          TimedErrStats `json:"failed"`
+		QStats          struct {
+			Curr struct {
+				Count float64 `json:"count"`
+				Bytes float64 `json:"bytes"`
+			} `json:"curr"`
+		} `json:"queued"`
+		PendingSize  uint64 `json:"pendingReplicationSize"`
+		FailedSize   uint64 `json:"failedReplicationSize"`
+		PendingCount uint64 `json:"pendingReplicationCount"`
+		FailedCount  uint64 `json:"failedReplicationCount"`
+	} `json:"currStats"`
+}
+
+// BucketReplicationMetrics - returns the replication counters of bucket,
+// in total and per target. Returns ErrReplicationNotConfigured if the
+// bucket is not replicated.
+func (adm *AdminClient) BucketReplicationMetrics(ctx context.Context, bucket string) (ReplicationMetrics, error) {
+	resp, err := adm.executeMethod(ctx, http.MethodGet, requestData{
+		relPath:     "/" + bucket,
+		queryValues: url.Values{"replication-metrics": []string{"2"}},
+		isS3:        true,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return ReplicationMetrics{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err = httpRespToErrorResponse(resp)
+		if ToErrorResponse(err).Code == "ReplicationConfigurationNotFoundError" {
+			return ReplicationMetrics{}, fmt.Errorf("%w: %s", ErrReplicationNotConfigured, bucket)
+		}
+		return ReplicationMetrics{}, err
+	}
+
+	var m bucketReplicationMetricsV2
+	if err = json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return ReplicationMetrics{}, err
+	}
+	return m.metrics(bucket), nil
+}
+
+// metrics - converts the server metrics, the pending and failed counters
+// deprecated by newer servers are derived from the queue and error stats.
+func (m bucketReplicationMetricsV2) metrics(bucket string) ReplicationMetrics {
+	s := m.CurrentStats
+	res := ReplicationMetrics{
+		Bucket:          bucket,
+		PendingCount:    s.PendingCount,
+		PendingSize:     s.PendingSize,
+		FailedCount:     s.FailedCount,
+		FailedSize:      s.FailedSize,
+		ReplicatedCount: uint64(s.ReplicatedCount),
+		ReplicatedSize:  s.ReplicatedSize,
+	}
+	if res.PendingCount == 0 && res.PendingSize == 0 {
+		res.PendingCount = uint64(s.QStats.Curr.Count)
+		res.PendingSize = uint64(s.QStats.Curr.Bytes)
+	}
+	if res.FailedCount == 0 && res.FailedSize == 0 {
+		res.FailedCount = uint64(s.Errors.Totals.Count)
+		res.FailedSize = uint64(s.Errors.Totals.Bytes)
+	}
+
+	for arn, t := range s.Stats {
+		tm := ReplicationTargetMetrics{
+			Arn:              arn,
+			PendingCount:     t.PendingCount,
+			PendingSize:      t.PendingSize,
+			FailedCount:      t.FailedCount,
+			FailedSize:       t.FailedSize,
+			ReplicatedCount:  t.ReplicatedCount,
+			ReplicatedSize:   t.ReplicatedSize,
+			BandwidthLimit:   t.BandwidthLimit,
+			CurrentBandwidth: t.CurrentBandwidth,
+			Failed:           t.Failed,
+		}
+		if tm.FailedCount == 0 && tm.FailedSize == 0 {
+			tm.FailedCount = uint64(t.Failed.Totals.Count)
+			tm.FailedSize = uint64(t.Failed.Totals.Bytes)
+		}
+		res.Targets = append(res.Targets, tm)
+	}
+	sort.Slice(res.Targets, func(i, j int) bool {
+		return res.Targets[i].Arn < res.Targets[j].Arn
+	})
+	return res
+}
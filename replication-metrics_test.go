@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBucketReplicationMetrics(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["replication-metrics"]; !ok {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/photos":
+			w.Write([]byte(`{
+  "uptime": 3600,
+  "currStats": {
+    "Stats": {
+      "arn:minio:replication::site2:photos": {
+        "replicationCount": 90,
+        "completedReplicationSize": 9000,
+        "limitInBits": 1048576,
+        "currentBandwidth": 2048.5,
+        "failed": {"lastMinute": {"count": 1, "bytes": 100}, "lastHour": {"count": 2, "bytes": 200}, "totals": {"count": 3, "bytes": 300}}
+      },
+      "arn:minio:replication::site1:photos": {
+        "replicationCount": 100,
+        "completedReplicationSize": 10000,
+        "failed": {"totals": {"count": 0, "bytes": 0}}
+      }
+    },
+    "completedReplicationSize": 19000,
+    "replicationCount": 190,
+    "failed": {"totals": {"count": 3, "bytes": 300}},
+    "queued": {"curr": {"count": 7, "bytes": 700}, "avg": {"count": 5, "bytes": 500}, "peak": {"count": 9, "bytes": 900}}
+  },
+  "queueStats": {"nodes": []}
+}`))
+		case "/plain":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"Code":"ReplicationConfigurationNotFoundError","Message":"The replication configuration was not found"}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	m, err := adm.BucketReplicationMetrics(context.Background(), "photos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.PendingCount != 7 || m.PendingSize != 700 || m.FailedCount != 3 || m.FailedSize != 300 || m.ReplicatedCount != 190 || m.ReplicatedSize != 19000 {
+		t.Errorf("Unexpected totals %+v", m)
+	}
+	if len(m.Targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %+v", m.Targets)
+	}
+	if tm := m.Targets[0]; tm.Arn != "arn:minio:replication::site1:photos" || tm.ReplicatedCount != 100 || tm.FailedCount != 0 {
+		t.Errorf("Unexpected first target %+v", tm)
+	}
+	if tm := m.Targets[1]; tm.ReplicatedSize != 9000 || tm.FailedCount != 3 || tm.Failed.LastHour.Count != 2 || tm.BandwidthLimit != 1048576 || tm.CurrentBandwidth != 2048.5 {
+		t.Errorf("Unexpected second target %+v", tm)
+	}
+
+	if _, err = adm.BucketReplicationMetrics(context.Background(), "plain"); !errors.Is(err, ErrReplicationNotConfigured) {
+		t.Errorf("Expected ErrReplicationNotConfigured, got %v", err)
+	}
+}
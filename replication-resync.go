@@ -0,0 +1,200 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrReplicationResyncRunning is returned when a resync is started for a
+// replication target which already has one in progress.
+var ErrReplicationResyncRunning = errors.New("replication resync already running")
+
+// ResyncState - state of a bucket replication resync
+type ResyncState string
+
+const (
+	// ResyncPending - resync is queued
+	ResyncPending ResyncState = "Pending"
+	// ResyncStarted - resync is in progress
+	ResyncStarted ResyncState = "Started"
+	// ResyncCompleted - resync finished
+	ResyncCompleted ResyncState = "Completed"
+	// ResyncFailed - resync stopped on failure
+	ResyncFailed ResyncState = "Failed"
+	// ResyncCanceled - resync was canceled
+	ResyncCanceled ResyncState = "Canceled"
+)
+
+// ResyncStatus - progress of a bucket replication resync towards a target
+type ResyncStatus struct {
+	ResyncID        string      `json:"id"`
+	Bucket          string      `json:"bucket"`
+	Arn             string      `json:"arn"`
+	State           ResyncState `json:"state"`
+	StartTime       time.Time   `json:"startTime"`
+	LastUpdate      time.Time   `json:"lastUpdate"`
+	ReplicatedCount int64       `json:"replicatedCount"`
+	ReplicatedSize  int64       `json:"replicatedSize"`
+	FailedCount     int64       `json:"failedCount"`
+	FailedSize      int64       `json:"failedSize"`
+	Error           string      `json:"error,omitempty"`
+}
+
+// Running reports whether the resync is pending or in progress.
+func (s ResyncStatus) Running() bool {
+	return s.State == ResyncPending || s.State == ResyncStarted
+}
+
+// resyncTargetsInfo - response of the replication-reset and
+// replication-reset-status bucket APIs.
+type resyncTargetsInfo struct {
+	Targets []resyncTarget `json:"target,omitempty"`
+}
+
+type resyncTarget struct {
+	Arn             string    `json:"arn"`
+	ResetID         string    `json:"resetid"`
+	StartTime       time.Time `json:"startTime,omitempty"`
+	EndTime         time.Time `json:"endTime,omitempty"`
+	ResyncStatus    string    `json:"resyncStatus,omitempty"`
+	ReplicatedSize  int64     `json:"completedReplicationSize,omitempty"`
+	FailedSize      int64     `json:"failedReplicationSize,omitempty"`
+	ReplicatedCount int64     `json:"replicationCount,omitempty"`
+	FailedCount     int64     `json:"failedReplicationCount,omitempty"`
+	Bucket          string    `json:"bucket,omitempty"`
+}
+
+func (t resyncTarget) status(bucket string) ResyncStatus {
+	if t.Bucket != "" {
+		bucket = t.Bucket
+	}
+	return ResyncStatus{
+		ResyncID:        t.ResetID,
+		Bucket:          bucket,
+		Arn:             t.Arn,
+		State:           ResyncState(t.ResyncStatus),
+		StartTime:       t.StartTime,
+		LastUpdate:      t.EndTime,
+		ReplicatedCount: t.ReplicatedCount,
+		ReplicatedSize:  t.ReplicatedSize,
+		FailedCount:     t.FailedCount,
+		FailedSize:      t.FailedSize,
+	}
+}
+
+// StartReplicationResync starts a full resync of bucket to the replication
+// target arn and returns the id of the resync. Returns
+// ErrReplicationResyncRunning if the target already has a resync running.
+func (adm *AdminClient) StartReplicationResync(ctx context.Context, bucket, arn string) (resyncID string, err error) {
+	if bucket == "" || arn == "" {
+		return "", ErrInvalidArgument("bucket and arn are required")
+	}
+
+	targets, err := adm.replicationResyncStatus(ctx, bucket, arn)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range targets {
+		if status := t.status(bucket); t.Arn == arn && status.Running() {
+			return "", fmt.Errorf("%w: %s on bucket %s", ErrReplicationResyncRunning, status.ResyncID, bucket)
+		}
+	}
+
+	q := make(url.Values)
+	q.Set("replication-reset", "")
+	q.Set("arn", arn)
+	resp, err := adm.executeMethod(ctx, http.MethodPut, requestData{
+		relPath:     "/" + bucket,
+		queryValues: q,
+		isS3:        true,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return "", fmt.Errorf("%w: on bucket %s", ErrReplicationResyncRunning, bucket)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", httpRespToErrorResponse(resp)
+	}
+
+	var started resyncTargetsInfo
+	if err = json.NewDecoder(resp.Body).Decode(&started); err != nil {
+		return "", err
+	}
+	for _, t := range started.Targets {
+		if t.Arn == arn {
+			return t.ResetID, nil
+		}
+	}
+	return "", fmt.Errorf("no resync id returned for target %s on bucket %s", arn, bucket)
+}
+
+// ReplicationResyncStatus returns the progress of the resync resyncID of bucket.
+func (adm *AdminClient) ReplicationResyncStatus(ctx context.Context, bucket, resyncID string) (ResyncStatus, error) {
+	targets, err := adm.replicationResyncStatus(ctx, bucket, "")
+	if err != nil {
+		return ResyncStatus{}, err
+	}
+	for _, t := range targets {
+		if t.ResetID == resyncID {
+			return t.status(bucket), nil
+		}
+	}
+	return ResyncStatus{}, ErrInvalidArgument(fmt.Sprintf("no resync %s on bucket %s", resyncID, bucket))
+}
+
+// replicationResyncStatus returns the latest resync of every replication
+// target of bucket, or only of the target arn if it is set.
+func (adm *AdminClient) replicationResyncStatus(ctx context.Context, bucket, arn string) ([]resyncTarget, error) {
+	q := make(url.Values)
+	q.Set("replication-reset-status", "")
+	if arn != "" {
+		q.Set("arn", arn)
+	}
+	resp, err := adm.executeMethod(ctx, http.MethodGet, requestData{
+		relPath:     "/" + bucket,
+		queryValues: q,
+		isS3:        true,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	var info resyncTargetsInfo
+	if err = json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return info.Targets, nil
+}
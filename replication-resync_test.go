@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestStartReplicationResync(t *testing.T) {
+	const arn = "arn:minio:replication::id:target"
+	var state ResyncState
+	var started int
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/bucket" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			return
+		}
+		switch {
+		case q.Has("replication-reset-status"):
+			var info resyncTargetsInfo
+			if state != "" && (q.Get("arn") == "" || q.Get("arn") == arn) {
+				info.Targets = []resyncTarget{{Arn: arn, ResetID: "resync-1", ResyncStatus: string(state), ReplicatedCount: 10}}
+			}
+			json.NewEncoder(w).Encode(info)
+		case q.Has("replication-reset"):
+			if r.Method != http.MethodPut || q.Get("arn") != arn {
+				t.Errorf("Unexpected request %s %v", r.Method, q)
+			}
+			started++
+			state = ResyncPending
+			json.NewEncoder(w).Encode(resyncTargetsInfo{Targets: []resyncTarget{{Arn: arn, ResetID: "resync-1"}}})
+		default:
+			t.Errorf("Unexpected query %v", q)
+		}
+	})
+
+	id, err := adm.StartReplicationResync(context.Background(), "bucket", arn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "resync-1" || started != 1 {
+		t.Fatalf("Unexpected resync %q started %d times", id, started)
+	}
+
+	state = ResyncStarted
+	if _, err = adm.StartReplicationResync(context.Background(), "bucket", arn); !errors.Is(err, ErrReplicationResyncRunning) {
+		t.Fatalf("Expected ErrReplicationResyncRunning, got %v", err)
+	}
+	if started != 1 {
+		t.Errorf("Expected no resync to be started while one is running")
+	}
+
+	status, err := adm.ReplicationResyncStatus(context.Background(), "bucket", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Running() || status.ReplicatedCount != 10 || status.Bucket != "bucket" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if _, err = adm.ReplicationResyncStatus(context.Background(), "bucket", "resync-2"); err == nil {
+		t.Errorf("Expected an error for an unknown resync")
+	}
+
+	state = ResyncCompleted
+	if _, err = adm.StartReplicationResync(context.Background(), "bucket", arn); err != nil {
+		t.Errorf("Expected a new resync after completion, got %v", err)
+	}
+}
+
+func TestStartReplicationResyncStatusError(t *testing.T) {
+	var started bool
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("replication-reset") {
+			started = true
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{Code: "NoSuchBucket", Message: "The specified bucket does not exist"})
+	})
+
+	_, err := adm.StartReplicationResync(context.Background(), "bucket", "arn:minio:replication::id:target")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if started {
+		t.Errorf("Expected no resync to be started when its status cannot be read")
+	}
+}
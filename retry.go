@@ -21,8 +21,10 @@ package madmin
 
 import (
 	"context"
+	"math"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -66,33 +68,33 @@ func (r *lockedRandSource) Seed(seed int64) {
 	r.lk.Unlock()
 }
 
+// exponentialBackoffWait computes the exponential backoff duration according to
+// https://www.awsarchitectureblog.com/2015/03/backoff.html
+func exponentialBackoffWait(random *rand.Rand, attempt int, unit, cap time.Duration, jitter float64) time.Duration {
+	// normalize jitter to the range [0, 1.0]
+	if jitter < NoJitter {
+		jitter = NoJitter
+	}
+	if jitter > MaxJitter {
+		jitter = MaxJitter
+	}
+
+	// sleep = random_between(0, min(cap, base * 2 ** attempt))
+	sleep := unit * 1 << uint(attempt)
+	if sleep > cap || sleep <= 0 {
+		sleep = cap
+	}
+	if jitter > NoJitter {
+		sleep -= time.Duration(random.Float64() * float64(sleep) * jitter)
+	}
+	return sleep
+}
+
 // newRetryTimer creates a timer with exponentially increasing
 // delays until the maximum retry attempts are reached.
 func (adm AdminClient) newRetryTimer(ctx context.Context, maxRetry int, unit time.Duration, cap time.Duration, jitter float64) <-chan int {
 	attemptCh := make(chan int)
 
-	// computes the exponential backoff duration according to
-	// https://www.awsarchitectureblog.com/2015/03/backoff.html
-	exponentialBackoffWait := func(attempt int) time.Duration {
-		// normalize jitter to the range [0, 1.0]
-		if jitter < NoJitter {
-			jitter = NoJitter
-		}
-		if jitter > MaxJitter {
-			jitter = MaxJitter
-		}
-
-		// sleep = random_between(0, min(cap, base * 2 ** attempt))
-		sleep := unit * 1 << uint(attempt)
-		if sleep > cap {
-			sleep = cap
-		}
-		if jitter > NoJitter {
-			sleep -= time.Duration(adm.random.Float64() * float64(sleep) * jitter)
-		}
-		return sleep
-	}
-
 	go func() {
 		defer close(attemptCh)
 		for i := 0; i < maxRetry; i++ {
@@ -105,7 +107,7 @@ func (adm AdminClient) newRetryTimer(ctx context.Context, maxRetry int, unit tim
 			}
 
 			select {
-			case <-time.After(exponentialBackoffWait(i)):
+			case <-time.After(exponentialBackoffWait(adm.random, i, unit, cap, jitter)):
 			case <-ctx.Done():
 				// Stop the routine.
 				return
@@ -115,6 +117,106 @@ func (adm AdminClient) newRetryTimer(ctx context.Context, maxRetry int, unit tim
 	return attemptCh
 }
 
+// RetryPolicy - controls how failed requests are retried when set with
+// Options.RetryPolicy. Only GET, HEAD and OPTIONS requests, and requests
+// whose context was marked with WithRetry, are retried.
+type RetryPolicy struct {
+	// MaxRetry is the maximum number of attempts, defaults to MaxRetry.
+	MaxRetry int
+	// Unit is the base of the exponential backoff, defaults to DefaultRetryUnit.
+	Unit time.Duration
+	// Cap is the maximum wait between attempts, defaults to DefaultRetryCap.
+	// It also bounds waits requested with Retry-After.
+	Cap time.Duration
+	// Jitter randomizes the backoff, between NoJitter and MaxJitter.
+	Jitter float64
+	// Sleep waits d or until ctx is done, it can be replaced in tests.
+	Sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (p RetryPolicy) maxRetry() int {
+	if p.MaxRetry <= 0 {
+		return MaxRetry
+	}
+	return p.MaxRetry
+}
+
+func (p RetryPolicy) capWait(d time.Duration) time.Duration {
+	limit := p.Cap
+	if limit <= 0 {
+		limit = DefaultRetryCap
+	}
+	if d > limit {
+		return limit
+	}
+	return d
+}
+
+func (p RetryPolicy) backoff(random *rand.Rand, attempt int) time.Duration {
+	unit := p.Unit
+	if unit <= 0 {
+		unit = DefaultRetryUnit
+	}
+	return exponentialBackoffWait(random, attempt, unit, p.capWait(math.MaxInt64), p.Jitter)
+}
+
+func (p RetryPolicy) sleep(ctx context.Context, d time.Duration) error {
+	if p.Sleep != nil {
+		return p.Sleep(ctx, d)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type retryContextKey struct{}
+
+// WithRetry marks the requests made with ctx as safe to retry under a
+// RetryPolicy, even if they are not idempotent.
+func WithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryContextKey{}, true)
+}
+
+func retryRequested(ctx context.Context) bool {
+	ok, _ := ctx.Value(retryContextKey{}).(bool)
+	return ok
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header holding either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
 // List of admin error codes which are retryable.
 var retryableAdminErrCodes = map[string]struct{}{
 	"RequestError":         {},
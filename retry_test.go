@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var calls int
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls%3 != 0 {
+			if calls%3 == 1 {
+				w.Header().Set("Retry-After", "7")
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	var waits []time.Duration
+	adm.SetRetryPolicy(&RetryPolicy{
+		MaxRetry: 5,
+		Unit:     time.Second,
+		Cap:      10 * time.Second,
+		Sleep: func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	})
+
+	resp, err := adm.executeMethod(context.Background(), http.MethodGet, requestData{relPath: adminAPIPrefix + "/info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeResponse(resp)
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("Expected success after 3 attempts, got status %d after %d", resp.StatusCode, calls)
+	}
+	// The first failure asked for 7s, the second one backs off 2^1 units.
+	if len(waits) != 2 || waits[0] != 7*time.Second || waits[1] != 2*time.Second {
+		t.Errorf("Unexpected waits %v", waits)
+	}
+
+	// Mutations are attempted once unless marked safe to retry.
+	calls, waits = 0, nil
+	resp, err = adm.executeMethod(context.Background(), http.MethodPost, requestData{relPath: adminAPIPrefix + "/add-user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeResponse(resp)
+	if resp.StatusCode != http.StatusServiceUnavailable || calls != 1 {
+		t.Errorf("Expected a single failed attempt, got status %d after %d", resp.StatusCode, calls)
+	}
+
+	calls = 0
+	resp, err = adm.executeMethod(WithRetry(context.Background()), http.MethodPost, requestData{relPath: adminAPIPrefix + "/add-user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeResponse(resp)
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("Expected success after 3 attempts, got status %d after %d", resp.StatusCode, calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+	for i, tc := range testCases {
+		d, ok := parseRetryAfter(tc.value, now)
+		if d != tc.expected || ok != tc.ok {
+			t.Errorf("Test %d: expected %v %v, got %v %v", i+1, tc.expected, tc.ok, d, ok)
+		}
+	}
+}
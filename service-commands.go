@@ -25,7 +25,6 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -153,6 +152,10 @@ type ServiceTraceOpts struct {
 	ILM               bool
 	OnlyErrors        bool
 	Threshold         time.Duration
+
+	// Types enables the trace types set, in addition to
+	// the types enabled by the individual fields above.
+	Types TraceType
 }
 
 // TraceTypes returns the enabled traces as a bitfield value.
@@ -180,6 +183,7 @@ func (t ServiceTraceOpts) TraceTypes() TraceType {
 	tt.SetIf(t.Bootstrap, TraceBootstrap)
 	tt.SetIf(t.FTP, TraceFTP)
 	tt.SetIf(t.ILM, TraceILM)
+	tt.Merge(t.Types & TraceAll)
 
 	return tt
 }
@@ -189,26 +193,22 @@ func (t ServiceTraceOpts) AddParams(u url.Values) {
 	u.Set("err", strconv.FormatBool(t.OnlyErrors))
 	u.Set("threshold", t.Threshold.String())
 
-	u.Set("s3", strconv.FormatBool(t.S3))
-	u.Set("internal", strconv.FormatBool(t.Internal))
-	u.Set("storage", strconv.FormatBool(t.Storage))
-	u.Set("os", strconv.FormatBool(t.OS))
-	u.Set("scanner", strconv.FormatBool(t.Scanner))
-	u.Set("decommission", strconv.FormatBool(t.Decommission))
-	u.Set("healing", strconv.FormatBool(t.Healing))
-	u.Set("batch-replication", strconv.FormatBool(t.BatchReplication))
-	u.Set("batch-keyrotation", strconv.FormatBool(t.BatchKeyRotation))
-	u.Set("batch-expire", strconv.FormatBool(t.BatchExpire))
-	if t.BatchAll {
-		u.Set("batch-replication", "true")
-		u.Set("batch-keyrotation", "true")
-		u.Set("batch-expire", "true")
-	}
-	u.Set("rebalance", strconv.FormatBool(t.Rebalance))
-	u.Set("replication-resync", strconv.FormatBool(t.ReplicationResync))
-	u.Set("bootstrap", strconv.FormatBool(t.Bootstrap))
-	u.Set("ftp", strconv.FormatBool(t.FTP))
-	u.Set("ilm", strconv.FormatBool(t.ILM))
+	tt := t.TraceTypes()
+	u.Set("s3", strconv.FormatBool(tt.Contains(TraceS3)))
+	u.Set("internal", strconv.FormatBool(tt.Contains(TraceInternal)))
+	u.Set("storage", strconv.FormatBool(tt.Contains(TraceStorage)))
+	u.Set("os", strconv.FormatBool(tt.Contains(TraceOS)))
+	u.Set("scanner", strconv.FormatBool(tt.Contains(TraceScanner)))
+	u.Set("decommission", strconv.FormatBool(tt.Contains(TraceDecommission)))
+	u.Set("healing", strconv.FormatBool(tt.Contains(TraceHealing)))
+	u.Set("batch-replication", strconv.FormatBool(tt.Contains(TraceBatchReplication)))
+	u.Set("batch-keyrotation", strconv.FormatBool(tt.Contains(TraceBatchKeyRotation)))
+	u.Set("batch-expire", strconv.FormatBool(tt.Contains(TraceBatchExpire)))
+	u.Set("rebalance", strconv.FormatBool(tt.Contains(TraceRebalance)))
+	u.Set("replication-resync", strconv.FormatBool(tt.Contains(TraceReplicationResync)))
+	u.Set("bootstrap", strconv.FormatBool(tt.Contains(TraceBootstrap)))
+	u.Set("ftp", strconv.FormatBool(tt.Contains(TraceFTP)))
+	u.Set("ilm", strconv.FormatBool(tt.Contains(TraceILM)))
 }
 
 // ParseParams will parse parameters and set them to t.
@@ -275,39 +275,15 @@ func (adm AdminClient) ServiceTrace(ctx context.Context, opts ServiceTraceOpts)
 					traceInfoCh <- ServiceTraceInfo{Err: err}
 					break
 				}
-				// Convert if legacy...
-				if info.TraceType == TraceType(0) {
-					if strings.HasPrefix(info.FuncName, "s3.") {
-						info.TraceType = TraceS3
-					} else {
-						info.TraceType = TraceInternal
-					}
-					info.HTTP = &TraceHTTPStats{}
-					if info.ReqInfo != nil {
-						info.Path = info.ReqInfo.Path
-						info.HTTP.ReqInfo = *info.ReqInfo
-					}
-					if info.RespInfo != nil {
-						info.HTTP.RespInfo = *info.RespInfo
-					}
-					if info.CallStats != nil {
-						info.Duration = info.CallStats.Latency
-						info.HTTP.CallStats = *info.CallStats
-					}
-				}
-				if info.TraceType == TraceOS && info.OSStats != nil {
-					info.Path = info.OSStats.Path
-					info.Duration = info.OSStats.Duration
-				}
-				if info.TraceType == TraceStorage && info.StorageStats != nil {
-					info.Path = info.StorageStats.Path
-					info.Duration = info.StorageStats.Duration
-				}
+				// Traces of an unknown schema are passed through as decoded,
+				// so that a server upgrade does not stop the stream, callers
+				// can tell them apart with Trace.Version.
+				trace, _ := info.convert()
 				select {
 				case <-ctx.Done():
 					closeResponse(resp)
 					return
-				case traceInfoCh <- ServiceTraceInfo{Trace: info.TraceInfo}:
+				case traceInfoCh <- ServiceTraceInfo{Trace: trace}:
 				}
 			}
 		}
@@ -316,3 +292,38 @@ func (adm AdminClient) ServiceTrace(ctx context.Context, opts ServiceTraceOpts)
 	// Returns the trace info channel, for caller to start reading from.
 	return traceInfoCh
 }
+
+// ServiceTraceFiltered - listen on http trace notifications, only the trace
+// types enabled in opts are requested from the server. On top of that only
+// the traces for which predicate returns true are sent on the returned
+// channel, a nil predicate accepts all traces. Errors are always sent.
+func (adm AdminClient) ServiceTraceFiltered(ctx context.Context, opts ServiceTraceOpts, predicate func(TraceInfo) bool) <-chan ServiceTraceInfo {
+	traceInfoCh := make(chan ServiceTraceInfo)
+	types := opts.TraceTypes()
+	go func(traceInfoCh chan<- ServiceTraceInfo) {
+		defer close(traceInfoCh)
+		traceCh := adm.ServiceTrace(ctx, opts)
+		// Drain the remaining traces so the trace routine can exit.
+		defer func() {
+			for range traceCh {
+			}
+		}()
+		for info := range traceCh {
+			if info.Err == nil {
+				if !types.Overlaps(info.Trace.Category()) {
+					continue
+				}
+				if predicate != nil && !predicate(info.Trace) {
+					continue
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case traceInfoCh <- info:
+			}
+		}
+	}(traceInfoCh)
+
+	return traceInfoCh
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestServiceTraceOptsTypes(t *testing.T) {
+	opts := ServiceTraceOpts{S3: true, Types: TraceScanner | TraceILM}
+	if tt := opts.TraceTypes(); tt != TraceS3|TraceScanner|TraceILM {
+		t.Errorf("Unexpected trace types %v", tt)
+	}
+	u := url.Values{}
+	opts.AddParams(u)
+	for _, k := range []string{"s3", "scanner", "ilm"} {
+		if u.Get(k) != "true" {
+			t.Errorf("Expected %s to be enabled", k)
+		}
+	}
+	for _, k := range []string{"internal", "storage", "os", "healing"} {
+		if u.Get(k) != "false" {
+			t.Errorf("Expected %s to be disabled", k)
+		}
+	}
+}
+
+func TestTraceInfoCategory(t *testing.T) {
+	testCases := []struct {
+		info     TraceInfo
+		expected TraceType
+	}{
+		{TraceInfo{TraceType: TraceScanner, FuncName: "scanner.ScanObject"}, TraceScanner},
+		{TraceInfo{FuncName: "s3.GetObject"}, TraceS3},
+		{TraceInfo{FuncName: "storage.ReadAll"}, TraceInternal},
+	}
+	for i, tc := range testCases {
+		if c := tc.info.Category(); c != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, tc.expected, c)
+		}
+	}
+}
+
+func TestServiceTraceFiltered(t *testing.T) {
+	traces := []TraceInfo{
+		{TraceType: TraceS3, FuncName: "s3.GetObject", Path: "/bucket/a"},
+		{TraceType: TraceS3, FuncName: "s3.PutObject", Path: "/bucket/b"},
+		{TraceType: TraceInternal, FuncName: "peer.ServerInfo"},
+		{TraceType: TraceS3, FuncName: "s3.GetObject", Path: "/bucket/c"},
+	}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("s3") != "true" || r.URL.Query().Get("internal") != "false" {
+			t.Errorf("Unexpected trace query %v", r.URL.Query())
+		}
+		enc := json.NewEncoder(w)
+		for _, ti := range traces {
+			enc.Encode(ti)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := adm.ServiceTraceFiltered(ctx, ServiceTraceOpts{Types: TraceS3}, func(ti TraceInfo) bool {
+		return ti.FuncName == "s3.GetObject"
+	})
+	var paths []string
+	for info := range ch {
+		if info.Err != nil {
+			break
+		}
+		paths = append(paths, info.Trace.Path)
+	}
+	cancel()
+	for range ch {
+	}
+	if len(paths) != 2 || paths[0] != "/bucket/a" || paths[1] != "/bucket/c" {
+		t.Errorf("Unexpected filtered traces %v", paths)
+	}
+}
+
+func TestServiceTraceUnknownVersion(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		enc := json.NewEncoder(w)
+		enc.Encode(TraceInfo{Version: TraceInfoVersion, TraceType: TraceS3, FuncName: "s3.GetObject"})
+		enc.Encode(TraceInfo{Version: "99", TraceType: TraceS3, FuncName: "s3.PutObject"})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := adm.ServiceTrace(ctx, ServiceTraceOpts{S3: true})
+	var traces []TraceInfo
+	for info := range ch {
+		if info.Err != nil {
+			break
+		}
+		traces = append(traces, info.Trace)
+	}
+	cancel()
+	for range ch {
+	}
+	if len(traces) != 2 || traces[1].FuncName != "s3.PutObject" || traces[1].Version != "99" {
+		t.Errorf("Expected traces of an unknown version to be passed through, got %+v", traces)
+	}
+}
@@ -0,0 +1,172 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import "sort"
+
+// SRMismatch - an entity whose replication state differs across sites
+type SRMismatch struct {
+	Name string `json:"name"`
+	// Sites lists the names of all the sites reporting the entity.
+	Sites []string `json:"sites"`
+	// Behind lists the names of the sites missing the entity or missing
+	// some of its configuration present on the other sites.
+	Behind []string `json:"behind,omitempty"`
+	// Mismatches lists the entity properties that differ across sites.
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+// ReplicationMismatchReport - the entities out of sync across sites
+type ReplicationMismatchReport struct {
+	Buckets  []SRMismatch `json:"buckets"`
+	Policies []SRMismatch `json:"policies"`
+	Users    []SRMismatch `json:"users"`
+	Groups   []SRMismatch `json:"groups"`
+}
+
+// InSync returns true if no mismatch was found.
+func (r ReplicationMismatchReport) InSync() bool {
+	return len(r.Buckets) == 0 && len(r.Policies) == 0 && len(r.Users) == 0 && len(r.Groups) == 0
+}
+
+// srSiteState - replication state of an entity on a single site
+type srSiteState struct {
+	has        bool     // the site has the entity
+	mismatches []string // properties differing from the other sites
+	behind     bool     // the site lacks some of the entity properties
+}
+
+// srFlag records the property name if the mismatch flag is set, the site
+// is behind if it doesn't have the property set.
+func srFlag(mismatch, has bool, name string, st *srSiteState) {
+	if !mismatch {
+		return
+	}
+	st.mismatches = append(st.mismatches, name)
+	if !has {
+		st.behind = true
+	}
+}
+
+// Mismatches returns the buckets, policies, users and groups whose state
+// differs across sites. Only entities reported by the server are considered,
+// which requires the matching SRStatusOptions to be set. Mismatches are
+// sorted by name, the report slices are empty when everything is in sync.
+func (s SRStatusInfo) Mismatches() ReplicationMismatchReport {
+	report := ReplicationMismatchReport{
+		Buckets:  []SRMismatch{},
+		Policies: []SRMismatch{},
+		Users:    []SRMismatch{},
+		Groups:   []SRMismatch{},
+	}
+	for name, stats := range s.BucketStats {
+		states := make(map[string]srSiteState, len(stats))
+		for id, st := range stats {
+			ss := srSiteState{has: st.HasBucket && !st.BucketMarkedDeleted}
+			srFlag(st.TagMismatch, st.HasTagsSet, "tags", &ss)
+			srFlag(st.VersioningConfigMismatch, true, "versioning", &ss)
+			srFlag(st.OLockConfigMismatch, st.HasOLockConfigSet, "object-lock", &ss)
+			srFlag(st.PolicyMismatch, st.HasPolicySet, "policy", &ss)
+			srFlag(st.SSEConfigMismatch, st.HasSSECfgSet, "sse", &ss)
+			srFlag(st.ReplicationCfgMismatch, st.HasReplicationCfg, "replication", &ss)
+			srFlag(st.QuotaCfgMismatch, st.HasQuotaCfgSet, "quota", &ss)
+			states[id] = ss
+		}
+		report.Buckets = s.appendMismatch(report.Buckets, name, states)
+	}
+	for name, stats := range s.PolicyStats {
+		states := make(map[string]srSiteState, len(stats))
+		for id, st := range stats {
+			ss := srSiteState{has: st.HasPolicy}
+			srFlag(st.PolicyMismatch, true, "policy", &ss)
+			states[id] = ss
+		}
+		report.Policies = s.appendMismatch(report.Policies, name, states)
+	}
+	for name, stats := range s.UserStats {
+		states := make(map[string]srSiteState, len(stats))
+		for id, st := range stats {
+			ss := srSiteState{has: st.HasUser}
+			srFlag(st.PolicyMismatch, st.HasPolicyMapping, "policy-mapping", &ss)
+			srFlag(st.UserInfoMismatch, true, "user-info", &ss)
+			states[id] = ss
+		}
+		report.Users = s.appendMismatch(report.Users, name, states)
+	}
+	for name, stats := range s.GroupStats {
+		states := make(map[string]srSiteState, len(stats))
+		for id, st := range stats {
+			ss := srSiteState{has: st.HasGroup}
+			srFlag(st.PolicyMismatch, st.HasPolicyMapping, "policy-mapping", &ss)
+			srFlag(st.GroupDescMismatch, true, "group-info", &ss)
+			states[id] = ss
+		}
+		report.Groups = s.appendMismatch(report.Groups, name, states)
+	}
+	for _, l := range [][]SRMismatch{report.Buckets, report.Policies, report.Users, report.Groups} {
+		sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	}
+	return report
+}
+
+// appendMismatch appends the mismatch of the entity to l if its state
+// differs across the sites in states, keyed by deployment ID.
+func (s SRStatusInfo) appendMismatch(l []SRMismatch, name string, states map[string]srSiteState) []SRMismatch {
+	m := SRMismatch{Name: name}
+	var haveIt, missIt bool
+	mismatches := make(map[string]bool)
+	for id, st := range states {
+		site := s.siteName(id)
+		m.Sites = append(m.Sites, site)
+		if st.has {
+			haveIt = true
+		} else {
+			missIt = true
+		}
+		if !st.has || st.behind {
+			m.Behind = append(m.Behind, site)
+		}
+		for _, mm := range st.mismatches {
+			mismatches[mm] = true
+		}
+	}
+	if !(haveIt && missIt) && len(mismatches) == 0 {
+		return l
+	}
+	if !haveIt {
+		// No site has the entity, no site is behind another.
+		m.Behind = nil
+	}
+	for mm := range mismatches {
+		m.Mismatches = append(m.Mismatches, mm)
+	}
+	sort.Strings(m.Sites)
+	sort.Strings(m.Behind)
+	sort.Strings(m.Mismatches)
+	return append(l, m)
+}
+
+// siteName returns the name of the site of the deployment ID if known.
+func (s SRStatusInfo) siteName(deploymentID string) string {
+	if p, ok := s.Sites[deploymentID]; ok && p.Name != "" {
+		return p.Name
+	}
+	return deploymentID
+}
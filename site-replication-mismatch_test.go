@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSRStatusInfoMismatches(t *testing.T) {
+	status := SRStatusInfo{
+		Enabled: true,
+		Sites: map[string]PeerInfo{
+			"dep-1": {Name: "site-a", DeploymentID: "dep-1"},
+			"dep-2": {Name: "site-b", DeploymentID: "dep-2"},
+		},
+		BucketStats: map[string]map[string]SRBucketStatsSummary{
+			"in-sync": {
+				"dep-1": {DeploymentID: "dep-1", HasBucket: true},
+				"dep-2": {DeploymentID: "dep-2", HasBucket: true},
+			},
+			"missing": {
+				"dep-1": {DeploymentID: "dep-1", HasBucket: true},
+				"dep-2": {DeploymentID: "dep-2"},
+			},
+			"tags": {
+				"dep-1": {DeploymentID: "dep-1", HasBucket: true, HasTagsSet: true, TagMismatch: true},
+				"dep-2": {DeploymentID: "dep-2", HasBucket: true, TagMismatch: true},
+			},
+		},
+		UserStats: map[string]map[string]SRUserStatsSummary{
+			"alice": {
+				"dep-1": {DeploymentID: "dep-1", HasUser: true},
+				"dep-2": {DeploymentID: "dep-2", HasUser: true},
+			},
+		},
+	}
+
+	report := status.Mismatches()
+	expected := []SRMismatch{
+		{Name: "missing", Sites: []string{"site-a", "site-b"}, Behind: []string{"site-b"}},
+		{Name: "tags", Sites: []string{"site-a", "site-b"}, Behind: []string{"site-b"}, Mismatches: []string{"tags"}},
+	}
+	if !reflect.DeepEqual(report.Buckets, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, report.Buckets)
+	}
+	if report.Users == nil || len(report.Users) != 0 || report.InSync() {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	delete(status.BucketStats, "missing")
+	delete(status.BucketStats, "tags")
+	report = status.Mismatches()
+	if !report.InSync() || report.Buckets == nil || report.Policies == nil || report.Groups == nil {
+		t.Errorf("Expected an empty non-nil report, got %+v", report)
+	}
+}
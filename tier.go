@@ -22,6 +22,8 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -33,11 +35,94 @@ import (
 // tierAPI is API path prefix for tier related admin APIs
 const tierAPI = "tier"
 
+var (
+	// ErrTierAuthFailed "remote tier authentication failed"
+	ErrTierAuthFailed = errors.New("remote tier authentication failed")
+	// ErrTierUnreachable "remote tier unreachable"
+	ErrTierUnreachable = errors.New("remote tier unreachable")
+	// ErrTierTypeChange "remote tier type cannot be changed"
+	ErrTierTypeChange = errors.New("remote tier type cannot be changed")
+)
+
 // AddTierIgnoreInUse adds a new remote tier, ignoring if it's being used by another MinIO deployment.
 func (adm *AdminClient) AddTierIgnoreInUse(ctx context.Context, cfg *TierConfig) error {
 	return adm.addTier(ctx, cfg, true)
 }
 
+// AddTierDryRun asks the server to validate the remote tier config, i.e the
+// bucket exists and the credentials are allowed to write and delete objects,
+// without saving it. The config is sent to a dedicated validation API, so a
+// server without it answers with ErrNotSupported and never adds the tier.
+// Authentication failures wrap ErrTierAuthFailed and connectivity failures
+// wrap ErrTierUnreachable, other failures are returned as reported by the
+// server.
+func (adm *AdminClient) AddTierDryRun(ctx context.Context, cfg *TierConfig) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	encData, err := EncryptData(adm.getSecretKey(), data)
+	if err != nil {
+		return err
+	}
+
+	reqData := requestData{
+		relPath: path.Join(adminAPIPrefix, tierAPI+"-validate"),
+		content: encData,
+	}
+
+	// Execute PUT on /minio/admin/v3/tier-validate to validate a remote tier
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	err = httpRespToErrorResponse(resp)
+	errResp := ToErrorResponse(err)
+	switch {
+	case isTierAuthError(errResp):
+		return fmt.Errorf("%w: %s", ErrTierAuthFailed, errResp.Message)
+	case isTierConnectError(errResp):
+		return fmt.Errorf("%w: %s", ErrTierUnreachable, errResp.Message)
+	case isUnknownAPIError(errResp):
+		return fmt.Errorf("%w: tier validation: %s", ErrNotSupported, errResp.Message)
+	}
+	return err
+}
+
+// isTierAuthError returns true if the server rejected the tier credentials.
+func isTierAuthError(errResp ErrorResponse) bool {
+	switch errResp.Code {
+	case "XMinioAdminTierMissingCredentials", "XMinioAdminTierInsufficientCreds",
+		"InvalidAccessKeyId", "SignatureDoesNotMatch", "AuthenticationFailed":
+		return true
+	}
+	return false
+}
+
+// isTierConnectError returns true if the server could not reach the remote
+// tier.
+func isTierConnectError(errResp ErrorResponse) bool {
+	if errResp.Code == "XMinioAdminTierUnreachable" {
+		return true
+	}
+	return errResp.StatusCode == http.StatusBadGateway || errResp.StatusCode == http.StatusGatewayTimeout
+}
+
+// isUnknownAPIError returns true if the server does not know the API, it
+// rejects unknown admin APIs with a BadRequest code.
+func isUnknownAPIError(errResp ErrorResponse) bool {
+	if errResp.Code == "BadRequest" || errors.Is(errResp, ErrNotSupported) {
+		return true
+	}
+	return errResp.StatusCode == http.StatusNotFound || errResp.StatusCode == http.StatusMethodNotAllowed
+}
+
 // AddTier adds a new remote tier.
 func (adm *AdminClient) addTier(ctx context.Context, cfg *TierConfig, ignoreInUse bool) error {
 	data, err := json.Marshal(cfg)
@@ -121,15 +206,59 @@ type TierCreds struct {
 	CredsJSON []byte `json:"creds,omitempty"`
 }
 
+// TierEndpoint is used to pass the remote tier endpoint and region in a
+// tier-edit operation. Empty fields are left unchanged on the server.
+type TierEndpoint struct {
+	Endpoint string `json:"endpoint,omitempty"`
+	Region   string `json:"region,omitempty"`
+}
+
+// TierEdit is used to pass the remote tier credentials along with its
+// endpoint and region in a tier-edit operation. Type is only used to check
+// that the edit doesn't change the type of the tier, it is never sent.
+type TierEdit struct {
+	TierCreds
+	TierEndpoint
+	Type TierType `json:"-"`
+}
+
 // EditTier supports updating credentials for the remote tier identified by tierName.
 func (adm *AdminClient) EditTier(ctx context.Context, tierName string, creds TierCreds) error {
 	data, err := json.Marshal(creds)
 	if err != nil {
 		return err
 	}
+	return adm.editTier(ctx, tierName, data)
+}
+
+// EditTierConfig supports updating credentials, endpoint and region for the
+// remote tier identified by tierName. Fields omitted in edit are left
+// unchanged. If edit.Type is set, it must match the type of the existing tier,
+// otherwise ErrTierTypeChange is returned.
+func (adm *AdminClient) EditTierConfig(ctx context.Context, tierName string, edit TierEdit) error {
+	if tierName == "" {
+		return ErrTierNameEmpty
+	}
+	if edit.Type != Unsupported {
+		tiers, err := adm.ListTiers(ctx)
+		if err != nil {
+			return err
+		}
+		for _, tier := range tiers {
+			if tier.Name == tierName && tier.Type != edit.Type {
+				return fmt.Errorf("%w: tier %s is of type %s", ErrTierTypeChange, tierName, tier.Type)
+			}
+		}
+	}
+	data, err := json.Marshal(edit)
+	if err != nil {
+		return err
+	}
+	return adm.editTier(ctx, tierName, data)
+}
 
-	var encData []byte
-	encData, err = EncryptData(adm.getSecretKey(), data)
+func (adm *AdminClient) editTier(ctx context.Context, tierName string, data []byte) error {
+	encData, err := EncryptData(adm.getSecretKey(), data)
 	if err != nil {
 		return err
 	}
@@ -244,3 +373,59 @@ func (adm *AdminClient) TierStats(ctx context.Context) ([]TierInfo, error) {
 
 	return tierInfos, nil
 }
+
+// TierUsageInfo contains the usage of a tier
+type TierUsageInfo struct {
+	Type        string `json:"type"`
+	TotalSize   uint64 `json:"totalSize"`
+	NumVersions int    `json:"numVersions"`
+	NumObjects  int    `json:"numObjects"`
+}
+
+// TierUsage returns the usage of every configured tier keyed by tier name,
+// tiers without any transitioned data are included with zero usage. Returns
+// ErrNotSupported if the server doesn't report tier statistics.
+func (adm *AdminClient) TierUsage(ctx context.Context) (map[string]TierUsageInfo, error) {
+	tiers, err := adm.ListTiers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqData := requestData{
+		relPath: path.Join(adminAPIPrefix, "tier-stats"),
+	}
+
+	// Execute GET on /minio/admin/v3/tier-stats to list tier-stats.
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := ToErrorResponse(httpRespToErrorResponse(resp))
+		if isNotSupportedResponse(resp, errResp) {
+			return nil, ErrNotSupported
+		}
+		return nil, errResp
+	}
+
+	var tierInfos []TierInfo
+	if err = json.NewDecoder(resp.Body).Decode(&tierInfos); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]TierUsageInfo, len(tiers)+len(tierInfos))
+	for _, tier := range tiers {
+		usage[tier.Name] = TierUsageInfo{Type: tier.Type.String()}
+	}
+	for _, ti := range tierInfos {
+		usage[ti.Name] = TierUsageInfo{
+			Type:        ti.Type,
+			TotalSize:   ti.Stats.TotalSize,
+			NumVersions: ti.Stats.NumVersions,
+			NumObjects:  ti.Stats.NumObjects,
+		}
+	}
+	return usage, nil
+}
@@ -20,9 +20,13 @@
 package madmin
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"reflect"
 	"testing"
 )
@@ -187,3 +191,142 @@ func TestGCSStorageClass(t *testing.T) {
 		t.Fatalf("got != want, got = %v want = %v", *got, *want)
 	}
 }
+
+// TestAddTierDryRun tests classification of AddTierDryRun errors
+func TestAddTierDryRun(t *testing.T) {
+	testCases := []struct {
+		status   int
+		resp     ErrorResponse
+		expected error
+	}{
+		{status: http.StatusNoContent},
+		{status: http.StatusBadRequest, resp: ErrorResponse{Code: "XMinioAdminTierInsufficientCreds", Message: "insufficient permissions"}, expected: ErrTierAuthFailed},
+		{status: http.StatusBadRequest, resp: ErrorResponse{Code: "XMinioAdminTierUnreachable", Message: "connection refused"}, expected: ErrTierUnreachable},
+		{status: http.StatusGatewayTimeout, resp: ErrorResponse{Code: "XMinioAdminTierInvalidConfig", Message: "remote timed out"}, expected: ErrTierUnreachable},
+		{status: http.StatusBadRequest, resp: ErrorResponse{Code: "BadRequest", Message: "An unsupported API call"}, expected: ErrNotSupported},
+		{status: http.StatusNotFound, expected: ErrNotSupported},
+		{status: http.StatusBadRequest, resp: ErrorResponse{Code: "XMinioAdminTierBackendNotEmpty", Message: "remote bucket not empty"}},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+			// The tier must never be added by the dry run.
+			if r.Method != http.MethodPut || r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/tier-validate" {
+				t.Errorf("Test %d: unexpected request %s %s", i+1, r.Method, r.URL.Path)
+			}
+			w.WriteHeader(tc.status)
+			if tc.resp.Code != "" {
+				json.NewEncoder(w).Encode(tc.resp)
+			}
+		})
+		cfg, err := NewTierS3("dry-s3", "accessKey", "secretKey", "testbucket")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = adm.AddTierDryRun(context.Background(), cfg)
+		switch {
+		case tc.status == http.StatusNoContent:
+			if err != nil {
+				t.Errorf("Test %d: expected no error, got %v", i+1, err)
+			}
+		case tc.expected != nil:
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Test %d: expected %v, got %v", i+1, tc.expected, err)
+			}
+		default:
+			if ToErrorResponse(err).Code != tc.resp.Code {
+				t.Errorf("Test %d: expected server error %s, got %v", i+1, tc.resp.Code, err)
+			}
+		}
+	}
+}
+
+// TestEditTierConfig tests tier-edit payloads of EditTierConfig
+func TestEditTierConfig(t *testing.T) {
+	tier, err := NewTierS3("edit-s3", "accessKey", "secretKey", "testbucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload map[string]interface{}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode([]*TierConfig{tier})
+		case r.Method == http.MethodPost:
+			data, err := DecryptData(testSecretKey, r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			payload = nil
+			if err = json.Unmarshal(data, &payload); err != nil {
+				t.Error(err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	ctx := context.Background()
+	edit := TierEdit{TierEndpoint: TierEndpoint{Endpoint: "https://s3.us-west-2.amazonaws.com"}, Type: S3}
+	if err = adm.EditTierConfig(ctx, "edit-s3", edit); err != nil {
+		t.Fatal(err)
+	}
+	if payload["endpoint"] != edit.Endpoint {
+		t.Errorf("Expected endpoint %s, got %v", edit.Endpoint, payload["endpoint"])
+	}
+	for _, k := range []string{"region", "access", "secret", "type"} {
+		if _, ok := payload[k]; ok {
+			t.Errorf("Expected %s to be omitted, got %v", k, payload[k])
+		}
+	}
+
+	edit.Type = Azure
+	if err = adm.EditTierConfig(ctx, "edit-s3", edit); !errors.Is(err, ErrTierTypeChange) {
+		t.Errorf("Expected ErrTierTypeChange, got %v", err)
+	}
+}
+
+// TestTierUsage tests TierUsage includes tiers without usage
+func TestTierUsage(t *testing.T) {
+	s3Tier, err := NewTierS3("used-s3", "accessKey", "secretKey", "testbucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	azTier, err := NewTierAzure("unused-az", "accessKey", "secretKey", "testbucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notSupported := false
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/tier":
+			json.NewEncoder(w).Encode([]*TierConfig{s3Tier, azTier})
+		case libraryAdminURLPrefix + adminAPIPrefix + "/tier-stats":
+			if notSupported {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode([]TierInfo{
+				{Name: "STANDARD", Type: "internal", Stats: TierStats{TotalSize: 10, NumObjects: 1, NumVersions: 1}},
+				{Name: "used-s3", Type: "s3", Stats: TierStats{TotalSize: 1 << 20, NumObjects: 2, NumVersions: 3}},
+			})
+		}
+	})
+
+	usage, err := adm.TierUsage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]TierUsageInfo{
+		"STANDARD":  {Type: "internal", TotalSize: 10, NumObjects: 1, NumVersions: 1},
+		"used-s3":   {Type: "s3", TotalSize: 1 << 20, NumObjects: 2, NumVersions: 3},
+		"unused-az": {Type: "azure"},
+	}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("Expected %v, got %v", expected, usage)
+	}
+
+	notSupported = true
+	if _, err = adm.TierUsage(context.Background()); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+}
@@ -23,8 +23,10 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -46,6 +48,14 @@ type LockEntry struct {
 	Quorum int `json:"quorum"`
 }
 
+// Age returns for how long the lock has been held, computed from its timestamp.
+func (l LockEntry) Age() time.Duration {
+	if l.Timestamp.IsZero() {
+		return 0
+	}
+	return nowFunc().Sub(l.Timestamp)
+}
+
 // LockEntries - To sort the locks
 type LockEntries []LockEntry
 
@@ -65,10 +75,82 @@ func (l LockEntries) Swap(i, j int) {
 type TopLockOpts struct {
 	Count int
 	Stale bool
+
+	// MinDuration only returns locks held for at least this long.
+	MinDuration time.Duration
+	// Bucket and Prefix only return locks on objects of the bucket,
+	// whose name starts with Prefix.
+	Bucket string
+	Prefix string
+}
+
+// matches returns true if the lock entry passes the filters of opts.
+func (opts TopLockOpts) matches(l LockEntry) bool {
+	if opts.MinDuration > 0 && l.Age() < opts.MinDuration {
+		return false
+	}
+	if opts.Bucket == "" {
+		return true
+	}
+	bucket, object := l.Resource, ""
+	if i := strings.Index(l.Resource, "/"); i >= 0 {
+		bucket, object = l.Resource[:i], l.Resource[i+1:]
+	}
+	return bucket == opts.Bucket && strings.HasPrefix(object, opts.Prefix)
+}
+
+// ForceUnlockErrors is returned by ForceUnlockResources with
+// the error of every resource that could not be unlocked.
+type ForceUnlockErrors map[string]error
+
+func (e ForceUnlockErrors) Error() string {
+	resources := make([]string, 0, len(e))
+	for resource := range e {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	msgs := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		msgs = append(msgs, resource+": "+e[resource].Error())
+	}
+	return "unable to force unlock: " + strings.Join(msgs, "; ")
+}
+
+// ForceUnlockResources force unlocks every resource, e.g. as found in
+// LockEntry.Resource, one at a time. The resources that could not be
+// unlocked are returned as ForceUnlockErrors.
+func (adm *AdminClient) ForceUnlockResources(ctx context.Context, resources []string) error {
+	if len(resources) == 0 {
+		return ErrInvalidArgument("no resources to unlock")
+	}
+	errs := make(ForceUnlockErrors)
+	for _, resource := range resources {
+		if err := adm.ForceUnlock(ctx, resource); err != nil {
+			errs[resource] = err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
-// ForceUnlock force unlocks input paths...
+// ForceUnlockEntry force unlocks the resource of the lock entry.
+func (adm *AdminClient) ForceUnlockEntry(ctx context.Context, e LockEntry) error {
+	return adm.ForceUnlock(ctx, e.Resource)
+}
+
+// ForceUnlock force unlocks input paths, at least one non-empty path is
+// required.
 func (adm *AdminClient) ForceUnlock(ctx context.Context, paths ...string) error {
+	if len(paths) == 0 {
+		return ErrInvalidArgument("no paths to unlock")
+	}
+	for _, p := range paths {
+		if p == "" {
+			return ErrInvalidArgument("empty path to unlock")
+		}
+	}
 	// Execute POST on /minio/admin/v3/force-unlock
 	queryVals := make(url.Values)
 	queryVals.Set("paths", strings.Join(paths, ","))
@@ -93,13 +175,29 @@ func (adm *AdminClient) ForceUnlock(ctx context.Context, paths ...string) error
 
 // TopLocksWithOpts - returns the count number of oldest locks currently active on the server.
 // additionally we can also enable `stale` to get stale locks currently present on server.
+// Locks can be filtered by age and bucket, an empty non-nil slice is returned
+// when no locks match.
 func (adm *AdminClient) TopLocksWithOpts(ctx context.Context, opts TopLockOpts) (LockEntries, error) {
 	// Execute GET on /minio/admin/v3/top/locks?count=10
 	// to get the 'count' number of oldest locks currently
 	// active on the server.
+	// Servers without filters would return the count oldest locks of all
+	// resources, ask for all the locks and trim once filtered instead.
+	filtering := opts.MinDuration > 0 || opts.Bucket != ""
+	count := opts.Count
+	if filtering {
+		count = math.MaxInt32
+	}
 	queryVals := make(url.Values)
-	queryVals.Set("count", strconv.Itoa(opts.Count))
+	queryVals.Set("count", strconv.Itoa(count))
 	queryVals.Set("stale", strconv.FormatBool(opts.Stale))
+	if opts.MinDuration > 0 {
+		queryVals.Set("min-duration", opts.MinDuration.String())
+	}
+	if opts.Bucket != "" {
+		queryVals.Set("bucket", opts.Bucket)
+		queryVals.Set("prefix", opts.Prefix)
+	}
 	resp, err := adm.executeMethod(ctx,
 		http.MethodGet,
 		requestData{
@@ -122,8 +220,21 @@ func (adm *AdminClient) TopLocksWithOpts(ctx context.Context, opts TopLockOpts)
 	}
 
 	var lockEntries LockEntries
-	err = json.Unmarshal(response, &lockEntries)
-	return lockEntries, err
+	if err = json.Unmarshal(response, &lockEntries); err != nil {
+		return nil, err
+	}
+
+	// Older servers don't filter, filter again on the client side.
+	filtered := LockEntries{}
+	for _, l := range lockEntries {
+		if opts.matches(l) {
+			filtered = append(filtered, l)
+		}
+	}
+	if filtering && opts.Count > 0 && len(filtered) > opts.Count {
+		filtered = filtered[:opts.Count]
+	}
+	return filtered, nil
 }
 
 // TopLocks - returns top '10' oldest locks currently active on the server.
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLockEntryAge(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+	nowFunc = func() time.Time { return now }
+
+	testCases := []struct {
+		ts       time.Time
+		expected time.Duration
+	}{
+		{ts: now.Add(-5 * time.Minute), expected: 5 * time.Minute},
+		{ts: now, expected: 0},
+		{ts: time.Time{}, expected: 0},
+	}
+	for i, tc := range testCases {
+		if age := (LockEntry{Timestamp: tc.ts}).Age(); age != tc.expected {
+			t.Errorf("Test %d: expected age %v, got %v", i+1, tc.expected, age)
+		}
+	}
+}
+
+func TestTopLocksWithOptsFilter(t *testing.T) {
+	now := time.Now()
+	locks := LockEntries{
+		{Resource: "bucket/stuck/object", Timestamp: now.Add(-time.Hour)},
+		{Resource: "bucket/recent/object", Timestamp: now.Add(-time.Second)},
+		{Resource: "other/stuck/object", Timestamp: now.Add(-time.Hour)},
+	}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Like a server without filters, only return the count oldest locks.
+		count, err := strconv.Atoi(r.URL.Query().Get("count"))
+		if err != nil {
+			t.Error(err)
+		}
+		if count > len(locks) {
+			count = len(locks)
+		}
+		json.NewEncoder(w).Encode(locks[:count])
+	})
+
+	ctx := context.Background()
+	res, err := adm.TopLocksWithOpts(ctx, TopLockOpts{Count: 1, MinDuration: time.Minute, Bucket: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Resource != "other/stuck/object" {
+		t.Errorf("Expected the lock past the count to be found, got %v", res)
+	}
+
+	res, err = adm.TopLocksWithOpts(ctx, TopLockOpts{Count: 1, MinDuration: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Resource != "bucket/stuck/object" {
+		t.Errorf("Expected the filtered locks to be trimmed to count, got %v", res)
+	}
+
+	res, err = adm.TopLocksWithOpts(ctx, TopLockOpts{Count: 10, MinDuration: time.Minute, Bucket: "bucket", Prefix: "stuck/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Resource != "bucket/stuck/object" {
+		t.Errorf("Unexpected locks %v", res)
+	}
+
+	res, err = adm.TopLocksWithOpts(ctx, TopLockOpts{Count: 10, Bucket: "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil || len(res) != 0 {
+		t.Errorf("Expected an empty non-nil slice, got %#v", res)
+	}
+}
+
+func TestForceUnlockResources(t *testing.T) {
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("paths") == "bucket/busy" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Code: "InvalidRequest", Message: "lock is busy"})
+		}
+	})
+
+	ctx := context.Background()
+	err := adm.ForceUnlockResources(ctx, []string{"bucket/stuck", "bucket/busy"})
+	errs, ok := err.(ForceUnlockErrors)
+	if !ok {
+		t.Fatalf("Expected ForceUnlockErrors, got %v", err)
+	}
+	if len(errs) != 1 || errs["bucket/busy"] == nil {
+		t.Errorf("Expected only bucket/busy to fail, got %v", errs)
+	}
+
+	if err = adm.ForceUnlockEntry(ctx, LockEntry{Resource: "bucket/stuck"}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err = adm.ForceUnlockResources(ctx, nil); err == nil {
+		t.Error("Expected an error for an empty resource list")
+	}
+	if err = adm.ForceUnlockEntry(ctx, LockEntry{}); err == nil {
+		t.Error("Expected an error for an empty resource")
+	}
+}
@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TraceCallSummary - summary of the traces of a single call path
+type TraceCallSummary struct {
+	FuncName  string        `json:"funcName"`
+	Path      string        `json:"path"`
+	Count     int           `json:"count"`
+	Errors    int           `json:"errors"`
+	ErrorRate float64       `json:"errorRate"`
+	P50       time.Duration `json:"p50"`
+	P90       time.Duration `json:"p90"`
+	P99       time.Duration `json:"p99"`
+}
+
+type traceCallKey struct {
+	funcName string
+	path     string
+}
+
+type traceCalls struct {
+	durations []time.Duration
+	errors    int
+}
+
+// TraceAggregator - aggregates the traces received on a channel for a
+// window of time into per call path summaries, calls are grouped by
+// function name and path.
+type TraceAggregator struct {
+	mu    sync.Mutex
+	calls map[traceCallKey]*traceCalls
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewTraceAggregator - starts aggregating the traces of traceCh, as returned
+// by ServiceTrace, until window elapses or traceCh is closed. Trace errors
+// are ignored.
+//
+// cancel should cancel the context of the trace producer, it is called as
+// soon as the aggregation ends, the remaining traces are then discarded
+// until traceCh is closed so that the producer is never left blocked. When
+// cancel is nil the caller must stop the producer itself.
+func NewTraceAggregator(traceCh <-chan ServiceTraceInfo, window time.Duration, cancel context.CancelFunc) *TraceAggregator {
+	a := &TraceAggregator{
+		calls:  make(map[traceCallKey]*traceCalls),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go a.run(traceCh, window, cancel)
+	return a
+}
+
+func (a *TraceAggregator) run(traceCh <-chan ServiceTraceInfo, window time.Duration, cancel context.CancelFunc) {
+	defer close(a.doneCh)
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	for {
+		select {
+		case <-a.stopCh:
+			stopTraceProducer(traceCh, cancel)
+			return
+		case <-timer.C:
+			stopTraceProducer(traceCh, cancel)
+			return
+		case info, ok := <-traceCh:
+			if !ok {
+				if cancel != nil {
+					cancel()
+				}
+				return
+			}
+			if info.Err == nil {
+				a.add(info.Trace)
+			}
+		}
+	}
+}
+
+// stopTraceProducer - cancels the producer of traceCh and discards what it
+// still sends until it closes traceCh.
+func stopTraceProducer(traceCh <-chan ServiceTraceInfo, cancel context.CancelFunc) {
+	if cancel == nil {
+		return
+	}
+	cancel()
+	go func() {
+		for range traceCh {
+		}
+	}()
+}
+
+func (a *TraceAggregator) add(t TraceInfo) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	k := traceCallKey{funcName: t.FuncName, path: t.Path}
+	c, ok := a.calls[k]
+	if !ok {
+		c = &traceCalls{}
+		a.calls[k] = c
+	}
+	c.durations = append(c.durations, t.Duration)
+	if t.Error != "" || (t.HTTP != nil && t.HTTP.RespInfo.StatusCode >= http.StatusInternalServerError) {
+		c.errors++
+	}
+}
+
+// Stop - stops the aggregation, possibly before the end of the window,
+// and returns the summaries of the traces received so far.
+func (a *TraceAggregator) Stop() []TraceCallSummary {
+	a.stopOnce.Do(func() { close(a.stopCh) })
+	<-a.doneCh
+	return a.Summaries()
+}
+
+// Wait - waits for the end of the window, or the trace channel to be
+// closed, and returns the summaries of all the traces received.
+func (a *TraceAggregator) Wait() []TraceCallSummary {
+	<-a.doneCh
+	return a.Summaries()
+}
+
+// Summaries - returns the summaries of the traces received so far,
+// sorted by function name and path.
+func (a *TraceAggregator) Summaries() []TraceCallSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	res := make([]TraceCallSummary, 0, len(a.calls))
+	for k, c := range a.calls {
+		durations := make([]time.Duration, len(c.durations))
+		copy(durations, c.durations)
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		res = append(res, TraceCallSummary{
+			FuncName:  k.funcName,
+			Path:      k.path,
+			Count:     len(durations),
+			Errors:    c.errors,
+			ErrorRate: float64(c.errors) / float64(len(durations)),
+			P50:       durationPercentile(durations, 50),
+			P90:       durationPercentile(durations, 90),
+			P99:       durationPercentile(durations, 99),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].FuncName != res[j].FuncName {
+			return res[i].FuncName < res[j].FuncName
+		}
+		return res[i].Path < res[j].Path
+	})
+	return res
+}
+
+// durationPercentile - returns the nearest-rank percentile p of the sorted durations.
+func durationPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTraceAggregator(t *testing.T) {
+	traceCh := make(chan ServiceTraceInfo)
+	agg := NewTraceAggregator(traceCh, time.Hour, nil)
+
+	// 100 GetObject calls taking 1ms to 100ms, every 10th one failing.
+	for i := 1; i <= 100; i++ {
+		ti := TraceInfo{FuncName: "s3.GetObject", Path: "/bucket/object", Duration: time.Duration(i) * time.Millisecond}
+		if i%10 == 0 {
+			ti.Error = "disk not found"
+		}
+		traceCh <- ServiceTraceInfo{Trace: ti}
+	}
+	traceCh <- ServiceTraceInfo{Trace: TraceInfo{
+		FuncName: "s3.PutObject", Path: "/bucket/object", Duration: time.Second,
+		HTTP: &TraceHTTPStats{RespInfo: TraceResponseInfo{StatusCode: 503}},
+	}}
+	traceCh <- ServiceTraceInfo{Err: errors.New("trace error")}
+
+	res := agg.Stop()
+	if len(res) != 2 {
+		t.Fatalf("Expected 2 call paths, got %d", len(res))
+	}
+	get := res[0]
+	if get.FuncName != "s3.GetObject" || get.Count != 100 || get.Errors != 10 || get.ErrorRate != 0.1 {
+		t.Errorf("Unexpected GetObject summary %+v", get)
+	}
+	if get.P50 != 50*time.Millisecond || get.P90 != 90*time.Millisecond || get.P99 != 99*time.Millisecond {
+		t.Errorf("Unexpected GetObject percentiles p50=%v p90=%v p99=%v", get.P50, get.P90, get.P99)
+	}
+	put := res[1]
+	if put.FuncName != "s3.PutObject" || put.Count != 1 || put.ErrorRate != 1 || put.P99 != time.Second {
+		t.Errorf("Unexpected PutObject summary %+v", put)
+	}
+
+	// Stopping again returns the same partial results.
+	if again := agg.Stop(); len(again) != 2 {
+		t.Errorf("Expected the same results after a second Stop, got %v", again)
+	}
+}
+
+func TestTraceAggregatorStopCancelsProducer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	traceCh := make(chan ServiceTraceInfo)
+	producerDone := make(chan struct{})
+	go func() {
+		defer close(producerDone)
+		defer close(traceCh)
+		for ctx.Err() == nil {
+			traceCh <- ServiceTraceInfo{Trace: TraceInfo{FuncName: "s3.GetObject"}}
+		}
+		// Like ServiceTrace, report the cancellation with a blocking send.
+		traceCh <- ServiceTraceInfo{Err: ctx.Err()}
+	}()
+
+	agg := NewTraceAggregator(traceCh, time.Hour, cancel)
+	time.Sleep(10 * time.Millisecond)
+	if res := agg.Stop(); len(res) != 1 || res[0].Count == 0 {
+		t.Errorf("Unexpected summaries %+v", res)
+	}
+
+	select {
+	case <-producerDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the trace producer to be stopped, it is still blocked")
+	}
+}
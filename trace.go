@@ -20,8 +20,12 @@
 package madmin
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/bits"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -109,6 +113,9 @@ func (t TraceType) Mask() uint64 {
 // TraceInfo - represents a trace record, additionally
 // also reports errors if any while listening on trace.
 type TraceInfo struct {
+	// Version of the trace schema, empty for servers predating
+	// versioned traces.
+	Version   string    `json:"version,omitempty"`
 	TraceType TraceType `json:"type"`
 
 	NodeName string        `json:"nodename"`
@@ -129,6 +136,81 @@ func (t TraceInfo) Mask() uint64 {
 	return t.TraceType.Mask()
 }
 
+// Category returns the trace type of the trace. Traces of servers not
+// reporting the type are categorized as S3 or Internal by function name.
+func (t TraceInfo) Category() TraceType {
+	if t.TraceType != 0 {
+		return t.TraceType
+	}
+	if strings.HasPrefix(t.FuncName, "s3.") {
+		return TraceS3
+	}
+	return TraceInternal
+}
+
+const (
+	// TraceInfoVersion1 is the trace schema with the HTTP details under
+	// "http", used since July 2022.
+	TraceInfoVersion1 = "1"
+	// TraceInfoVersion is the current trace schema version.
+	TraceInfoVersion = TraceInfoVersion1
+)
+
+// ErrUnknownTraceVersion is returned when decoding a trace of a schema
+// version newer than this package knows about.
+var ErrUnknownTraceVersion = errors.New("unknown trace info version")
+
+// DecodeTraceInfo - decodes a trace serialized as JSON. Besides the current
+// schema it accepts unversioned traces, including the schema of servers
+// before July 2022 which is converted to the current one.
+func DecodeTraceInfo(data []byte) (TraceInfo, error) {
+	var info traceInfoLegacy
+	if err := json.Unmarshal(data, &info); err != nil {
+		return TraceInfo{}, err
+	}
+	return info.convert()
+}
+
+// convert - returns the trace in the current schema, converting legacy
+// traces.
+func (info traceInfoLegacy) convert() (TraceInfo, error) {
+	switch info.Version {
+	case "", TraceInfoVersion1:
+	default:
+		return info.TraceInfo, fmt.Errorf("%w: %s", ErrUnknownTraceVersion, info.Version)
+	}
+
+	// Convert if legacy...
+	if info.TraceType == TraceType(0) {
+		if strings.HasPrefix(info.FuncName, "s3.") {
+			info.TraceType = TraceS3
+		} else {
+			info.TraceType = TraceInternal
+		}
+		info.HTTP = &TraceHTTPStats{}
+		if info.ReqInfo != nil {
+			info.Path = info.ReqInfo.Path
+			info.HTTP.ReqInfo = *info.ReqInfo
+		}
+		if info.RespInfo != nil {
+			info.HTTP.RespInfo = *info.RespInfo
+		}
+		if info.CallStats != nil {
+			info.Duration = info.CallStats.Latency
+			info.HTTP.CallStats = *info.CallStats
+		}
+	}
+	if info.TraceType == TraceOS && info.OSStats != nil {
+		info.Path = info.OSStats.Path
+		info.Duration = info.OSStats.Duration
+	}
+	if info.TraceType == TraceStorage && info.StorageStats != nil {
+		info.Path = info.StorageStats.Path
+		info.Duration = info.StorageStats.Duration
+	}
+	return info.TraceInfo, nil
+}
+
 // traceInfoLegacy - represents a trace record, additionally
 // also reports errors if any while listening on trace.
 // For minio versions before July 2022.
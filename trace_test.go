@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDecodeTraceInfo(t *testing.T) {
+	// Trace of a server from before July 2022.
+	legacy := []byte(`{
+  "nodename": "node1:9000",
+  "funcname": "s3.GetObject",
+  "time": "2022-03-01T10:00:00Z",
+  "request": {"time": "2022-03-01T10:00:00Z", "method": "GET", "path": "/bucket/object", "client": "10.0.0.1"},
+  "response": {"time": "2022-03-01T10:00:01Z", "statuscode": 200},
+  "stats": {"inputbytes": 0, "outputbytes": 1024, "latency": 1500000}
+}`)
+	info, err := DecodeTraceInfo(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.TraceType != TraceS3 || info.Path != "/bucket/object" || info.Duration != 1500*time.Microsecond {
+		t.Errorf("Unexpected trace %+v", info)
+	}
+	if info.HTTP == nil || info.HTTP.ReqInfo.Method != http.MethodGet || info.HTTP.RespInfo.StatusCode != http.StatusOK || info.HTTP.CallStats.OutputBytes != 1024 {
+		t.Errorf("Unexpected HTTP stats %+v", info.HTTP)
+	}
+
+	current := []byte(`{"version": "1", "type": 1, "nodename": "node1:9000", "funcname": "storage.ReadAll", "path": "/disk1/bucket/object", "dur": 2000}`)
+	if info, err = DecodeTraceInfo(current); err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != TraceInfoVersion1 || info.TraceType != TraceType(1) || info.Path != "/disk1/bucket/object" || info.Duration != 2*time.Microsecond {
+		t.Errorf("Unexpected trace %+v", info)
+	}
+
+	if _, err = DecodeTraceInfo([]byte(`{"version": "2", "type": 1}`)); !errors.Is(err, ErrUnknownTraceVersion) {
+		t.Errorf("Expected ErrUnknownTraceVersion, got %v", err)
+	}
+}
@@ -62,3 +62,80 @@ var DefaultTransport = func(secure bool) http.RoundTripper {
 	}
 	return tr
 }
+
+// TransportOptions - tunes the connection pool of the transport used by a
+// client, see Options.TransportOptions.
+type TransportOptions struct {
+	// Transport is used instead of the DefaultTransport when set. It is
+	// never modified, a transport which already matches the settings below
+	// is used as is, so that its connections can be shared between
+	// clients. Otherwise the client uses an adjusted clone of it, with a
+	// connection pool of its own.
+	Transport *http.Transport
+	// OwnTLS leaves the TLS configuration of Transport untouched. Otherwise
+	// a secure client sets a TLS configuration requiring TLS 1.2 or newer,
+	// keeping the other settings of an existing one.
+	OwnTLS bool
+
+	// Non-zero values override the corresponding http.Transport settings.
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	TLSHandshakeTimeout time.Duration
+}
+
+// newTransport - returns the transport of a client built from opts, the
+// DefaultTransport adjusted with o when o is set.
+func newTransport(secure bool, o *TransportOptions) http.RoundTripper {
+	if o == nil {
+		return DefaultTransport(secure)
+	}
+	tr := o.Transport
+	if tr == nil {
+		rt := DefaultTransport(secure)
+		var ok bool
+		if tr, ok = rt.(*http.Transport); !ok {
+			// DefaultTransport was replaced, nothing to tune.
+			return rt
+		}
+	} else if transportNeedsTuning(secure, tr, o) {
+		tr = tr.Clone()
+	} else {
+		return tr
+	}
+	if secure && !o.OwnTLS {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		if tr.TLSClientConfig.MinVersion < tls.VersionTLS12 {
+			tr.TLSClientConfig.MinVersion = tls.VersionTLS12
+		}
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+		if tr.MaxIdleConns > 0 && tr.MaxIdleConns < o.MaxIdleConnsPerHost {
+			tr.MaxIdleConns = o.MaxIdleConnsPerHost
+		}
+	}
+	if o.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = o.IdleConnTimeout
+	}
+	if o.TLSHandshakeTimeout > 0 {
+		tr.TLSHandshakeTimeout = o.TLSHandshakeTimeout
+	}
+	return tr
+}
+
+// transportNeedsTuning - returns true if applying o to tr would change it.
+func transportNeedsTuning(secure bool, tr *http.Transport, o *TransportOptions) bool {
+	if secure && !o.OwnTLS && (tr.TLSClientConfig == nil || tr.TLSClientConfig.MinVersion < tls.VersionTLS12) {
+		return true
+	}
+	if o.MaxIdleConnsPerHost > 0 && (tr.MaxIdleConnsPerHost != o.MaxIdleConnsPerHost ||
+		(tr.MaxIdleConns > 0 && tr.MaxIdleConns < o.MaxIdleConnsPerHost)) {
+		return true
+	}
+	if o.IdleConnTimeout > 0 && tr.IdleConnTimeout != o.IdleConnTimeout {
+		return true
+	}
+	return o.TLSHandshakeTimeout > 0 && tr.TLSHandshakeTimeout != o.TLSHandshakeTimeout
+}
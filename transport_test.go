@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	tr := newTransport(true, &TransportOptions{
+		MaxIdleConnsPerHost: 4096,
+		IdleConnTimeout:     5 * time.Minute,
+		TLSHandshakeTimeout: time.Second,
+	}).(*http.Transport)
+	if tr.MaxIdleConnsPerHost != 4096 || tr.MaxIdleConns != 4096 || tr.IdleConnTimeout != 5*time.Minute || tr.TLSHandshakeTimeout != time.Second {
+		t.Errorf("Unexpected pool settings %d %d %v %v", tr.MaxIdleConnsPerHost, tr.MaxIdleConns, tr.IdleConnTimeout, tr.TLSHandshakeTimeout)
+	}
+	if tr.TLSClientConfig == nil || tr.TLSClientConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected the client TLS config, got %+v", tr.TLSClientConfig)
+	}
+
+	pool := x509.NewCertPool()
+	custom := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS10}}
+	got := newTransport(true, &TransportOptions{Transport: custom}).(*http.Transport)
+	if got == custom {
+		t.Fatal("Expected a clone of the custom transport to be adjusted")
+	}
+	if got.TLSClientConfig.MinVersion != tls.VersionTLS12 || got.TLSClientConfig.RootCAs != pool {
+		t.Errorf("Expected the minimum TLS version to be raised keeping the root CAs, got %+v", got.TLSClientConfig)
+	}
+	if custom.TLSClientConfig.MinVersion != tls.VersionTLS10 {
+		t.Error("Expected the custom transport to be left untouched")
+	}
+
+	shared := &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS13}, IdleConnTimeout: time.Minute}
+	if got := newTransport(true, &TransportOptions{Transport: shared, IdleConnTimeout: time.Minute}); got != shared {
+		t.Error("Expected a matching transport to be shared as is")
+	}
+
+	owned := &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS10}}
+	if got := newTransport(true, &TransportOptions{Transport: owned, OwnTLS: true}); got != owned {
+		t.Error("Expected an owned TLS config to be used as is")
+	}
+	if owned.TLSClientConfig.MinVersion != tls.VersionTLS10 {
+		t.Error("Expected an owned TLS config to be left untouched")
+	}
+
+	plain := &http.Transport{}
+	newTransport(false, &TransportOptions{Transport: plain, MaxIdleConnsPerHost: 10})
+	if plain.MaxIdleConnsPerHost != 0 {
+		t.Error("Expected the custom transport to be left untouched")
+	}
+}
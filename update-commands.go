@@ -22,9 +22,14 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ServerPeerUpdateStatus server update peer binary update result
@@ -117,3 +122,232 @@ func (adm *AdminClient) ServerUpdate(ctx context.Context, updateURL string) (us
 
 	return us, nil
 }
+
+// UpdateCheckResult - versions reported by ServerUpdateCheck
+type UpdateCheckResult struct {
+	UpdateURL        string                   `json:"updateURL,omitempty"`
+	CurrentVersion   string                   `json:"currentVersion"`
+	AvailableVersion string                   `json:"availableVersion"`
+	UpdateNeeded     bool                     `json:"updateNeeded"`
+	Peers            []ServerPeerUpdateStatus `json:"peers,omitempty"`
+}
+
+// String returns a short description of the check result.
+func (r UpdateCheckResult) String() string {
+	if !r.UpdateNeeded {
+		return "up to date"
+	}
+	return fmt.Sprintf("update available: %s -> %s", r.CurrentVersion, r.AvailableVersion)
+}
+
+// UpdateOpts returns the options applying the update which was checked.
+func (r UpdateCheckResult) UpdateOpts() ServerUpdateOpts {
+	return ServerUpdateOpts{UpdateURL: r.UpdateURL}
+}
+
+// releaseTagTimeLayout - layout of the release time in release tags,
+// the format of ServerPeerUpdateStatus.UpdatedVersion.
+const releaseTagTimeLayout = "2006-01-02T15-04-05Z"
+
+// parseServerVersion - parses a server version, either a release time as
+// reported by ServerProperties.Version and ServerPeerUpdateStatus.CurrentVersion
+// or a release tag as reported by ServerPeerUpdateStatus.UpdatedVersion.
+func parseServerVersion(v string) (time.Time, bool) {
+	v = strings.TrimPrefix(v, "RELEASE.")
+	for _, layout := range []string{time.RFC3339, releaseTagTimeLayout} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// compareServerVersions - compares two server versions by release time,
+// whatever their formats. Versions which are not release versions, such
+// as development builds, are compared as strings.
+func compareServerVersions(a, b string) int {
+	ta, okA := parseServerVersion(a)
+	tb, okB := parseServerVersion(b)
+	switch {
+	case okA && okB && ta.Before(tb):
+		return -1
+	case okA && okB && ta.After(tb):
+		return 1
+	case okA && okB:
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// ServerUpdateCheck - checks whether the servers can be updated, without
+// applying anything. The default release channel is used when updateURL is
+// empty. The current version is the oldest version running on the servers,
+// the result can be applied with ServerUpdateV2(ctx, res.UpdateOpts()).
+func (adm *AdminClient) ServerUpdateCheck(ctx context.Context, updateURL string) (UpdateCheckResult, error) {
+	us, err := adm.ServerUpdateV2(ctx, ServerUpdateOpts{UpdateURL: updateURL, DryRun: true})
+	if err != nil {
+		return UpdateCheckResult{}, err
+	}
+
+	res := UpdateCheckResult{UpdateURL: updateURL, Peers: us.Results}
+	var errs []string
+	for _, peer := range us.Results {
+		if peer.Err != "" {
+			errs = append(errs, peer.Host+": "+peer.Err)
+			continue
+		}
+		if res.CurrentVersion == "" || compareServerVersions(peer.CurrentVersion, res.CurrentVersion) < 0 {
+			res.CurrentVersion = peer.CurrentVersion
+		}
+		if res.AvailableVersion == "" || compareServerVersions(peer.UpdatedVersion, res.AvailableVersion) > 0 {
+			res.AvailableVersion = peer.UpdatedVersion
+		}
+		if peer.UpdatedVersion != "" && compareServerVersions(peer.UpdatedVersion, peer.CurrentVersion) != 0 {
+			res.UpdateNeeded = true
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(us.Results) {
+		return res, fmt.Errorf("unable to check for updates: %s", strings.Join(errs, "; "))
+	}
+	if res.AvailableVersion == "" {
+		res.AvailableVersion = res.CurrentVersion
+	}
+	return res, nil
+}
+
+// ErrRollingUpdateUnhealthy is returned by RollingServerUpdate when the
+// cluster does not return to healthy after updating a node or pool.
+var ErrRollingUpdateUnhealthy = errors.New("cluster did not return to healthy after update")
+
+// Defaults of RollingUpdateOpts.
+const (
+	defaultRollingUpdateHealthTimeout = 5 * time.Minute
+	defaultRollingUpdatePollInterval  = 5 * time.Second
+)
+
+// RollingUpdateOpts - options of RollingServerUpdate
+type RollingUpdateOpts struct {
+	// UpdateNode updates and restarts a single server. It is required, the
+	// update API of the server always updates all servers at once.
+	UpdateNode func(ctx context.Context, host, updateURL string) error
+	// ByPool updates all servers of a pool before waiting for the
+	// cluster to be healthy, instead of one server at a time.
+	ByPool bool
+	// HealthTimeout is how long to wait for the cluster to be healthy
+	// after each step, defaults to 5 minutes.
+	HealthTimeout time.Duration
+	// PollInterval between two health checks, defaults to 5 seconds.
+	PollInterval time.Duration
+}
+
+// RollingUpdateResult - servers updated by RollingServerUpdate
+type RollingUpdateResult struct {
+	Version string   `json:"version"`
+	Updated []string `json:"updated,omitempty"`
+}
+
+// RollingServerUpdate - updates the servers to the version available at
+// updateURL one server, or one pool, at a time. After each step it waits for
+// the updated servers to run the new version and for the cluster to be
+// healthy according to InfoMessage.HealthScore. It stops at the first step
+// not healthy within opts.HealthTimeout, the returned result lists the
+// servers updated successfully until then. Servers already running the
+// new version are skipped.
+func (adm *AdminClient) RollingServerUpdate(ctx context.Context, updateURL string, opts RollingUpdateOpts) (RollingUpdateResult, error) {
+	if opts.UpdateNode == nil {
+		return RollingUpdateResult{}, fmt.Errorf("%w: servers cannot be updated one at a time without an UpdateNode function", ErrNotSupported)
+	}
+	if opts.HealthTimeout <= 0 {
+		opts.HealthTimeout = defaultRollingUpdateHealthTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultRollingUpdatePollInterval
+	}
+
+	check, err := adm.ServerUpdateCheck(ctx, updateURL)
+	if err != nil {
+		return RollingUpdateResult{}, err
+	}
+	res := RollingUpdateResult{Version: check.AvailableVersion}
+	if !check.UpdateNeeded {
+		return res, nil
+	}
+
+	info, err := adm.ServerInfo(ctx)
+	if err != nil {
+		return res, err
+	}
+	for _, hosts := range rollingUpdateSteps(info, check.AvailableVersion, opts.ByPool) {
+		for _, host := range hosts {
+			if err = opts.UpdateNode(ctx, host, updateURL); err != nil {
+				return res, fmt.Errorf("unable to update %s: %w", host, err)
+			}
+		}
+		if err = adm.waitRollingUpdateStep(ctx, hosts, check.AvailableVersion, opts); err != nil {
+			return res, err
+		}
+		res.Updated = append(res.Updated, hosts...)
+	}
+	return res, nil
+}
+
+// rollingUpdateSteps - groups the servers not running version yet by
+// update step, sorted by pool and endpoint.
+func rollingUpdateSteps(info InfoMessage, version string, byPool bool) [][]string {
+	servers := make([]ServerProperties, 0, len(info.Servers))
+	for _, srv := range info.Servers {
+		if compareServerVersions(srv.Version, version) != 0 {
+			servers = append(servers, srv)
+		}
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].PoolNumber != servers[j].PoolNumber {
+			return servers[i].PoolNumber < servers[j].PoolNumber
+		}
+		return servers[i].Endpoint < servers[j].Endpoint
+	})
+
+	var steps [][]string
+	for i, srv := range servers {
+		if byPool && i > 0 && servers[i-1].PoolNumber == srv.PoolNumber {
+			steps[len(steps)-1] = append(steps[len(steps)-1], srv.Endpoint)
+			continue
+		}
+		steps = append(steps, []string{srv.Endpoint})
+	}
+	return steps
+}
+
+// waitRollingUpdateStep - waits for hosts to run version and the
+// cluster to be healthy.
+func (adm *AdminClient) waitRollingUpdateStep(parent context.Context, hosts []string, version string, opts RollingUpdateOpts) error {
+	ctx, cancel := context.WithTimeout(parent, opts.HealthTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		info, err := adm.ServerInfo(ctx)
+		if err == nil && info.HealthScore().Status == HealthStatusHealthy {
+			updated := 0
+			for _, srv := range info.Servers {
+				for _, host := range hosts {
+					if srv.Endpoint == host && compareServerVersions(srv.Version, version) == 0 {
+						updated++
+					}
+				}
+			}
+			if updated == len(hosts) {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if err := parent.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s", ErrRollingUpdateUnhealthy, strings.Join(hosts, ", "))
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,177 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestServerUpdateCheck(t *testing.T) {
+	// Servers report their current version as a release time and the
+	// updated version in the release tag format.
+	const (
+		oldRelease = "2024-01-01T00:00:00Z"
+		newRelease = "2024-02-01T00:00:00Z"
+		newTag     = "2024-02-01T00-00-00Z"
+	)
+	var results []ServerPeerUpdateStatus
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("dry-run") != "true" || q.Get("type") != "2" || q.Get("updateURL") != "" {
+			t.Errorf("Unexpected query %v", q)
+		}
+		json.NewEncoder(w).Encode(ServerUpdateStatusV2{DryRun: true, Results: results})
+	})
+
+	results = []ServerPeerUpdateStatus{
+		{Host: "node1", CurrentVersion: newRelease, UpdatedVersion: newTag},
+		{Host: "node2", CurrentVersion: newRelease, UpdatedVersion: newTag},
+	}
+	res, err := adm.ServerUpdateCheck(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.UpdateNeeded || res.CurrentVersion != newRelease || res.AvailableVersion != newTag {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if res.String() != "up to date" {
+		t.Errorf("Expected up to date, got %q", res)
+	}
+
+	results = []ServerPeerUpdateStatus{
+		{Host: "node1", CurrentVersion: newRelease, UpdatedVersion: newTag},
+		{Host: "node2", CurrentVersion: oldRelease, UpdatedVersion: newTag},
+		{Host: "node3", Err: "timeout"},
+	}
+	if res, err = adm.ServerUpdateCheck(context.Background(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if !res.UpdateNeeded || res.CurrentVersion != oldRelease || res.AvailableVersion != newTag {
+		t.Errorf("Unexpected result %+v", res)
+	}
+
+	results = []ServerPeerUpdateStatus{{Host: "node1", Err: "timeout"}}
+	if _, err = adm.ServerUpdateCheck(context.Background(), ""); err == nil {
+		t.Error("Expected an error when all servers failed")
+	}
+}
+
+func TestCompareServerVersions(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{a: "2024-02-01T00:00:00Z", b: "2024-02-01T00-00-00Z", want: 0},
+		{a: "2024-02-01T00:00:00Z", b: "RELEASE.2024-02-01T00-00-00Z", want: 0},
+		{a: "2024-01-01T00:00:00Z", b: "2024-02-01T00-00-00Z", want: -1},
+		{a: "2024-02-01T00-00-00Z", b: "2024-01-01T00:00:00Z", want: 1},
+		{a: "DEVELOPMENT.GOGET", b: "DEVELOPMENT.GOGET", want: 0},
+	}
+	for _, tc := range testCases {
+		if got := compareServerVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareServerVersions(%q, %q) = %d, expected %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestRollingServerUpdate(t *testing.T) {
+	// Servers report their version as a release time and the updated
+	// version in the release tag format.
+	const (
+		oldRelease = "2024-01-01T00:00:00Z"
+		newRelease = "2024-02-01T00:00:00Z"
+		newTag     = "2024-02-01T00-00-00Z"
+	)
+	var mu sync.Mutex
+	servers := map[string]*ServerProperties{}
+	for _, host := range []string{"node0:9000", "node1:9000", "node2:9000", "node3:9000"} {
+		servers[host] = &ServerProperties{
+			Endpoint: host,
+			State:    string(ItemOnline),
+			Version:  oldRelease,
+			Disks:    []Disk{{State: DriveStateOk, PoolIndex: -1, SetIndex: -1}},
+		}
+	}
+	// Already running the new version, it must be skipped.
+	servers["node0:9000"].Version = newRelease
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/update":
+			if r.URL.Query().Get("dry-run") != "true" {
+				t.Error("Expected a dry run")
+			}
+			var us ServerUpdateStatusV2
+			for host, srv := range servers {
+				us.Results = append(us.Results, ServerPeerUpdateStatus{Host: host, CurrentVersion: srv.Version, UpdatedVersion: newTag})
+			}
+			json.NewEncoder(w).Encode(us)
+		case libraryAdminURLPrefix + adminAPIPrefix + "/info":
+			var info InfoMessage
+			for _, srv := range servers {
+				info.Servers = append(info.Servers, *srv)
+			}
+			json.NewEncoder(w).Encode(info)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	var order []string
+	opts := RollingUpdateOpts{
+		UpdateNode: func(_ context.Context, host, _ string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, host)
+			srv := servers[host]
+			srv.Version = newRelease
+			if host == "node3:9000" {
+				// Does not come back after the update.
+				srv.State = string(ItemOffline)
+			}
+			return nil
+		},
+		HealthTimeout: 100 * time.Millisecond,
+		PollInterval:  10 * time.Millisecond,
+	}
+	res, err := adm.RollingServerUpdate(context.Background(), "", opts)
+	if !errors.Is(err, ErrRollingUpdateUnhealthy) {
+		t.Fatalf("Expected ErrRollingUpdateUnhealthy, got %v", err)
+	}
+	if !reflect.DeepEqual(res.Updated, []string{"node1:9000", "node2:9000"}) || res.Version != newTag {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if !reflect.DeepEqual(order, []string{"node1:9000", "node2:9000", "node3:9000"}) {
+		t.Errorf("Unexpected update order %v", order)
+	}
+
+	if _, err = adm.RollingServerUpdate(context.Background(), "", RollingUpdateOpts{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported without UpdateNode, got %v", err)
+	}
+}
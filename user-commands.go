@@ -21,12 +21,19 @@ package madmin
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/minio/minio-go/v7/pkg/tags"
@@ -124,6 +131,24 @@ const (
 	AccountDisabled AccountStatus = "disabled"
 )
 
+// ErrInvalidUserStatus is returned for an account status other than
+// AccountEnabled and AccountDisabled.
+var ErrInvalidUserStatus = errors.New("invalid user status, must be either enabled or disabled")
+
+// IsValid returns true if the account status is a known status.
+func (s AccountStatus) IsValid() bool {
+	return s == AccountEnabled || s == AccountDisabled
+}
+
+// UserStatusFromString - parses an account status, ignoring case.
+func UserStatusFromString(s string) (AccountStatus, error) {
+	status := AccountStatus(strings.ToLower(strings.TrimSpace(s)))
+	if !status.IsValid() {
+		return "", ErrInvalidUserStatus
+	}
+	return status, nil
+}
+
 // UserAuthType indicates the type of authentication for the user.
 type UserAuthType string
 
@@ -213,6 +238,58 @@ func (adm *AdminClient) ListUsers(ctx context.Context) (map[string]UserInfo, err
 	return users, nil
 }
 
+// UserIterator - iterates over users one at a time, see ListUsersIter.
+type UserIterator struct {
+	users map[string]UserInfo
+	names []string
+
+	name string
+	info UserInfo
+	err  error
+}
+
+// ListUsersIter - returns an iterator over all users, sorted by access key.
+// The server sends all users in a single response, entries are released
+// by the iterator once they have been visited.
+func (adm *AdminClient) ListUsersIter(ctx context.Context) (*UserIterator, error) {
+	users, err := adm.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newUserIterator(users), nil
+}
+
+func newUserIterator(users map[string]UserInfo) *UserIterator {
+	names := make([]string, 0, len(users))
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &UserIterator{users: users, names: names}
+}
+
+// Next - advances to the next user, returns false when there are no more users.
+func (it *UserIterator) Next() bool {
+	if it.err != nil || len(it.names) == 0 {
+		it.name, it.info = "", UserInfo{}
+		return false
+	}
+	it.name, it.names = it.names[0], it.names[1:]
+	it.info = it.users[it.name]
+	delete(it.users, it.name)
+	return true
+}
+
+// Value - returns the access key and info of the current user.
+func (it *UserIterator) Value() (string, UserInfo) {
+	return it.name, it.info
+}
+
+// Err - returns the error encountered while iterating, if any.
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
 // GetUserInfo - get info on a user
 func (adm *AdminClient) GetUserInfo(ctx context.Context, name string) (u UserInfo, err error) {
 	queryValues := url.Values{}
@@ -247,6 +324,83 @@ func (adm *AdminClient) GetUserInfo(ctx context.Context, name string) (u UserInf
 	return u, nil
 }
 
+// defaultGetUsersInfoConcurrency is the default number of parallel
+// lookups made by GetUsersInfo.
+const defaultGetUsersInfoConcurrency = 8
+
+// GetUsersInfoOpts - options for GetUsersInfo
+type GetUsersInfoOpts struct {
+	// Concurrency is the maximum number of parallel lookups,
+	// defaults to 8 when zero or negative.
+	Concurrency int
+}
+
+// UsersInfoErrors - the failed lookups of GetUsersInfo by user name.
+type UsersInfoErrors map[string]error
+
+func (e UsersInfoErrors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = name + ": " + e[name].Error()
+	}
+	return fmt.Sprintf("%d user lookups failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// GetUsersInfo - get info on multiple users, looking them up in parallel.
+// The info of every user found is returned in the map. A failed lookup does
+// not fail the whole batch, the error is then of type UsersInfoErrors and
+// holds the error of every user which could not be looked up. Users not
+// looked up yet when ctx is canceled get ctx.Err().
+func (adm *AdminClient) GetUsersInfo(ctx context.Context, names []string, opts ...GetUsersInfoOpts) (map[string]UserInfo, error) {
+	concurrency := defaultGetUsersInfoConcurrency
+	if len(opts) > 0 && opts[0].Concurrency > 0 {
+		concurrency = opts[0].Concurrency
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		sem   = make(chan struct{}, concurrency)
+		infos = make(map[string]UserInfo, len(names))
+		errs  = make(UsersInfoErrors)
+	)
+	for _, name := range names {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs[name] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			info, err := adm.GetUserInfo(ctx, name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[name] = err
+			} else {
+				infos[name] = info
+			}
+		}(name)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return infos, errs
+	}
+	return infos, nil
+}
+
 // AddOrUpdateUserReq allows to update
 //   - user details such as secret key
 //   - account status.
@@ -306,8 +460,92 @@ func (adm *AdminClient) AddUser(ctx context.Context, accessKey, secretKey string
 	return adm.SetUser(ctx, accessKey, secretKey, AccountEnabled)
 }
 
+// UserSpec - desired state of a user, see EnsureUser.
+type UserSpec struct {
+	// SecretKey is only used when the user is created.
+	SecretKey string
+	// Status defaults to AccountEnabled.
+	Status AccountStatus
+	// Policies attached to the user, left untouched when nil.
+	Policies []string
+}
+
+// EnsureUser - makes the user accessKey match spec, creating it if it does
+// not exist. The status and policies of an existing user are only updated
+// when they differ and its secret key is never changed. Returns whether
+// any change was made.
+func (adm *AdminClient) EnsureUser(ctx context.Context, accessKey string, spec UserSpec) (changed bool, err error) {
+	status := spec.Status
+	if status == "" {
+		status = AccountEnabled
+	}
+	if !status.IsValid() {
+		return false, ErrInvalidUserStatus
+	}
+
+	info, err := adm.GetUserInfo(ctx, accessKey)
+	if errors.Is(err, ErrNotFound) {
+		if spec.SecretKey == "" {
+			return false, ErrInvalidArgument("secret key is required to create user " + accessKey)
+		}
+		err = adm.SetUserReq(ctx, accessKey, AddOrUpdateUserReq{
+			SecretKey: spec.SecretKey,
+			Policy:    strings.Join(spec.Policies, ","),
+			Status:    status,
+		})
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if info.Status != status {
+		if err = adm.SetUserStatus(ctx, accessKey, status); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if spec.Policies != nil && !samePolicies(info.PolicyName, spec.Policies) {
+		if err = adm.SetPolicy(ctx, strings.Join(spec.Policies, ","), accessKey, false); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// samePolicies - reports whether the comma separated policy list current
+// holds the same policies as desired, in any order.
+func samePolicies(current string, desired []string) bool {
+	have := make(map[string]struct{})
+	for _, p := range strings.Split(current, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			have[p] = struct{}{}
+		}
+	}
+	want := make(map[string]struct{}, len(desired))
+	for _, p := range desired {
+		if p = strings.TrimSpace(p); p != "" {
+			want[p] = struct{}{}
+		}
+	}
+	if len(have) != len(want) {
+		return false
+	}
+	for p := range want {
+		if _, ok := have[p]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // SetUserStatus - adds a status for a user.
 func (adm *AdminClient) SetUserStatus(ctx context.Context, accessKey string, status AccountStatus) error {
+	if !status.IsValid() {
+		return ErrInvalidUserStatus
+	}
+
 	queryValues := url.Values{}
 	queryValues.Set("accessKey", accessKey)
 	queryValues.Set("status", string(status))
@@ -343,7 +581,8 @@ type AddServiceAccountReq struct {
 	Name string `json:"name,omitempty"`
 	// Description for this access key
 	Description string `json:"description,omitempty"`
-	// Time at which this access key expires
+	// Time at which this access key expires, servers that do not
+	// support expiration ignore it and create a non-expiring key.
 	Expiration *time.Time `json:"expiration,omitempty"`
 
 	// Deprecated: use description instead
@@ -529,6 +768,31 @@ func (adm *AdminClient) UpdateServiceAccount(ctx context.Context, accessKey stri
 	return nil
 }
 
+// serviceAccountSecretKeyLen is the length of the secret keys generated by
+// UpdateServiceAccountSecret, same as the ones generated by the server.
+const serviceAccountSecretKeyLen = 40
+
+// UpdateServiceAccountSecret - replaces the secret key of an existing service
+// account, keeping its access key, policy and parent user. The server has no
+// API to generate the secret, so it is generated here and returned to the
+// caller only, it cannot be retrieved again later.
+func (adm *AdminClient) UpdateServiceAccountSecret(ctx context.Context, accessKey string) (string, error) {
+	keyBytes := make([]byte, base64.StdEncoding.DecodedLen(serviceAccountSecretKeyLen))
+	if _, err := io.ReadFull(rand.Reader, keyBytes); err != nil {
+		return "", err
+	}
+	secretKey := base64.StdEncoding.EncodeToString(keyBytes)[:serviceAccountSecretKeyLen]
+	secretKey = strings.ReplaceAll(secretKey, "/", "+")
+
+	if err := adm.UpdateServiceAccount(ctx, accessKey, UpdateServiceAccountReq{NewSecretKey: secretKey}); err != nil {
+		return "", err
+	}
+	return secretKey, nil
+}
+
+// ServiceAccountInfo contains the access key of a service account and its
+// expiration, Expiration is nil for non-expiring keys and for servers which
+// do not report it.
 type ServiceAccountInfo struct {
 	AccessKey  string     `json:"accessKey"`
 	Expiration *time.Time `json:"expiration,omitempty"`
@@ -572,6 +836,69 @@ func (adm *AdminClient) ListServiceAccounts(ctx context.Context, user string) (L
 	return listResp, nil
 }
 
+// ListAccessKeysOpts - filters for ListAccessKeysForUser
+type ListAccessKeysOpts struct {
+	// Status only lists access keys with the given status,
+	// all access keys are listed when empty.
+	Status AccountStatus
+	// OnlyExpiring only lists access keys with an expiration set.
+	OnlyExpiring bool
+}
+
+// AccessKeyInfo - information about a service account access key
+type AccessKeyInfo struct {
+	AccessKey  string        `json:"accessKey"`
+	ParentUser string        `json:"parentUser"`
+	Status     AccountStatus `json:"status"`
+	Expiration *time.Time    `json:"expiration,omitempty"`
+}
+
+// ListAccessKeysForUser - lists the service accounts of the user together
+// with their status, filtered according to opts.
+func (adm *AdminClient) ListAccessKeysForUser(ctx context.Context, user string, opts ListAccessKeysOpts) ([]AccessKeyInfo, error) {
+	if opts.Status != "" && !opts.Status.IsValid() {
+		return nil, ErrInvalidUserStatus
+	}
+
+	list, err := adm.ListServiceAccounts(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []AccessKeyInfo
+	for _, acc := range list.Accounts {
+		if opts.OnlyExpiring && acc.Expiration == nil {
+			continue
+		}
+		info, err := adm.InfoServiceAccount(ctx, acc.AccessKey)
+		if err != nil {
+			return nil, err
+		}
+		// Service accounts report their status as "on" or "off".
+		status := AccountStatus(info.AccountStatus)
+		switch info.AccountStatus {
+		case "on":
+			status = AccountEnabled
+		case "off":
+			status = AccountDisabled
+		}
+		if opts.Status != "" && status != opts.Status {
+			continue
+		}
+		expiration := info.Expiration
+		if expiration == nil {
+			expiration = acc.Expiration
+		}
+		keys = append(keys, AccessKeyInfo{
+			AccessKey:  acc.AccessKey,
+			ParentUser: info.ParentUser,
+			Status:     status,
+			Expiration: expiration,
+		})
+	}
+	return keys, nil
+}
+
 // ListAccessKeysLDAPResp is the response body of the list service accounts call
 type ListAccessKeysLDAPResp struct {
 	ServiceAccounts []ServiceAccountInfo `json:"serviceAccounts"`
@@ -716,3 +1043,142 @@ func (adm *AdminClient) TemporaryAccountInfo(ctx context.Context, accessKey stri
 	}
 	return infoResp, nil
 }
+
+// TempAccountInfo - information about a temporary (STS) account
+type TempAccountInfo struct {
+	AccessKey     string     `json:"accessKey"`
+	ParentUser    string     `json:"parentUser"`
+	AccountStatus string     `json:"accountStatus"`
+	ImpliedPolicy bool       `json:"impliedPolicy"`
+	Policy        string     `json:"policy"`
+	Expiration    *time.Time `json:"expiration,omitempty"`
+}
+
+// ListTempAccounts - lists the temporary accounts derived from parentUser,
+// the temporary accounts of all users when parentUser is empty. The access
+// keys are listed with the bulk access keys API, the details of each one
+// are read from the temporary account info API.
+func (adm *AdminClient) ListTempAccounts(ctx context.Context, parentUser string) ([]TempAccountInfo, error) {
+	queryValues := url.Values{}
+	queryValues.Set("listType", "sts-only")
+	if parentUser != "" {
+		queryValues.Set("users", parentUser)
+	} else {
+		queryValues.Set("all", "true")
+	}
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/list-access-keys-bulk",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/list-access-keys-bulk
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	data, err := DecryptData(adm.getSecretKey(), resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var listResp map[string]ListAccessKeysLDAPResp
+	if err = json.Unmarshal(data, &listResp); err != nil {
+		return nil, err
+	}
+	users := make([]string, 0, len(listResp))
+	for user := range listResp {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	var accounts []TempAccountInfo
+	for _, user := range users {
+		for _, key := range listResp[user].STSKeys {
+			info, err := adm.TemporaryAccountInfo(ctx, key.AccessKey)
+			if err != nil {
+				if isNoSuchAccount(err) {
+					// Expired since it was listed.
+					continue
+				}
+				return nil, err
+			}
+			expiration := info.Expiration
+			if expiration == nil {
+				expiration = key.Expiration
+			}
+			accounts = append(accounts, TempAccountInfo{
+				AccessKey:     key.AccessKey,
+				ParentUser:    info.ParentUser,
+				AccountStatus: info.AccountStatus,
+				ImpliedPolicy: info.ImpliedPolicy,
+				Policy:        info.Policy,
+				Expiration:    expiration,
+			})
+		}
+	}
+	return accounts, nil
+}
+
+// RevokeTempAccount - revokes the temporary account accessKey before its
+// expiration, with the token revocation API of its parent user. The server
+// revokes tokens by parent user and token revoke type, so all the temporary
+// accounts of the parent user issued with tokenRevokeType are revoked as
+// well. Revoking an account which does not exist, or has already expired,
+// is not an error.
+func (adm *AdminClient) RevokeTempAccount(ctx context.Context, accessKey, tokenRevokeType string) error {
+	if accessKey == "" || tokenRevokeType == "" {
+		return ErrInvalidArgument("access key and token revoke type are required")
+	}
+	info, err := adm.TemporaryAccountInfo(ctx, accessKey)
+	if err != nil {
+		if isNoSuchAccount(err) {
+			return nil
+		}
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("user", info.ParentUser)
+	queryValues.Set("tokenRevokeType", tokenRevokeType)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/revoke-tokens/builtin",
+		queryValues: queryValues,
+	}
+
+	// Execute POST on /minio/admin/v3/revoke-tokens/builtin
+	resp, err := adm.executeMethod(ctx, http.MethodPost, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		err = httpRespToErrorResponse(resp)
+		if isNoSuchAccount(err) {
+			// The parent user, and so its tokens, is gone.
+			return nil
+		}
+		switch resp.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return fmt.Errorf("%w: token revocation: %v", ErrNotSupported, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// isNoSuchAccount - returns true if the server explicitly reported that
+// the account does not exist. A bare 404 may come from a server without
+// the API, it does not tell that there is no account.
+func isNoSuchAccount(err error) bool {
+	code := ToErrorResponse(err).Code
+	return code != "" && errors.Is(ErrorResponse{Code: code}, ErrNotFound)
+}
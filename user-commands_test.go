@@ -0,0 +1,437 @@
+//
+// Copyright (c) 2015-2024 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "minioadmin"
+	testSecretKey = "minioadmin123"
+)
+
+// newTestAdminClient - returns an admin client talking to the given handler.
+func newTestAdminClient(t *testing.T, handler http.HandlerFunc) *AdminClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	adm, err := New(strings.TrimPrefix(srv.URL, "http://"), testAccessKey, testSecretKey, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return adm
+}
+
+func TestListUsersIter(t *testing.T) {
+	const numUsers = 10000
+	users := make(map[string]UserInfo, numUsers)
+	for i := 0; i < numUsers; i++ {
+		users[fmt.Sprintf("user-%05d", i)] = UserInfo{Status: AccountEnabled}
+	}
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/list-users" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, err := json.Marshal(users)
+		if err != nil {
+			t.Error(err)
+		}
+		edata, err := EncryptData(testSecretKey, data)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(edata)
+	})
+
+	it, err := adm.ListUsersIter(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	prev := ""
+	for it.Next() {
+		name, info := it.Value()
+		if name <= prev {
+			t.Fatalf("Expected sorted users, got %q after %q", name, prev)
+		}
+		if info.Status != AccountEnabled {
+			t.Fatalf("Unexpected status %q for %s", info.Status, name)
+		}
+		prev = name
+		n++
+	}
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != numUsers {
+		t.Errorf("Expected %d users, got %d", numUsers, n)
+	}
+}
+
+func TestUserStatusFromString(t *testing.T) {
+	testCases := []struct {
+		s       string
+		status  AccountStatus
+		wantErr bool
+	}{
+		{"enabled", AccountEnabled, false},
+		{"Disabled", AccountDisabled, false},
+		{" ENABLED ", AccountEnabled, false},
+		{"enable", "", true},
+		{"", "", true},
+	}
+	for _, tc := range testCases {
+		status, err := UserStatusFromString(tc.s)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%q: unexpected error %v", tc.s, err)
+		}
+		if status != tc.status {
+			t.Errorf("%q: expected %q, got %q", tc.s, tc.status, status)
+		}
+	}
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Unexpected request for an invalid status")
+	})
+	if err := adm.SetUserStatus(context.Background(), "user", "enabeld"); err != ErrInvalidUserStatus {
+		t.Errorf("Expected ErrInvalidUserStatus, got %v", err)
+	}
+}
+
+func TestListAccessKeysForUser(t *testing.T) {
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	accounts := ListServiceAccountsResp{Accounts: []ServiceAccountInfo{
+		{AccessKey: "key1"},
+		{AccessKey: "key2", Expiration: &expiry},
+		{AccessKey: "key3"},
+	}}
+	status := map[string]string{"key1": "on", "key2": "on", "key3": "off"}
+
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var v interface{}
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/list-service-accounts":
+			v = accounts
+		case libraryAdminURLPrefix + adminAPIPrefix + "/info-service-account":
+			key := r.URL.Query().Get("accessKey")
+			v = InfoServiceAccountResp{ParentUser: "user1", AccountStatus: status[key]}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Error(err)
+		}
+		edata, err := EncryptData(testSecretKey, data)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(edata)
+	})
+
+	keys, err := adm.ListAccessKeysForUser(context.Background(), "user1", ListAccessKeysOpts{Status: AccountEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].AccessKey != "key1" || keys[1].AccessKey != "key2" {
+		t.Fatalf("Unexpected keys %+v", keys)
+	}
+	if keys[1].Expiration == nil || !keys[1].Expiration.Equal(expiry) || keys[1].ParentUser != "user1" {
+		t.Errorf("Unexpected key info %+v", keys[1])
+	}
+}
+
+func TestEnsureUser(t *testing.T) {
+	var (
+		user      *UserInfo
+		mutations []string
+	)
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/user-info":
+			if user == nil {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioAdminNoSuchUser", Message: "The specified user does not exist."})
+				return
+			}
+			json.NewEncoder(w).Encode(user)
+			return
+		case libraryAdminURLPrefix + adminAPIPrefix + "/add-user":
+			data, err := DecryptData(testSecretKey, r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var req AddOrUpdateUserReq
+			json.Unmarshal(data, &req)
+			user = &UserInfo{PolicyName: req.Policy, Status: req.Status}
+		case libraryAdminURLPrefix + adminAPIPrefix + "/set-user-status":
+			user.Status = AccountStatus(q.Get("status"))
+		case libraryAdminURLPrefix + adminAPIPrefix + "/set-user-or-group-policy":
+			user.PolicyName = q.Get("policyName")
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		mutations = append(mutations, r.URL.Path[len(libraryAdminURLPrefix+adminAPIPrefix):])
+	})
+
+	spec := UserSpec{SecretKey: "secret123", Policies: []string{"readwrite", "diagnostics"}}
+	changed, err := adm.EnsureUser(context.Background(), "user", spec)
+	if err != nil || !changed {
+		t.Fatalf("Expected the user to be created, got %v %v", changed, err)
+	}
+
+	spec.Policies = []string{"diagnostics", "readwrite"}
+	changed, err = adm.EnsureUser(context.Background(), "user", spec)
+	if err != nil || changed {
+		t.Fatalf("Expected no change, got %v %v", changed, err)
+	}
+
+	spec.Status = AccountDisabled
+	spec.Policies = []string{"readonly"}
+	changed, err = adm.EnsureUser(context.Background(), "user", spec)
+	if err != nil || !changed {
+		t.Fatalf("Expected the user to be updated, got %v %v", changed, err)
+	}
+	if user.Status != AccountDisabled || user.PolicyName != "readonly" {
+		t.Errorf("Unexpected user %+v", user)
+	}
+
+	expected := "/add-user,/set-user-status,/set-user-or-group-policy"
+	if got := strings.Join(mutations, ","); got != expected {
+		t.Errorf("Expected mutations %s, got %s", expected, got)
+	}
+}
+
+func TestTempAccounts(t *testing.T) {
+	expiration := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	accounts := map[string]TemporaryAccountInfoResp{
+		"STSKEY1": {ParentUser: "alice", AccountStatus: "on", Policy: "readonly", Expiration: &expiration},
+		"STSKEY2": {ParentUser: "alice", AccountStatus: "on", Policy: "readonly", Expiration: &expiration},
+	}
+	revokeTypes := map[string]string{"STSKEY1": "session-1", "STSKEY2": "session-2"}
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var data []byte
+		switch r.URL.Path {
+		case libraryAdminURLPrefix + adminAPIPrefix + "/list-access-keys-bulk":
+			if q.Get("listType") != "sts-only" {
+				t.Errorf("Unexpected list type %q", q.Get("listType"))
+			}
+			resp := make(map[string]ListAccessKeysLDAPResp)
+			for key, a := range accounts {
+				if a.ParentUser == q.Get("users") {
+					r := resp[a.ParentUser]
+					r.STSKeys = append(r.STSKeys, ServiceAccountInfo{AccessKey: key})
+					resp[a.ParentUser] = r
+				}
+			}
+			data, _ = json.Marshal(resp)
+		case libraryAdminURLPrefix + adminAPIPrefix + "/revoke-tokens/builtin":
+			if r.Method != http.MethodPost {
+				t.Errorf("Unexpected method %s", r.Method)
+			}
+			if q.Get("fullRevoke") == "true" {
+				t.Errorf("Expected no full revocation")
+			}
+			for key, a := range accounts {
+				if a.ParentUser == q.Get("user") && revokeTypes[key] == q.Get("tokenRevokeType") {
+					delete(accounts, key)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case libraryAdminURLPrefix + adminAPIPrefix + "/temporary-account-info":
+			a, ok := accounts[q.Get("accessKey")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioAdminNoSuchTempAccount", Message: "The specified temporary account does not exist."})
+				return
+			}
+			data, _ = json.Marshal(a)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		enc, err := EncryptData(testSecretKey, data)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(enc)
+	})
+
+	list, err := adm.ListTempAccounts(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].AccessKey < list[j].AccessKey })
+	if len(list) != 2 || list[0].AccessKey != "STSKEY1" || list[0].ParentUser != "alice" ||
+		list[0].Policy != "readonly" || !list[0].Expiration.Equal(expiration) {
+		t.Fatalf("Unexpected temporary accounts %+v", list)
+	}
+
+	if err = adm.RevokeTempAccount(context.Background(), "UNKNOWN", "session-1"); err != nil {
+		t.Errorf("Expected revoking an unknown key to succeed, got %v", err)
+	}
+	if err = adm.RevokeTempAccount(context.Background(), "STSKEY1", ""); err == nil {
+		t.Errorf("Expected an error revoking without a token revoke type")
+	}
+	if err = adm.RevokeTempAccount(context.Background(), "STSKEY1", "session-1"); err != nil {
+		t.Fatal(err)
+	}
+	if list, err = adm.ListTempAccounts(context.Background(), "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].AccessKey != "STSKEY2" {
+		t.Errorf("Expected only STSKEY2 left, got %+v", list)
+	}
+}
+
+func TestGetUsersInfo(t *testing.T) {
+	var inFlight, maxInFlight int32
+	adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		name := r.URL.Query().Get("accessKey")
+		if name == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioAdminNoSuchUser", Message: "The specified user does not exist."})
+			return
+		}
+		json.NewEncoder(w).Encode(UserInfo{PolicyName: name + "-policy", Status: AccountEnabled})
+	})
+
+	names := []string{"u1", "u2", "missing", "u3", "u4", "u5"}
+	infos, err := adm.GetUsersInfo(context.Background(), names, GetUsersInfoOpts{Concurrency: 2})
+	var errs UsersInfoErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(errs["missing"], ErrNotFound) {
+		t.Fatalf("Expected only the missing user to fail, got %v", err)
+	}
+	if len(infos) != 5 || infos["u3"].PolicyName != "u3-policy" {
+		t.Errorf("Unexpected users info %+v", infos)
+	}
+	if m := atomic.LoadInt32(&maxInFlight); m > 2 {
+		t.Errorf("Expected at most 2 parallel lookups, got %d", m)
+	}
+
+	if infos, err = adm.GetUsersInfo(context.Background(), []string{"u1"}); err != nil || len(infos) != 1 {
+		t.Errorf("Expected the default options to work, got %v %v", infos, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	infos, err = adm.GetUsersInfo(ctx, names)
+	if !errors.As(err, &errs) || len(errs) != len(names) || len(infos) != 0 {
+		t.Fatalf("Expected every lookup to fail, got %v %v", infos, err)
+	}
+	for _, name := range names {
+		if !errors.Is(errs[name], context.Canceled) {
+			t.Errorf("Expected %s to fail with context.Canceled, got %v", name, errs[name])
+		}
+	}
+}
+
+func TestUpdateServiceAccountSecret(t *testing.T) {
+	testCases := []struct {
+		name      string
+		accessKey string
+		wantErr   error
+	}{
+		{name: "rotated", accessKey: "svc1"},
+		{name: "unknown account", accessKey: "missing", wantErr: ErrNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sent []string
+			adm := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != libraryAdminURLPrefix+adminAPIPrefix+"/update-service-account" {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+					return
+				}
+				if r.URL.Query().Get("accessKey") != "svc1" {
+					w.WriteHeader(http.StatusNotFound)
+					json.NewEncoder(w).Encode(ErrorResponse{Code: "XMinioAdminServiceAccountNotFound", Message: "The specified service account is not found"})
+					return
+				}
+				data, err := DecryptData(testSecretKey, r.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				var req UpdateServiceAccountReq
+				if err = json.Unmarshal(data, &req); err != nil {
+					t.Error(err)
+				}
+				if req.NewPolicy != nil || req.NewStatus != "" || req.NewName != "" {
+					t.Errorf("Expected only the secret key to be updated, got %+v", req)
+				}
+				sent = append(sent, req.NewSecretKey)
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			secret, err := adm.UpdateServiceAccountSecret(context.Background(), tc.accessKey)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) || secret != "" {
+					t.Fatalf("Expected %v and no secret, got %q, %v", tc.wantErr, secret, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(secret) != serviceAccountSecretKeyLen || strings.Contains(secret, "/") {
+				t.Errorf("Unexpected secret key %q", secret)
+			}
+			again, err := adm.UpdateServiceAccountSecret(context.Background(), tc.accessKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again == secret {
+				t.Errorf("Expected a fresh secret key on every rotation, got %q twice", secret)
+			}
+			if len(sent) != 2 || sent[0] != secret || sent[1] != again {
+				t.Errorf("Expected the returned secrets to be sent, sent %v", sent)
+			}
+		})
+	}
+}